@@ -36,6 +36,7 @@ func clientFlags() []cli.Flag {
 		&cli.StringFlag{Name: consts.OutDir, Usage: "out_dir"},
 		&cli.StringSliceFlag{Name: consts.ProtoSearchPath, Aliases: []string{"I"}, Usage: "Add an IDL search path for includes. (Valid only if idl is protobuf)"},
 		&cli.StringSliceFlag{Name: consts.Pass, Usage: "pass param to hz or kitex"},
+		&cli.StringFlag{Name: consts.Lang, Usage: "Specify the language of the HTTP client. (go or ts, ts is valid only if idl is protobuf)", Value: consts.Go},
 		&cli.BoolFlag{Name: consts.Verbose, Usage: "Turn on verbose mode."},
 	}
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/hu-1996/cwgo/pkg/curd/doc"
 	"github.com/hu-1996/cwgo/pkg/fallback"
 	"github.com/hu-1996/cwgo/pkg/job"
+	"github.com/hu-1996/cwgo/pkg/lint"
 	"github.com/hu-1996/cwgo/pkg/model"
 	"github.com/hu-1996/cwgo/pkg/server"
 	"github.com/urfave/cli/v2"
@@ -84,6 +85,19 @@ func Init() *cli.App {
 				}
 				return model.Model(globalArgs.ModelArgument)
 			},
+			Subcommands: []*cli.Command{
+				{
+					Name:  ModelDiffName,
+					Usage: ModelDiffUsage,
+					Flags: modelDiffFlags(),
+					Action: func(c *cli.Context) error {
+						if err := globalArgs.ModelDiffArgument.ParseCli(c); err != nil {
+							return err
+						}
+						return model.Diff(globalArgs.ModelDiffArgument)
+					},
+				},
+			},
 		},
 		{
 			Name:  DocName,
@@ -118,6 +132,28 @@ func Init() *cli.App {
 				return api_list.Api(globalArgs.ApiArgument)
 			},
 		},
+		{
+			Name:  LintName,
+			Usage: LintUsage,
+			Flags: lintFlags(),
+			Action: func(c *cli.Context) error {
+				if err := globalArgs.LintArgument.ParseCli(c); err != nil {
+					return err
+				}
+				return lint.Lint(globalArgs.LintArgument)
+			},
+		},
+		{
+			Name:  BreakingName,
+			Usage: BreakingUsage,
+			Flags: breakingFlags(),
+			Action: func(c *cli.Context) error {
+				if err := globalArgs.BreakingArgument.ParseCli(c); err != nil {
+					return err
+				}
+				return lint.Breaking(globalArgs.BreakingArgument)
+			},
+		},
 		{
 			Name:  FallbackName,
 			Usage: FallbackUsage,
@@ -193,6 +229,26 @@ Examples:
 Examples:
   # Generate DB model code 
   cwgo  model --db_type mysql --dsn "gorm:gorm@tcp(localhost:9910)/gorm?charset=utf8mb4&parseTime=True&loc=Local"
+
+  # Generate DB model code and render the templates for each table
+  cwgo  model --db_type mysql --dsn "gorm:gorm@tcp(localhost:9910)/gorm?charset=utf8mb4&parseTime=True&loc=Local" --template {{path/to/template_dir}}
+
+  # Generate DB model code with the custom query methods from annotated sql
+  cwgo  model --db_type mysql --dsn "gorm:gorm@tcp(localhost:9910)/gorm?charset=utf8mb4&parseTime=True&loc=Local" --query_dir {{path/to/query_dir}}
+
+  # Generate DB model code of each data source in the model config, the DSNs are read from the environment at runtime
+  cwgo  model --model_config {{path/to/model_config.yaml}}
+
+  # Generate thrift idl with CRUD service from the tables
+  cwgo  model --db_type mysql --dsn "gorm:gorm@tcp(localhost:9910)/gorm?charset=utf8mb4&parseTime=True&loc=Local" --to_idl thrift --service {{svc_name}}
+`
+
+	ModelDiffName  = "diff"
+	ModelDiffUsage = `generate up/down migrations from the diff between the mysql database and the sql schema
+
+Examples:
+  # Generate migrations which turn the database into the schema in ./schema
+  cwgo  model diff --db_type mysql --dsn "gorm:gorm@tcp(localhost:9910)/gorm?charset=utf8mb4&parseTime=True&loc=Local" --sql_dir ./schema --name add_user
 `
 
 	DocName  = "doc"
@@ -214,6 +270,24 @@ Examples:
 
 Examples:
 	cwgo job --job_name jobOne --job_name jobTwo --module my_job
+`
+	LintName  = "lint"
+	LintUsage = `check the naming, http annotations, routes and field bindings of the IDL
+
+Examples:
+  # Check the IDL, it exits with an error if any problem is found
+  cwgo lint --idl {{path/to/IDL_file.thrift}}
+
+  # Check the IDL of the RPC service without the http annotations
+  cwgo lint --idl {{path/to/IDL_file.thrift}} --skip_rule http_annotation
+`
+	BreakingName  = "breaking"
+	BreakingUsage = `report the wire, json and route breaking changes of the IDL against its previous version
+
+Examples:
+  # Check the IDL against the one of the main branch, it exits with an error if any breaking change is found
+  git show main:idl/hello.proto > /tmp/hello.proto
+  cwgo breaking --idl idl/hello.proto --against /tmp/hello.proto -I idl
 `
 	FallbackName  = "fallback"
 	FallbackUsage = "fallback to hz or kitex"
@@ -0,0 +1,39 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package static
+
+import (
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"github.com/urfave/cli/v2"
+)
+
+func lintFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: consts.IDLPath, Usage: "Specify the IDL file path. (.thrift or .proto)", Required: true},
+		&cli.StringSliceFlag{Name: consts.ProtoSearchPath, Aliases: []string{"I"}, Usage: "Add an IDL search path for includes."},
+		&cli.StringSliceFlag{Name: consts.Rule, Usage: "Specify the rules to check, all the rules are checked by default. (naming, http_annotation, route_conflict, binding or unused)"},
+		&cli.StringSliceFlag{Name: consts.SkipRule, Usage: "Specify the rules to skip, such as `--skip_rule http_annotation` for the RPC only IDL."},
+	}
+}
+
+func breakingFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: consts.IDLPath, Usage: "Specify the IDL file path of the new version. (.thrift or .proto)", Required: true},
+		&cli.StringFlag{Name: consts.Against, Usage: "Specify the IDL file path of the previous version to check against.", Required: true},
+		&cli.StringSliceFlag{Name: consts.ProtoSearchPath, Aliases: []string{"I"}, Usage: "Add an IDL search path for includes, it is used by both versions."},
+	}
+}
@@ -51,5 +51,36 @@ func modelFlags() []cli.Flag {
 		&cli.BoolFlag{Name: consts.TypeTag, Usage: "Specify generate field with gorm column type tag", Value: false, DefaultText: "false"},
 		&cli.BoolFlag{Name: consts.IndexTag, Usage: "Specify generate field with gorm index tag", Value: false, DefaultText: "false"},
 		&cli.StringFlag{Name: consts.SQLDir, Usage: "Specify a sql file or directory", Value: "", DefaultText: ""},
+		&cli.StringFlag{Name: consts.ModelConfig, Usage: "Specify the yaml config of the data type mapping, json tag strategy, the table/column overrides and the data sources", Value: "", DefaultText: ""},
+		&cli.StringFlag{Name: consts.QueryDir, Usage: "Specify the directory of the go interfaces or sql files with annotated sql, the custom query methods are generated for the tables", Value: "", DefaultText: ""},
+		&cli.StringFlag{Name: consts.Template, Usage: "Specify the template path, the *.tpl files in it are rendered for each table. Currently cwgo supports local and git templates, such as `--template https://github.com/***/cwgo_model_template.git`", Value: "", DefaultText: ""},
+		&cli.StringFlag{Name: consts.Branch, Usage: "Specify the git template's branch, default is main branch.", Value: "", DefaultText: ""},
+		&cli.StringFlag{Name: consts.TemplateOutDir, Usage: "Specify the output directory of the template", Value: consts.DefaultDbTplOutDir, DefaultText: consts.DefaultDbTplOutDir},
+		&cli.StringFlag{Name: consts.ToIDL, Usage: "Specify generate thrift or proto idl from the tables instead of model code. (thrift or proto)", Value: "", DefaultText: "", Action: func(context *cli.Context, s string) error {
+			switch strings.ToLower(s) {
+			case consts.Thrift, consts.Proto, consts.Protobuf:
+				return nil
+			}
+			return fmt.Errorf("unknow idl type %s (support thrift || proto for now)", s)
+		}},
+		&cli.StringFlag{Name: consts.IDLOutFile, Usage: "Specify the output idl file, default is idl/{model_pkg}.thrift or idl/{model_pkg}.proto", Value: "", DefaultText: ""},
+		&cli.StringFlag{Name: consts.Service, Usage: "Specify the service name of the idl, the CRUD service of the tables is generated if specified", Value: "", DefaultText: ""},
+	}
+}
+
+func modelDiffFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: consts.DSN, Usage: "Specify the database source name. (https://gorm.io/docs/connecting_to_the_database.html)", Value: "", DefaultText: "", Required: true},
+		&cli.StringFlag{Name: consts.DBType, Usage: "Specify database type, the sql schema is parsed as mysql ddl. (mysql only for now)", Value: string(consts.MySQL), DefaultText: string(consts.MySQL), Action: func(context *cli.Context, s string) error {
+			if consts.DataBaseType(strings.ToLower(s)) != consts.MySQL {
+				return fmt.Errorf("db type %s is not supported by model diff (support mysql for now)", s)
+			}
+			return nil
+		}},
+		&cli.StringFlag{Name: consts.SQLDir, Usage: "Specify the sql file or directory of the target schema", Value: "", DefaultText: "", Required: true},
+		&cli.StringSliceFlag{Name: consts.Tables, Usage: "Specify databases tables"},
+		&cli.StringSliceFlag{Name: consts.ExcludeTables, Usage: "Specify exclude tables"},
+		&cli.StringFlag{Name: consts.OutDir, Usage: "Specify the output directory of the migrations", Value: consts.DefaultMigrationDir, DefaultText: consts.DefaultMigrationDir},
+		&cli.StringFlag{Name: consts.Name, Usage: "Specify the name of the migration, the files are named {version}_{name}.up.sql and {version}_{name}.down.sql", Value: "migration", DefaultText: "migration"},
 	}
 }
@@ -35,7 +35,7 @@ func serverFlags() []cli.Flag {
 		&cli.StringFlag{Name: consts.Registry, Usage: "Specify the registry, default is None."},
 		&cli.StringFlag{Name: consts.OutDir, Usage: "out_dir"},
 		&cli.StringSliceFlag{Name: consts.ProtoSearchPath, Aliases: []string{"I"}, Usage: "Add an IDL search path for includes."},
-		&cli.StringSliceFlag{Name: consts.Pass, Usage: "Pass param to hz or Kitex."},
+		&cli.StringSliceFlag{Name: consts.Pass, Usage: "Pass param to hz or Kitex. The hz param '-wkt_go_type' maps the protobuf well-known types to the go types in the models, the fields of them are dropped from the protobuf wire format, so they are rejected in the requests."},
 		&cli.BoolFlag{Name: consts.Verbose, Usage: "Turn on verbose mode."},
 		&cli.BoolFlag{Name: consts.HexTag, Usage: "Add HTTP listen for Kitex.", Destination: &globalArgs.Hex},
 	}
@@ -46,21 +46,27 @@ type Argument struct {
 	*ServerArgument
 	*ClientArgument
 	*ModelArgument
+	*ModelDiffArgument
 	*DocArgument
 	*JobArgument
 	*ApiArgument
 	*FallbackArgument
+	*LintArgument
+	*BreakingArgument
 }
 
 func NewArgument() *Argument {
 	return &Argument{
-		ServerArgument:   NewServerArgument(),
-		ClientArgument:   NewClientArgument(),
-		ModelArgument:    NewModelArgument(),
-		DocArgument:      NewDocArgument(),
-		JobArgument:      NewJobArgument(),
-		ApiArgument:      NewApiArgument(),
-		FallbackArgument: NewFallbackArgument(),
+		ServerArgument:    NewServerArgument(),
+		ClientArgument:    NewClientArgument(),
+		ModelArgument:     NewModelArgument(),
+		ModelDiffArgument: NewModelDiffArgument(),
+		DocArgument:       NewDocArgument(),
+		JobArgument:       NewJobArgument(),
+		ApiArgument:       NewApiArgument(),
+		FallbackArgument:  NewFallbackArgument(),
+		LintArgument:      NewLintArgument(),
+		BreakingArgument:  NewBreakingArgument(),
 	}
 }
 
@@ -125,6 +131,12 @@ type HzArgument struct {
 	CustomizeLayoutData string
 	CustomizePackage    string
 	ModelBackend        string
+
+	OpenAPI         bool   // generate the openapi document of the protobuf idl
+	SwaggerUI       bool   // register the swagger ui of the openapi document
+	WellKnownGoType bool   // map the protobuf well-known types to the idiomatic go types in the models, the fields are dropped from the protobuf wire format and rejected in the requests
+	ClientLang      string // language of the client for "client" command, "go" or "ts"
+	Mock            bool   // generate the mock server which responds the examples of the responses
 }
 
 func NewHzArgument() *HzArgument {
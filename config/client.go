@@ -30,6 +30,7 @@ type ClientArgument struct {
 	SliceParam *SliceParam
 
 	Verbose  bool
+	Lang     string // language of the HTTP client, "go" or "ts"
 	Template string
 	Branch   string
 	Cwd      string
@@ -49,6 +50,7 @@ func (c *ClientArgument) ParseCli(ctx *cli.Context) error {
 	c.Type = strings.ToUpper(ctx.String(consts.ServiceType))
 	c.Registry = strings.ToUpper(ctx.String(consts.Registry))
 	c.Verbose = ctx.Bool(consts.Verbose)
+	c.Lang = strings.ToLower(ctx.String(consts.Lang))
 	c.OutDir = ctx.String(consts.OutDir)
 	c.SliceParam.ProtoSearchPath = ctx.StringSlice(consts.ProtoSearchPath)
 	c.SliceParam.Pass = ctx.StringSlice(consts.Pass)
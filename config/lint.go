@@ -0,0 +1,58 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"github.com/urfave/cli/v2"
+)
+
+type LintArgument struct {
+	IdlPath         string
+	ProtoSearchPath []string
+	Rules           []string // the rules to check, all the rules are checked if it is empty
+	SkipRules       []string
+}
+
+func NewLintArgument() *LintArgument {
+	return &LintArgument{}
+}
+
+func (c *LintArgument) ParseCli(ctx *cli.Context) error {
+	c.IdlPath = ctx.String(consts.IDLPath)
+	c.ProtoSearchPath = ctx.StringSlice(consts.ProtoSearchPath)
+	c.Rules = ctx.StringSlice(consts.Rule)
+	c.SkipRules = ctx.StringSlice(consts.SkipRule)
+	return nil
+}
+
+type BreakingArgument struct {
+	IdlPath         string
+	Against         string // the idl of the previous version
+	ProtoSearchPath []string
+}
+
+func NewBreakingArgument() *BreakingArgument {
+	return &BreakingArgument{}
+}
+
+func (c *BreakingArgument) ParseCli(ctx *cli.Context) error {
+	c.IdlPath = ctx.String(consts.IDLPath)
+	c.Against = ctx.String(consts.Against)
+	c.ProtoSearchPath = ctx.StringSlice(consts.ProtoSearchPath)
+	return nil
+}
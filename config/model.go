@@ -38,12 +38,21 @@ type ModelArgument struct {
 	FieldWithIndexTag bool
 	FieldWithTypeTag  bool
 	SQLDir            string
+	Template          string
+	Branch            string
+	TemplateOutDir    string
+	ToIDL             string
+	IDLOutFile        string
+	Service           string
+	ModelConfig       string
+	QueryDir          string
 }
 
 func NewModelArgument() *ModelArgument {
 	return &ModelArgument{
-		OutPath: consts.DefaultDbOutDir,
-		OutFile: consts.DefaultDbOutFile,
+		OutPath:        consts.DefaultDbOutDir,
+		OutFile:        consts.DefaultDbOutFile,
+		TemplateOutDir: consts.DefaultDbTplOutDir,
 	}
 }
 
@@ -62,5 +71,40 @@ func (c *ModelArgument) ParseCli(ctx *cli.Context) error {
 	c.FieldWithIndexTag = ctx.Bool(consts.IndexTag)
 	c.FieldWithTypeTag = ctx.Bool(consts.TypeTag)
 	c.SQLDir = ctx.String(consts.SQLDir)
+	c.Template = ctx.String(consts.Template)
+	c.Branch = ctx.String(consts.Branch)
+	c.TemplateOutDir = ctx.String(consts.TemplateOutDir)
+	c.ToIDL = strings.ToLower(ctx.String(consts.ToIDL))
+	c.IDLOutFile = ctx.String(consts.IDLOutFile)
+	c.Service = ctx.String(consts.Service)
+	c.ModelConfig = ctx.String(consts.ModelConfig)
+	c.QueryDir = ctx.String(consts.QueryDir)
+	return nil
+}
+
+type ModelDiffArgument struct {
+	DSN           string
+	Type          string
+	SQLDir        string
+	Tables        []string
+	ExcludeTables []string
+	OutDir        string
+	Name          string
+}
+
+func NewModelDiffArgument() *ModelDiffArgument {
+	return &ModelDiffArgument{
+		OutDir: consts.DefaultMigrationDir,
+	}
+}
+
+func (c *ModelDiffArgument) ParseCli(ctx *cli.Context) error {
+	c.DSN = ctx.String(consts.DSN)
+	c.Type = strings.ToLower(ctx.String(consts.DBType))
+	c.SQLDir = ctx.String(consts.SQLDir)
+	c.Tables = ctx.StringSlice(consts.Tables)
+	c.ExcludeTables = ctx.StringSlice(consts.ExcludeTables)
+	c.OutDir = ctx.String(consts.OutDir)
+	c.Name = ctx.String(consts.Name)
 	return nil
 }
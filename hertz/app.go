@@ -7,12 +7,12 @@ import (
 	"strings"
 
 	"github.com/cloudwego/hertz/cmd/hz/meta"
-	"github.com/cloudwego/hertz/cmd/hz/thrift"
 	"github.com/cloudwego/hertz/cmd/hz/util"
 	"github.com/cloudwego/hertz/cmd/hz/util/logs"
 	"github.com/hu-1996/cwgo/config"
 	"github.com/hu-1996/cwgo/hertz/generator"
 	"github.com/hu-1996/cwgo/hertz/protobuf"
+	"github.com/hu-1996/cwgo/hertz/thrift"
 )
 
 func GenerateLayout(args *config.HzArgument) error {
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	// BackendPluginPrefix is the prefix of the backend plugin binary, eg: cwgo-backend-easyjson
+	BackendPluginPrefix = "cwgo-backend-"
+
+	backendRootTpl    = "file"
+	backendTplSuffix  = ".tpl"
+	defaultFileSuffix = ".go"
+)
+
+// BackendPluginRequest is written to the stdin of the backend plugin. The plugin is template-only,
+// it is run once before generating and never receives the models, the models are rendered by cwgo
+// with the returned templates, which access the model by "." and "ROOT" like the golang backend
+type BackendPluginRequest struct {
+	Options []string `json:"options"`
+}
+
+// BackendPluginResponse is read from the stdout of the backend plugin, Templates must contain
+// the root template "file" which renders the whole model file
+type BackendPluginResponse struct {
+	Templates map[string]string `json:"templates"`
+	// Suffix is the suffix of the model file, default is ".go"
+	Suffix string `json:"suffix,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// TemplateBackend renders the models with the third-party templates, which are loaded from
+// a template directory or a plugin binary
+type TemplateBackend struct {
+	name    string
+	load    func(options []string) (*BackendPluginResponse, error)
+	list    map[string]string
+	suffix  string
+	options []string
+	funcs   template.FuncMap
+	tpl     *template.Template
+}
+
+func newTemplateBackend(name string, load func(options []string) (*BackendPluginResponse, error)) *TemplateBackend {
+	tb := &TemplateBackend{
+		name:   name,
+		load:   load,
+		suffix: defaultFileSuffix,
+		funcs:  template.FuncMap{},
+	}
+	for k, f := range funcMap {
+		tb.funcs[k] = f
+	}
+	tb.funcs["Options"] = func() []string { return tb.options }
+	tb.funcs["HasOption"] = func(opt string) bool {
+		for _, o := range tb.options {
+			if o == opt {
+				return true
+			}
+		}
+		return false
+	}
+	return tb
+}
+
+// newDirBackend loads the *.tpl files in the directory, the template named "file" is the root
+// template, and "file.<ext>.tpl" specifies the suffix of the model file, eg: file.ts.tpl
+func newDirBackend(dir string) *TemplateBackend {
+	return newTemplateBackend(dir, func(options []string) (*BackendPluginResponse, error) {
+		files, err := filepath.Glob(filepath.Join(dir, "*"+backendTplSuffix))
+		if err != nil {
+			return nil, err
+		}
+		resp := &BackendPluginResponse{Templates: make(map[string]string, len(files))}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("read template '%s' failed, err: %v", file, err)
+			}
+			name := strings.TrimSuffix(filepath.Base(file), backendTplSuffix)
+			if strings.HasPrefix(name, backendRootTpl+".") {
+				resp.Suffix = strings.TrimPrefix(name, backendRootTpl)
+				name = backendRootTpl
+			}
+			resp.Templates[name] = string(content)
+		}
+		return resp, nil
+	})
+}
+
+// newPluginBackend runs the plugin binary with the BackendPluginRequest in stdin and reads the
+// templates from the BackendPluginResponse in stdout, the models are not sent to the plugin
+func newPluginBackend(path string) *TemplateBackend {
+	return newTemplateBackend(path, func(options []string) (*BackendPluginResponse, error) {
+		req, err := json.Marshal(&BackendPluginRequest{Options: options})
+		if err != nil {
+			return nil, err
+		}
+		var stdout bytes.Buffer
+		cmd := exec.Command(path)
+		cmd.Stdin = bytes.NewReader(req)
+		cmd.Stdout = &stdout
+		cmd.Stderr = os.Stderr
+		if err = cmd.Run(); err != nil {
+			return nil, fmt.Errorf("run backend plugin '%s' failed, err: %v", path, err)
+		}
+		resp := &BackendPluginResponse{}
+		if err = json.Unmarshal(stdout.Bytes(), resp); err != nil {
+			return nil, fmt.Errorf("unmarshal the response of backend plugin '%s' failed, err: %v", path, err)
+		}
+		if resp.Error != "" {
+			return nil, fmt.Errorf("backend plugin '%s' failed, err: %s", path, resp.Error)
+		}
+		return resp, nil
+	})
+}
+
+func (tb *TemplateBackend) Template() (*template.Template, error) {
+	if tb.tpl != nil {
+		return tb.tpl, nil
+	}
+	resp, err := tb.load(tb.options)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := resp.Templates[backendRootTpl]
+	if !ok {
+		return nil, fmt.Errorf("root template '%s' of backend '%s' not found", backendRootTpl, tb.name)
+	}
+	if resp.Suffix != "" {
+		tb.suffix = resp.Suffix
+	}
+	tb.list = resp.Templates
+
+	tpl, err := template.New(backendRootTpl).Funcs(tb.funcs).Parse(root)
+	if err != nil {
+		return nil, fmt.Errorf("parse template '%s' failed, err: %v", backendRootTpl, err)
+	}
+	names := make([]string, 0, len(resp.Templates))
+	for name := range resp.Templates {
+		if name != backendRootTpl {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err = tpl.New(name).Parse(resp.Templates[name]); err != nil {
+			return nil, fmt.Errorf("parse template '%s' failed, err: %v", name, err)
+		}
+	}
+	tb.tpl = tpl
+	return tpl, nil
+}
+
+func (tb *TemplateBackend) List() map[string]string {
+	return tb.list
+}
+
+// SetOption records the option, which is passed to the plugin and accessed by "Options" or "HasOption" in templates
+func (tb *TemplateBackend) SetOption(opt string) error {
+	tb.options = append(tb.options, opt)
+	return nil
+}
+
+func (tb *TemplateBackend) GetOptions() []string {
+	return tb.options
+}
+
+func (tb *TemplateBackend) Funcs(name string, fn interface{}) error {
+	if _, ok := tb.funcs[name]; ok {
+		return fmt.Errorf("duplicate function: %s has been registered", name)
+	}
+	tb.funcs[name] = fn
+	return nil
+}
+
+// FileSuffix returns the suffix of the model file
+func (tb *TemplateBackend) FileSuffix() string {
+	return tb.suffix
+}
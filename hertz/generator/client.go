@@ -17,11 +17,21 @@
 package generator
 
 import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudwego/hertz/cmd/hz/generator/model"
 	"github.com/cloudwego/hertz/cmd/hz/util"
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
 )
 
 type ClientMethod struct {
@@ -32,6 +42,42 @@ type ClientMethod struct {
 	HeaderParamsCode string
 	FormValueCode    string
 	FormFileCode     string
+	Timeout          time.Duration // timeout of the method from the idl annotation
+	RetryTimes       int           // max retry times of the method from the idl annotation
+	Params           []ClientParam // parameters out of the body, they are bound by the clients not in go
+}
+
+// ParseTimeout parses the timeout annotation of the method, it is a duration such as "500ms"
+func ParseTimeout(val, method string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(val)
+	if err != nil || timeout < time.Millisecond {
+		return 0, fmt.Errorf("invalid timeout '%s' of method '%s', it should be a duration such as \"500ms\"", val, method)
+	}
+	return timeout, nil
+}
+
+// ParseRetryTimes parses the retry annotation of the method, it is the max retry times
+func ParseRetryTimes(val, method string) (int, error) {
+	retry, err := strconv.Atoi(val)
+	if err != nil || retry < 0 {
+		return 0, fmt.Errorf("invalid retry '%s' of method '%s', it should be the max retry times", val, method)
+	}
+	return retry, nil
+}
+
+const (
+	ParamInQuery  = "query"
+	ParamInPath   = "path"
+	ParamInHeader = "header"
+	ParamInForm   = "form"
+	ParamInFile   = "file"
+)
+
+// ClientParam is a field of the request which is bound to the query, path, header or form
+type ClientParam struct {
+	In    string // where the parameter is bound, eg: "query"
+	Name  string // name of the parameter in the http request
+	Field string // json name of the field in the request
 }
 
 type ClientConfig struct {
@@ -46,19 +92,19 @@ type ClientFile struct {
 	BaseDomain    string
 	Imports       map[string]*model.Model
 	ClientMethods []*ClientMethod
+	TSTypes       []*TSType // interfaces and enums of the typescript client
 }
 
 func (pkgGen *HttpPackageGenerator) genClient(pkg *HttpPackage, clientDir string) error {
+	if pkgGen.ClientLang == ClientLangTS {
+		return pkgGen.genTSClient(pkg, clientDir)
+	}
 	for _, s := range pkg.Services {
 		cliDir := util.SubDir(clientDir, util.ToSnakeCase(s.Name))
 		if len(pkgGen.ForceClientDir) != 0 {
 			cliDir = pkgGen.ForceClientDir
 		}
 		hertzClientPath := filepath.Join(cliDir, hertzClientTplName)
-		isExist, err := util.PathExist(hertzClientPath)
-		if err != nil {
-			return err
-		}
 		baseDomain := s.BaseDomain
 		if len(pkgGen.BaseDomain) != 0 {
 			baseDomain = pkgGen.BaseDomain
@@ -71,23 +117,9 @@ func (pkgGen *HttpPackageGenerator) genClient(pkg *HttpPackage, clientDir string
 			BaseDomain:    baseDomain,
 			Config:        ClientConfig{QueryEnumAsInt: pkgGen.QueryEnumAsInt},
 		}
-		if !isExist {
-			err := pkgGen.TemplateGenerator.Generate(client, hertzClientTplName, hertzClientPath, false)
-			if err != nil {
-				return err
-			}
-		}
-		client.Imports = make(map[string]*model.Model, len(client.ClientMethods))
-		for _, m := range client.ClientMethods {
-			// Iterate over the request and return parameters of the method to get import path.
-			for key, mm := range m.Models {
-				if v, ok := client.Imports[mm.PackageName]; ok && v.Package != mm.Package {
-					client.Imports[key] = mm
-					continue
-				}
-
-				client.Imports[mm.PackageName] = mm
-			}
+		// the underlying client is regenerated to get the new options
+		if err := pkgGen.updateClientFile(client, hertzClientTplName, hertzClientPath); err != nil {
+			return err
 		}
 		if len(pkgGen.UseDir) != 0 {
 			oldModelDir := filepath.Clean(filepath.Join(pkgGen.ProjPackage, pkgGen.ModelDir))
@@ -98,10 +130,217 @@ func (pkgGen *HttpPackageGenerator) genClient(pkg *HttpPackage, clientDir string
 				}
 			}
 		}
-		err = pkgGen.TemplateGenerator.Generate(client, idlClientName, client.FilePath, false)
-		if err != nil {
+		// the server streaming methods are generated in their own file, because they return the streams
+		var streams []*ClientMethod
+		unary := make([]*ClientMethod, 0, len(client.ClientMethods))
+		for _, m := range client.ClientMethods {
+			if m.Stream != "" {
+				streams = append(streams, m)
+			} else {
+				unary = append(unary, m)
+			}
+		}
+		client.ClientMethods = unary
+		client.Imports = clientImports(client.ClientMethods)
+		if err := pkgGen.updateClientFile(client, idlClientName, client.FilePath); err != nil {
 			return err
 		}
+		if len(streams) != 0 {
+			streamClient := client
+			streamClient.ClientMethods = streams
+			streamClient.Imports = clientImports(streams)
+			streamPath := filepath.Join(cliDir, util.ToSnakeCase(s.Name)+"_stream.go")
+			if err := pkgGen.updateClientFile(streamClient, idlClientStreamName, streamPath); err != nil {
+				return err
+			}
+		}
+
+		// the timeout and retry annotations of the methods are registered as the method policies
+		if hasClientPolicy(client.ClientMethods) {
+			policyPath := filepath.Join(cliDir, util.ToSnakeCase(s.Name)+"_policy.go")
+			if err := pkgGen.updateClientFile(client, idlClientPolicyName, policyPath); err != nil {
+				return err
+			}
+		}
+
+		// the extension of the client is generated once, the custom options can be put in it
+		if info := pkgGen.tplsInfo[idlClientExtName]; info != nil && !info.Disable {
+			extPath := filepath.Join(cliDir, util.ToSnakeCase(s.Name)+"_ext.go")
+			isExist, err := util.PathExist(extPath)
+			if err != nil {
+				return err
+			}
+			if !isExist {
+				if err = pkgGen.TemplateGenerator.Generate(client, idlClientExtName, extPath, false); err != nil {
+					return err
+				}
+			}
+		}
 	}
 	return nil
 }
+
+// clientImports returns the model packages of the request and return parameters of the methods
+func clientImports(methods []*ClientMethod) map[string]*model.Model {
+	imports := make(map[string]*model.Model, len(methods))
+	for _, m := range methods {
+		for key, mm := range m.Models {
+			if v, ok := imports[mm.PackageName]; ok && v.Package != mm.Package {
+				imports[key] = mm
+				continue
+			}
+
+			imports[mm.PackageName] = mm
+		}
+	}
+	return imports
+}
+
+func hasClientPolicy(methods []*ClientMethod) bool {
+	for _, m := range methods {
+		if m.Timeout > 0 || m.RetryTimes > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// updateClientFile updates the existing client file of the client command by the update behavior of the template,
+// the client is regenerated by default, and the declarations not in the file are merged into it with "append".
+// The client.go generated by the server command is kept once generated, see updateClient
+func (pkgGen *HttpPackageGenerator) updateClientFile(client interface{}, clientTpl, filePath string) error {
+	tplInfo := pkgGen.tplsInfo[clientTpl]
+	if tplInfo != nil && tplInfo.Disable {
+		return nil
+	}
+	isExist, err := util.PathExist(filePath)
+	if err != nil {
+		return err
+	}
+	if !isExist || tplInfo == nil {
+		return pkgGen.TemplateGenerator.Generate(client, clientTpl, filePath, false)
+	}
+
+	switch tplInfo.UpdateBehavior.Type {
+	case Skip:
+		logs.Infof("do not update file '%s', because the update behavior is 'Unchanged'", filePath)
+		return nil
+	case Append:
+		tpl := pkgGen.tpls[clientTpl]
+		if tpl == nil {
+			return fmt.Errorf("tpl %s not found", clientTpl)
+		}
+		generated := bytes.NewBuffer(nil)
+		if err = tpl.Execute(generated, client); err != nil {
+			return fmt.Errorf("render template '%s' failed, err: %v", clientTpl, err)
+		}
+		file, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		file, err = mergeGoContent(file, generated.Bytes())
+		if err != nil {
+			return fmt.Errorf("merge client file '%s' failed, err: %v", filePath, err)
+		}
+		pkgGen.files = append(pkgGen.files, File{filePath, string(file), false, clientTpl})
+		return nil
+	default:
+		logs.Infof("re-generate file '%s', because the update behavior is 'Regenerate'", filePath)
+		return pkgGen.TemplateGenerator.Generate(client, clientTpl, filePath, false)
+	}
+}
+
+// mergeGoContent appends the declarations and imports in the generated content which are not in the file
+func mergeGoContent(file, generated []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", file, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	genFset := token.NewFileSet()
+	gf, err := parser.ParseFile(genFset, "", generated, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]bool)
+	for _, decl := range f.Decls {
+		for _, key := range declKeys(decl) {
+			exists[key] = true
+		}
+	}
+	imports := make(map[string]bool, len(f.Imports))
+	for _, imp := range f.Imports {
+		imports[imp.Path.Value] = true
+	}
+
+	buf := bytes.NewBuffer(file)
+	for _, decl := range gf.Decls {
+		keys := declKeys(decl)
+		if len(keys) == 0 || exists[keys[0]] {
+			continue
+		}
+		start := decl.Pos()
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		}
+		buf.WriteString("\n")
+		buf.Write(generated[genFset.Position(start).Offset:genFset.Position(decl.End()).Offset])
+		buf.WriteString("\n")
+	}
+
+	content := buf.Bytes()
+	for _, imp := range gf.Imports {
+		if imports[imp.Path.Value] {
+			continue
+		}
+		alias := ""
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if content, err = util.AddImportForContent(content, alias, path); err != nil {
+			return nil, err
+		}
+	}
+	return format.Source(content)
+}
+
+// declKeys returns the names of the declaration, the method is named as <receiver>.<method>
+func declKeys(decl ast.Decl) (keys []string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return []string{d.Name.Name}
+		}
+		recv := d.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		return []string{identName(recv) + "." + d.Name.Name}
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT {
+			return nil
+		}
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				keys = append(keys, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					if name.Name != "_" {
+						keys = append(keys, name.Name)
+					}
+				}
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestMergeGoContent(t *testing.T) {
+	file := `package user
+
+import "context"
+
+type UserClient struct{}
+
+// GetUser is customized
+func (s *UserClient) GetUser(ctx context.Context) error {
+	return nil
+}
+`
+	generated := `package user
+
+import (
+	"context"
+	"net/http"
+)
+
+type UserClient struct{}
+
+// GetUser gets the user
+func (s *UserClient) GetUser(ctx context.Context) error {
+	return nil
+}
+
+// DeleteUser deletes the user
+func (s *UserClient) DeleteUser(ctx context.Context) error {
+	_ = http.MethodDelete
+	return nil
+}
+`
+	out, err := mergeGoContent([]byte(file), []byte(generated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	if strings.Count(got, "type UserClient struct") != 1 || strings.Count(got, "GetUser(") != 1 {
+		t.Errorf("want no duplicate declarations in:\n%s", got)
+	}
+	for _, want := range []string{"// GetUser is customized", "// DeleteUser deletes the user", `"net/http"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestUpdateClientFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "user.go")
+	existing := "package user\n\n// UserClient is customized\ntype UserClient struct{}\n"
+	if err := ioutil.WriteFile(file, []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tpl := template.Must(template.New("client").Parse("package user\n\ntype UserClient struct{}\n\nfunc (s *UserClient) {{.ServiceName}}() {}\n"))
+	pkgGen := &HttpPackageGenerator{TemplateGenerator: TemplateGenerator{
+		tpls: map[string]*template.Template{clientTplName: tpl, idlClientName: tpl},
+		tplsInfo: map[string]*Template{
+			clientTplName: {UpdateBehavior: UpdateBehavior{Type: Append}},
+			idlClientName: {UpdateBehavior: UpdateBehavior{Type: Append}},
+		},
+	}}
+	client := Client{ServiceName: "GetUser"}
+
+	// the client of the server is generated once and never updated
+	if err := pkgGen.updateClient(client, clientTplName, file, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgGen.files) != 0 {
+		t.Fatalf("want the client of the server untouched, got %d files", len(pkgGen.files))
+	}
+
+	// the client of the client command is merged
+	if err := pkgGen.updateClientFile(client, idlClientName, file); err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgGen.files) != 1 {
+		t.Fatalf("want 1 file, got %d", len(pkgGen.files))
+	}
+	got := pkgGen.files[0].Content
+	if !strings.Contains(got, "// UserClient is customized") || !strings.Contains(got, "func (s *UserClient) GetUser()") {
+		t.Errorf("want the client merged in:\n%s", got)
+	}
+}
+
+func TestClientPolicyTemplate(t *testing.T) {
+	var body string
+	for _, l := range defaultPkgConfig.Layouts {
+		if strings.HasSuffix(l.Path, idlClientPolicyName) {
+			body = l.Body
+		}
+	}
+	tpl := template.Must(template.New(idlClientPolicyName).Parse(body))
+
+	methods := []*ClientMethod{
+		{HttpMethod: &HttpMethod{HTTPMethod: "GET", Path: "/user/:id"}, Timeout: 500 * time.Millisecond, RetryTimes: 2},
+		{HttpMethod: &HttpMethod{HTTPMethod: "POST", Path: "/user"}},
+		{HttpMethod: &HttpMethod{HTTPMethod: "DELETE", Path: "/user/:id"}, RetryTimes: 1},
+	}
+	if !hasClientPolicy(methods) || hasClientPolicy(methods[1:2]) {
+		t.Fatal("want the policy of the methods with the timeout or retry")
+	}
+	for _, ms := range [][]*ClientMethod{methods, methods[1:]} {
+		buf := bytes.NewBuffer(nil)
+		if err := tpl.Execute(buf, ClientFile{PackageName: "user", ClientMethods: ms}); err != nil {
+			t.Fatal(err)
+		}
+		out, err := format.Source(buf.Bytes())
+		if err != nil {
+			t.Fatalf("format policy failed, err: %v\n%s", err, buf.String())
+		}
+		got := string(out)
+		if strings.Contains(got, `"/user"`) || !strings.Contains(got, `MaxRetryTimes: 1`) {
+			t.Errorf("unexpected policies in:\n%s", got)
+		}
+		if hasTime := strings.Contains(got, `import "time"`); hasTime != (len(ms) == 3) {
+			t.Errorf("unexpected time import in:\n%s", got)
+		}
+	}
+}
+
+func TestTSClientTemplate(t *testing.T) {
+	tpls := make(map[string]*template.Template)
+	for _, l := range defaultPkgConfig.Layouts {
+		if name := filepath.Base(l.Path); name == idlClientTSName || name == idlClientTSTypesName {
+			tpls[name] = template.Must(template.New(name).Funcs(funcMap).Parse(l.Body))
+		}
+	}
+	client := ClientFile{
+		FilePath:    "biz/http/user_service/user_service.ts",
+		ServiceName: "UserService",
+		BaseDomain:  "http://127.0.0.1:8888",
+		ClientMethods: []*ClientMethod{
+			{
+				HttpMethod:     &HttpMethod{Name: "GetUser", HTTPMethod: "GET", Path: "/user/:id", RequestTypeRawName: "GetUserReq", ReturnTypeRawName: "User"},
+				BodyParamsCode: "setBodyParam(req).",
+				Params: []ClientParam{
+					{In: ParamInPath, Name: "id", Field: "id"},
+					{In: ParamInHeader, Name: "X-Token", Field: "token"},
+					{In: ParamInQuery, Name: "fields", Field: "fields"},
+				},
+			},
+			{
+				HttpMethod: &HttpMethod{Name: "UploadAvatar", HTTPMethod: "POST", Path: "/user/:id/avatar", RequestTypeRawName: "UploadAvatarReq", ReturnTypeRawName: "User"},
+				Params: []ClientParam{
+					{In: ParamInPath, Name: "id", Field: "id"},
+					{In: ParamInFile, Name: "avatar", Field: "avatar"},
+				},
+			},
+		},
+		TSTypes: []*TSType{
+			{Name: "User", Comment: "User is the user", Fields: []TSField{
+				{Name: "id", Type: "number", Required: true},
+				{Name: `"display-name"`, Type: "string", Comment: "name to display"},
+				{Name: "status", Type: "Status"},
+			}},
+			{Name: "Status", Values: []TSEnumValue{{Name: "ACTIVE", Value: 0}, {Name: "BANNED", Value: 1}}},
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := tpls[idlClientTSTypesName].Execute(buf, client); err != nil {
+		t.Fatal(err)
+	}
+	types := buf.String()
+	for _, want := range []string{
+		"// User is the user\nexport interface User {\n  id: number;\n  // name to display\n  \"display-name\"?: string;\n  status?: Status;\n}\n",
+		"export enum Status {\n  ACTIVE = 0,\n  BANNED = 1,\n}\n",
+	} {
+		if !strings.Contains(types, want) {
+			t.Errorf("want %q in:\n%s", want, types)
+		}
+	}
+
+	buf.Reset()
+	if err := tpls[idlClientTSName].Execute(buf, client); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`import * as types from "./user_service_types";`,
+		`constructor(baseUrl: string = "http://127.0.0.1:8888", options: ClientOptions = {})`,
+		`async getUser(req: types.GetUserReq, init: RequestInit = {}): Promise<types.User>`,
+		`"X-Token": req["token"],`,
+		`"fields": req["fields"],`,
+		`"avatar": req["avatar"],`,
+		`this.baseUrl = baseUrl.replace(/\/+$/, "");`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "body: req,") != 1 {
+		t.Errorf("want the json body of GetUser only in:\n%s", got)
+	}
+}
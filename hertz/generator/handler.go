@@ -44,11 +44,21 @@ type HttpMethod struct {
 	RefPackage         string // handler import dir
 	RefPackageAlias    string // handler import alias
 	ModelPackage       map[string]string
-	GenHandler         bool // Whether to generate one handler, when an idl interface corresponds to multiple http method
+	GenHandler         bool     // Whether to generate one handler, when an idl interface corresponds to multiple http method
+	Stream             string   // the streaming of the method, it is empty for the unary method
+	ExampleResponse    string   // json example of the response which is responded by the mock server
+	Middlewares        []string // names of the middlewares from the annotations of the method and its service
+	ServiceMiddlewares []string // names of the middlewares of its service, they are registered on the groups of the routes
 	// Annotations     map[string]string
 	Models map[string]*model.Model
 }
 
+// the streaming methods are served by server-sent events or websocket
+const (
+	StreamSSE       = "sse"       // server streaming
+	StreamWebSocket = "websocket" // client streaming and bidirectional streaming
+)
+
 type Handler struct {
 	FilePath    string
 	PackageName string
@@ -192,85 +202,160 @@ func (pkgGen *HttpPackageGenerator) updateHandler(handler interface{}, handlerTp
 	if err != nil {
 		return err
 	}
-	if !isExist {
+	h := handler.(Handler)
+	methods := h.Methods
+	streams := h.splitStreamMethods()
+	if !isExist && len(streams) == 0 {
 		return pkgGen.TemplateGenerator.Generate(handler, handlerTpl, filePath, noRepeat)
 	}
-	if pkgGen.HandlerByMethod { // method by handler, do not need to insert new content
-		return nil
+
+	var file []byte
+	if isExist {
+		if file, err = ioutil.ReadFile(filePath); err != nil {
+			return err
+		}
+		if file, err = pkgGen.insertHandlers(h, handlerTpl, filePath, file); err != nil {
+			return err
+		}
+	} else if len(h.Methods) != 0 {
+		buf := bytes.NewBuffer(nil)
+		if err = pkgGen.tpls[handlerTpl].Execute(buf, h); err != nil {
+			return fmt.Errorf("render template '%s' failed, err: %v", handlerTpl, err)
+		}
+		file = buf.Bytes()
+	}
+
+	// the streaming handlers are rendered by their own template, and merged into the handler file
+	if len(streams) != 0 && !pkgGen.tplsInfo[streamHandlerTplName].Disable {
+		h.Methods = streams
+		if file, err = pkgGen.mergeStreamHandlers(h, file); err != nil {
+			return fmt.Errorf("merge streaming handlers into '%s' failed, err: %v", filePath, err)
+		}
+	}
+
+	if isExist {
+		// update the existing handlers by the idl, the orphaned handlers can only be found in the service file
+		file, err = reconcileHandlers(filePath, file, methods, !pkgGen.HandlerByMethod)
+		if err != nil {
+			return err
+		}
 	}
 
-	file, err := ioutil.ReadFile(filePath)
+	pkgGen.files = append(pkgGen.files, File{filePath, string(file), noRepeat, ""})
+
+	return nil
+}
+
+// insertHandlers inserts the imports and the handlers which are not in the existing handler file
+func (pkgGen *HttpPackageGenerator) insertHandlers(h Handler, handlerTpl, filePath string, file []byte) ([]byte, error) {
+	imports, err := existingImports(file)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("parse handler file '%s' failed, err: %v", filePath, err)
 	}
 
 	// insert new model imports
-	for alias, model := range handler.(Handler).Imports {
-		if bytes.Contains(file, []byte(model.Package)) {
+	for alias, model := range h.Imports {
+		if imports[model.Package] {
 			continue
 		}
 		file, err = util.AddImportForContent(file, alias, model.Package)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		imports[model.Package] = true
 	}
 	// insert customized imports
 	if tplInfo, exist := pkgGen.TemplateGenerator.tplsInfo[handlerTpl]; exist {
 		if len(tplInfo.UpdateBehavior.ImportTpl) != 0 {
-			imptSlice, err := getInsertImportContent(tplInfo, handler, file)
+			imptSlice, err := getInsertImportContent(tplInfo, h, file)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			for _, impt := range imptSlice {
-				if bytes.Contains(file, []byte(impt[1])) {
+				if imports[impt[1]] {
 					continue
 				}
 				file, err = util.AddImportForContent(file, impt[0], impt[1])
 				if err != nil {
 					logs.Warnf("can not add import(%s) for file(%s), err: %v\n", impt[1], filePath, err)
+					continue
 				}
+				imports[impt[1]] = true
 			}
 		}
 	}
 
-	// insert new handler
-	for _, method := range handler.(Handler).Methods {
-		if bytes.Contains(file, []byte(fmt.Sprintf("func %s(", method.Name))) {
+	funcs, err := existingFuncs(file)
+	if err != nil {
+		return nil, fmt.Errorf("parse handler file '%s' failed, err: %v", filePath, err)
+	}
+	// insert new handler, method by handler has only one handler in the file
+	for _, method := range h.Methods {
+		if funcs[method.Name] || pkgGen.HandlerByMethod {
 			continue
 		}
 
 		// Generate additional handlers using templates
 		handlerSingleTpl := pkgGen.tpls[handlerSingleTplName]
 		if handlerSingleTpl == nil {
-			return fmt.Errorf("tpl %s not found", handlerSingleTplName)
+			return nil, fmt.Errorf("tpl %s not found", handlerSingleTplName)
 		}
 		data := SingleHandler{
 			HttpMethod:  method,
-			FilePath:    handler.(Handler).FilePath,
-			PackageName: handler.(Handler).PackageName,
-			ProjPackage: handler.(Handler).ProjPackage,
+			FilePath:    h.FilePath,
+			PackageName: h.PackageName,
+			ProjPackage: h.ProjPackage,
 		}
 		handlerFunc := bytes.NewBuffer(nil)
 		err = handlerSingleTpl.Execute(handlerFunc, data)
 		if err != nil {
-			return fmt.Errorf("execute template \"%s\" failed, %v", handlerSingleTplName, err)
+			return nil, fmt.Errorf("execute template \"%s\" failed, %v", handlerSingleTplName, err)
 		}
 
 		buf := bytes.NewBuffer(nil)
 		_, err = buf.Write(file)
 		if err != nil {
-			return fmt.Errorf("write handler \"%s\" failed, %v", method.Name, err)
+			return nil, fmt.Errorf("write handler \"%s\" failed, %v", method.Name, err)
 		}
 		_, err = buf.Write(handlerFunc.Bytes())
 		if err != nil {
-			return fmt.Errorf("write handler \"%s\" failed, %v", method.Name, err)
+			return nil, fmt.Errorf("write handler \"%s\" failed, %v", method.Name, err)
 		}
 		file = buf.Bytes()
+		funcs[method.Name] = true
 	}
+	return file, nil
+}
 
-	pkgGen.files = append(pkgGen.files, File{filePath, string(file), false, ""})
+// splitStreamMethods keeps the unary methods in the handler, and returns the streaming methods
+func (h *Handler) splitStreamMethods() (streams []*HttpMethod) {
+	unary := make([]*HttpMethod, 0, len(h.Methods))
+	for _, m := range h.Methods {
+		if m.Stream != "" {
+			streams = append(streams, m)
+		} else {
+			unary = append(unary, m)
+		}
+	}
+	h.Methods = unary
+	return streams
+}
 
-	return nil
+// mergeStreamHandlers renders the streaming handlers and merges the ones not in the file,
+// the rendered file is used directly if there is no unary handler
+func (pkgGen *HttpPackageGenerator) mergeStreamHandlers(h Handler, file []byte) ([]byte, error) {
+	tpl := pkgGen.tpls[streamHandlerTplName]
+	if tpl == nil {
+		return nil, fmt.Errorf("tpl %s not found", streamHandlerTplName)
+	}
+	generated := bytes.NewBuffer(nil)
+	if err := tpl.Execute(generated, h); err != nil {
+		return nil, fmt.Errorf("render template '%s' failed, err: %v", streamHandlerTplName, err)
+	}
+	if len(file) == 0 {
+		return generated.Bytes(), nil
+	}
+	return mergeGoContent(file, generated.Bytes())
 }
 
 func (pkgGen *HttpPackageGenerator) updateClient(client interface{}, clientTpl, filePath string, noRepeat bool) error {
@@ -0,0 +1,291 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const orphanHandlerMark = " is not defined in the idl any more, please remove it if it is unused."
+
+type contentEdit struct {
+	start, end int
+	text       string
+}
+
+// existingFuncs returns the top level functions in the file
+func existingFuncs(file []byte) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", file, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	funcs := make(map[string]bool)
+	for _, decl := range f.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil {
+			funcs[fd.Name.Name] = true
+		}
+	}
+	return funcs, nil
+}
+
+// existingImports returns the import paths in the file
+func existingImports(file []byte) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", file, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	imports := make(map[string]bool, len(f.Imports))
+	for _, imp := range f.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		imports[p] = true
+	}
+	return imports, nil
+}
+
+// reconcileHandlers updates the request/response types and the @router comments of the handlers
+// by the idl, marks the handlers removed from the idl as deprecated and removes the unused imports
+func reconcileHandlers(filePath string, file []byte, methods []*HttpMethod, markOrphan bool) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", file, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse handler file '%s' failed, err: %v", filePath, err)
+	}
+	byName := make(map[string]*HttpMethod, len(methods))
+	for _, m := range methods {
+		byName[m.Name] = m
+	}
+
+	var edits []contentEdit
+	// the aliases of the model packages referenced by the replaced types, their imports may be unused
+	replaced := make(map[string]bool)
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+	replace := func(node ast.Node, text string) {
+		start, end := offset(node.Pos()), offset(node.End())
+		if string(file[start:end]) != text {
+			edits = append(edits, contentEdit{start, end, text})
+		}
+	}
+	replaceType := func(expr ast.Expr, text string) {
+		replace(expr, text)
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if name := identName(sel.X); name != "" {
+					replaced[name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || !isHandlerFunc(fd) {
+			continue
+		}
+		name := fd.Name.Name
+		mark := "// Deprecated: " + name + orphanHandlerMark
+		var markComment *ast.Comment
+		if fd.Doc != nil {
+			for _, c := range fd.Doc.List {
+				if c.Text == mark {
+					markComment = c
+				}
+			}
+		}
+
+		m, ok := byName[name]
+		if !ok {
+			if markOrphan {
+				logs.Warnf("handler '%s' in '%s' is not defined in the idl any more", name, filePath)
+				if markComment == nil {
+					start := offset(fd.Pos())
+					if fd.Doc != nil {
+						start = offset(fd.Doc.Pos())
+					}
+					edits = append(edits, contentEdit{start, start, mark + "\n"})
+				}
+			}
+			continue
+		}
+
+		if fd.Doc != nil {
+			if markComment != nil {
+				start, end := offset(markComment.Pos()), offset(markComment.End())
+				if end < len(file) && file[end] == '\n' {
+					end++
+				}
+				edits = append(edits, contentEdit{start, end, ""})
+			}
+			if router := routerComment(m.Comment); router != "" {
+				for _, c := range fd.Doc.List {
+					if strings.Contains(c.Text, "@router ") {
+						replace(c, router)
+						break
+					}
+				}
+			}
+		}
+
+		if fd.Body == nil {
+			continue
+		}
+		// only the statements generated by the handler template are updated:
+		//
+		//	var req {{.RequestTypeName}}
+		//	err = c.BindAndValidate(&req)
+		//	...
+		//	resp := new({{.ReturnTypeName}})
+		for i, stmt := range fd.Body.List {
+			switch x := stmt.(type) {
+			case *ast.DeclStmt:
+				if m.RequestTypeName == "" || i+1 >= len(fd.Body.List) || !bindsRequest(fd.Body.List[i+1]) {
+					continue
+				}
+				if gd, ok := x.Decl.(*ast.GenDecl); ok && gd.Tok == token.VAR && len(gd.Specs) == 1 {
+					vs := gd.Specs[0].(*ast.ValueSpec)
+					if len(vs.Names) == 1 && vs.Names[0].Name == "req" && vs.Type != nil && len(vs.Values) == 0 {
+						replaceType(vs.Type, m.RequestTypeName)
+					}
+				}
+			case *ast.AssignStmt: // resp := new({{.ReturnTypeName}}) or resp := &{{.ReturnTypeName}}{}
+				if m.ReturnTypeName == "" || x.Tok != token.DEFINE || len(x.Lhs) != 1 || len(x.Rhs) != 1 || identName(x.Lhs[0]) != "resp" {
+					continue
+				}
+				switch rhs := x.Rhs[0].(type) {
+				case *ast.CallExpr:
+					if identName(rhs.Fun) == "new" && len(rhs.Args) == 1 {
+						replaceType(rhs.Args[0], m.ReturnTypeName)
+					}
+				case *ast.UnaryExpr:
+					if cl, ok := rhs.X.(*ast.CompositeLit); ok && rhs.Op == token.AND && cl.Type != nil {
+						replaceType(cl.Type, m.ReturnTypeName)
+					}
+				}
+			}
+		}
+	}
+
+	if len(edits) > 0 {
+		sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+		for _, e := range edits {
+			file = append(file[:e.start:e.start], append([]byte(e.text), file[e.end:]...)...)
+		}
+		fset = token.NewFileSet()
+		if f, err = parser.ParseFile(fset, "", file, parser.ParseComments); err != nil {
+			return nil, fmt.Errorf("parse updated handler file '%s' failed, err: %v", filePath, err)
+		}
+	}
+
+	removeUnusedImports(fset, f, replaced)
+	var buf bytes.Buffer
+	if err = format.Node(&buf, fset, f); err != nil {
+		return nil, fmt.Errorf("format handler file '%s' failed, err: %v", filePath, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// isHandlerFunc checks the function is in the form of func(ctx context.Context, c *app.RequestContext)
+func isHandlerFunc(fd *ast.FuncDecl) bool {
+	params := fd.Type.Params.List
+	// the handler has no result, unlike the constructors of the streams
+	if len(params) == 0 || fd.Type.Results != nil {
+		return false
+	}
+	star, ok := params[len(params)-1].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "RequestContext"
+}
+
+func routerComment(comment string) string {
+	for _, line := range strings.Split(comment, "\n") {
+		if strings.Contains(line, "@router ") {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
+func identName(expr ast.Expr) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// bindsRequest checks the statement binds the request, eg: err = c.BindAndValidate(&req)
+func bindsRequest(stmt ast.Stmt) bool {
+	if is, ok := stmt.(*ast.IfStmt); ok && is.Init != nil {
+		stmt = is.Init
+	}
+	var call ast.Expr
+	switch x := stmt.(type) {
+	case *ast.AssignStmt:
+		if len(x.Rhs) == 1 {
+			call = x.Rhs[0]
+		}
+	case *ast.ExprStmt:
+		call = x.X
+	}
+	ce, ok := call.(*ast.CallExpr)
+	if !ok || len(ce.Args) != 1 {
+		return false
+	}
+	sel, ok := ce.Fun.(*ast.SelectorExpr)
+	if !ok || !strings.HasPrefix(sel.Sel.Name, "Bind") {
+		return false
+	}
+	arg, ok := ce.Args[0].(*ast.UnaryExpr)
+	return ok && arg.Op == token.AND && identName(arg.X) == "req"
+}
+
+// removeUnusedImports removes the model imports which are not referenced after the types are replaced,
+// the model packages are imported with the aliases by the generator, so the other imports are kept
+func removeUnusedImports(fset *token.FileSet, f *ast.File, replaced map[string]bool) {
+	if len(replaced) == 0 {
+		return
+	}
+	used := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	for _, imp := range append([]*ast.ImportSpec(nil), f.Imports...) {
+		if imp.Name == nil || !replaced[imp.Name.Name] || used[imp.Name.Name] {
+			continue
+		}
+		p, _ := strconv.Unquote(imp.Path.Value)
+		astutil.DeleteNamedImport(fset, f, imp.Name.Name, p)
+	}
+}
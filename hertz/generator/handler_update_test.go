@@ -0,0 +1,246 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/cloudwego/hertz/cmd/hz/generator/model"
+)
+
+func TestReconcileHandlers(t *testing.T) {
+	file := `package user
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/json-iterator/go"
+	v1 "example.com/biz/model/user/v1"
+	v2 "example.com/biz/model/user/v2"
+	audit "example.com/biz/model/audit"
+)
+
+// GetUser .
+// @router /user [GET]
+func GetUser(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req v1.GetUserReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+	resp := new(v1.GetUserResp)
+	// keep the custom code
+	data, _ := jsoniter.Marshal(resp)
+	func() {
+		var req audit.Record
+		_ = req
+	}()
+	c.Data(consts.StatusOK, "application/json", data)
+}
+
+// ListUser .
+// @router /users [GET]
+func ListUser(ctx context.Context, c *app.RequestContext) {
+	var req v1.ListUserReq
+	c.JSON(consts.StatusOK, req)
+}
+
+// DeleteUser .
+// @router /user [DELETE]
+func DeleteUser(ctx context.Context, c *app.RequestContext) {
+	resp := &v2.DeleteUserResp{}
+	c.JSON(consts.StatusOK, resp)
+}
+`
+	methods := []*HttpMethod{{
+		Name:            "GetUser",
+		HTTPMethod:      "GET",
+		Path:            "/user/:id",
+		RequestTypeName: "v2.GetUserReq",
+		ReturnTypeName:  "v2.GetUserResp",
+	}, {
+		Name:            "ListUser",
+		HTTPMethod:      "GET",
+		Path:            "/users",
+		RequestTypeName: "v2.ListUserReq",
+	}}
+	for _, m := range methods {
+		m.InitComment()
+	}
+
+	out, err := reconcileHandlers("user.go", []byte(file), methods, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	for _, want := range []string{
+		"// @router /user/:id [GET]",
+		"var req v2.GetUserReq",
+		"resp := new(v2.GetUserResp)",
+		"// keep the custom code",
+		// the package name of the import differs from its path
+		`"github.com/json-iterator/go"`,
+		// only the generated binding statement is updated
+		"var req audit.Record",
+		`audit "example.com/biz/model/audit"`,
+		"var req v1.ListUserReq",
+		"// Deprecated: DeleteUser" + orphanHandlerMark + "\n// DeleteUser .",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(got, `v1 "example.com/biz/model/user/v1"`) {
+		t.Errorf("want the import still used kept in:\n%s", got)
+	}
+
+	// the orphaned handler is marked only once, and the mark is removed when it is defined again
+	out, err = reconcileHandlers("user.go", out, methods, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(out), "// Deprecated: DeleteUser"); n != 1 {
+		t.Errorf("want the mark once, got %d", n)
+	}
+	methods = append(methods, &HttpMethod{Name: "DeleteUser", HTTPMethod: "DELETE", Path: "/user", ReturnTypeName: "v2.DeleteUserResp"})
+	methods[2].InitComment()
+	out, err = reconcileHandlers("user.go", out, methods, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "Deprecated") {
+		t.Errorf("want the mark removed in:\n%s", out)
+	}
+}
+
+func TestReconcileHandlersImports(t *testing.T) {
+	file := `package user
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/json-iterator/go"
+	"github.com/labstack/gommon/log"
+	v1 "example.com/biz/model/user/v1"
+)
+
+// GetUser .
+// @router /user [GET]
+func GetUser(ctx context.Context, c *app.RequestContext) {
+	var req v1.GetUserReq
+	if err := c.BindAndValidate(&req); err != nil {
+		return
+	}
+	data, _ := jsoniter.Marshal(req)
+	c.Data(200, "application/json", data)
+}
+`
+	methods := []*HttpMethod{{Name: "GetUser", HTTPMethod: "GET", Path: "/user", RequestTypeName: "v2.GetUserReq"}}
+	methods[0].InitComment()
+	out, err := reconcileHandlers("user.go", []byte(file), methods, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	if !strings.Contains(got, "var req v2.GetUserReq") {
+		t.Errorf("want the request type replaced in:\n%s", got)
+	}
+	// the import of the replaced type is removed, the imports not added by the generator are kept
+	if strings.Contains(got, "user/v1") {
+		t.Errorf("want the unused import removed in:\n%s", got)
+	}
+	for _, want := range []string{`"github.com/json-iterator/go"`, `"github.com/labstack/gommon/log"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %s kept in:\n%s", want, got)
+		}
+	}
+}
+
+func TestUpdateStreamHandlers(t *testing.T) {
+	pkgGen := &HttpPackageGenerator{}
+	pkgGen.tpls = map[string]*template.Template{}
+	pkgGen.tplsInfo = map[string]*Template{}
+	for _, layout := range defaultPkgConfig.Layouts {
+		if err := pkgGen.loadLayout(layout, filepath.Base(layout.Path), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	userModel := &model.Model{PackageName: "user", Package: "example.com/biz/model/user"}
+	method := func(name, stream string) *HttpMethod {
+		m := &HttpMethod{
+			Name: name, HTTPMethod: "GET", Path: "/user/" + strings.ToLower(name), Serializer: "JSON", Stream: stream, GenHandler: true,
+			RequestTypeName: "user." + name + "Req", ReturnTypeName: "user." + name + "Resp",
+			Models: map[string]*model.Model{"user": userModel},
+		}
+		m.InitComment()
+		return m
+	}
+	handler := Handler{
+		PackageName: "user",
+		Imports:     map[string]*model.Model{"user": userModel},
+		Methods:     []*HttpMethod{method("GetUser", ""), method("WatchUser", StreamSSE)},
+	}
+	filePath := filepath.Join(t.TempDir(), "user.go")
+	if err := pkgGen.updateHandler(handler, handlerTplName, filePath, false); err != nil {
+		t.Fatal(err)
+	}
+	got := pkgGen.files[len(pkgGen.files)-1].Content
+	for _, want := range []string{
+		"func GetUser(ctx context.Context, c *app.RequestContext)",
+		"func WatchUser(ctx context.Context, c *app.RequestContext)",
+		"func (s *WatchUserStream) Send(resp *user.WatchUserResp) error",
+		`"github.com/hertz-contrib/sse"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "websocket") {
+		t.Errorf("want no websocket in:\n%s", got)
+	}
+
+	// the websocket handler is merged into the existing file
+	if err := ioutil.WriteFile(filePath, []byte(got), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	handler.Methods = append(handler.Methods, method("ChatUser", StreamWebSocket))
+	if err := pkgGen.updateHandler(handler, handlerTplName, filePath, false); err != nil {
+		t.Fatal(err)
+	}
+	got = pkgGen.files[len(pkgGen.files)-1].Content
+	if strings.Count(got, "type WatchUserStream struct") != 1 || strings.Contains(got, "Deprecated") {
+		t.Errorf("want the existing handlers kept in:\n%s", got)
+	}
+	for _, want := range []string{
+		"func (s *ChatUserStream) Recv() (*user.ChatUserReq, error)",
+		"var ChatUserUpgrader = websocket.HertzUpgrader{}",
+		`"github.com/hertz-contrib/websocket"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+}
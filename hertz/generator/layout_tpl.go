@@ -32,6 +32,7 @@ const (
 	defaultConfDir    = "conf"
 	defaultRouterDir  = "biz" + sp + "router"
 	defaultClientDir  = "biz" + sp + "client"
+	defaultMockDir    = "mock"
 )
 
 const (
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/util"
+)
+
+// MiddlewareFactory is a middleware of the "middleware" annotations of the idl, it is created by the factory in the registry
+type MiddlewareFactory struct {
+	Name string // name in the annotations
+	Func string // name of the factory
+}
+
+// MiddlewareRegistry is the rendering data of the registry which maps the names of the middlewares to the factories
+type MiddlewareRegistry struct {
+	FilePath    string
+	PackageName string
+	Middlewares []MiddlewareFactory
+}
+
+var middlewareNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// ParseMiddlewares parses the names of the middlewares in the annotation, they are separated by the commas, eg: "auth,ratelimit"
+func ParseMiddlewares(val, desc string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(val, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !middlewareNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid middleware '%s' of %s, it should be letters, digits, '_' or '-'", name, desc)
+		}
+		names = MergeMiddlewares(names, name)
+	}
+	return names, nil
+}
+
+// MergeMiddlewares appends the middlewares which are not in the list
+func MergeMiddlewares(mws []string, names ...string) []string {
+	for _, name := range names {
+		if !stringsIncludes(mws, name) {
+			mws = append(mws, name)
+		}
+	}
+	return mws
+}
+
+// middlewareDir returns the directory of the middleware registry, it is next to the router dir, such as "biz/middleware"
+func (pkgGen *HttpPackageGenerator) middlewareDir() string {
+	return filepath.Join(filepath.Dir(pkgGen.RouterDir), "middleware")
+}
+
+// updateMiddlewareRegistry generates the registry of the middlewares of the idl annotations once,
+// the middlewares not in the registry are added with the empty factories for the update command
+func (pkgGen *HttpPackageGenerator) updateMiddlewareRegistry(pkg *HttpPackage) error {
+	var names []string
+	for _, s := range pkg.Services {
+		for _, m := range s.Methods {
+			for _, mw := range m.Middlewares {
+				if !stringsIncludes(names, mw) {
+					names = append(names, mw)
+				}
+			}
+		}
+	}
+	if len(names) == 0 || pkgGen.tplsInfo[middlewareRegistryTplName].Disable {
+		return nil
+	}
+
+	registry := MiddlewareRegistry{
+		FilePath:    filepath.Join(pkgGen.middlewareDir(), "registry.go"),
+		PackageName: filepath.Base(pkgGen.middlewareDir()),
+	}
+	funcs := make(map[string]string, len(names))
+	for _, name := range names {
+		f := MiddlewareFactory{Name: name, Func: util.CamelString(util.ToGoFuncName(name))}
+		if other, ok := funcs[f.Func]; ok {
+			return fmt.Errorf("the factories of the middlewares '%s' and '%s' are both named '%s'", other, name, f.Func)
+		}
+		funcs[f.Func] = name
+		registry.Middlewares = append(registry.Middlewares, f)
+	}
+
+	registryPath := filepath.Join(pkgGen.Module, registry.FilePath)
+	isExist, err := util.PathExist(registryPath)
+	if err != nil {
+		return err
+	}
+	if !isExist {
+		return pkgGen.TemplateGenerator.Generate(registry, middlewareRegistryTplName, registry.FilePath, false)
+	}
+
+	file, err := ioutil.ReadFile(registryPath)
+	if err != nil {
+		return fmt.Errorf("read middleware registry '%s' failed, err: %v", registryPath, err)
+	}
+	factoryTpl := pkgGen.tpls[middlewareFactoryTplName]
+	if factoryTpl == nil {
+		return fmt.Errorf("tpl %s not found", middlewareFactoryTplName)
+	}
+	var added bool
+	for _, mw := range registry.Middlewares {
+		entry := fmt.Sprintf("%q: %s,", mw.Name, mw.Func)
+		if bytes.Contains(file, []byte(fmt.Sprintf("%q:", mw.Name))) {
+			continue
+		}
+		subIndexReg := regRegisterV3.FindSubmatchIndex(file)
+		if len(subIndexReg) != 2 || subIndexReg[0] < 1 {
+			return fmt.Errorf("wrong format %s: insert-point '%s' not found", registryPath, insertPointNew)
+		}
+		buf := bytes.NewBuffer(nil)
+		buf.Write(file[:subIndexReg[1]])
+		buf.WriteString("\n\t" + entry)
+		buf.Write(file[subIndexReg[1]:])
+		if err = factoryTpl.Execute(buf, mw); err != nil {
+			return fmt.Errorf("execute template \"%s\" failed, %v", middlewareFactoryTplName, err)
+		}
+		file = buf.Bytes()
+		added = true
+	}
+	if added {
+		pkgGen.files = append(pkgGen.files, File{registry.FilePath, string(file), false, middlewareRegistryTplName})
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"path/filepath"
+
+	"github.com/cloudwego/hertz/cmd/hz/generator/model"
+	"github.com/cloudwego/hertz/cmd/hz/util"
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+)
+
+// Mock is the rendering data of the mock handlers of an idl
+type Mock struct {
+	FilePath string
+	IdlName  string
+	Name     string // prefix of the mock handlers
+	Imports  map[string]*model.Model
+	Methods  []*HttpMethod
+}
+
+// genMock generates the mock handlers of the idl in the standalone mock server, which is in "mock/main.go",
+// the handlers validate the requests by the models and respond the examples of the responses
+func (pkgGen *HttpPackageGenerator) genMock(pkg *HttpPackage) error {
+	if info := pkgGen.tplsInfo[mockTplName]; info == nil || info.Disable {
+		return nil
+	}
+	idlName := util.ToSnakeCase(util.BaseNameAndTrim(pkg.IdlName))
+	mock := Mock{
+		FilePath: filepath.Join(defaultMockDir, idlName+"_mock.go"),
+		IdlName:  pkg.IdlName,
+		Name:     util.ToCamelCase(idlName),
+		Imports:  make(map[string]*model.Model),
+	}
+	for _, s := range pkg.Services {
+		for _, m := range s.Methods {
+			if m.Stream != "" {
+				if m.GenHandler {
+					logs.Warnf("the mock handler of the streaming method '%s' is not generated", m.Name)
+				}
+				continue
+			}
+			mock.Methods = append(mock.Methods, m)
+			for key, mm := range m.Models {
+				if v, ok := mock.Imports[mm.PackageName]; ok && v.Package != mm.Package {
+					mock.Imports[key] = mm
+					continue
+				}
+				mock.Imports[mm.PackageName] = mm
+			}
+		}
+	}
+	if len(mock.Methods) == 0 {
+		return nil
+	}
+
+	// the main of the mock server is generated once, it runs the mock handlers of all the idls
+	mainPath := filepath.Join(defaultMockDir, "main.go")
+	isExist, err := util.PathExist(filepath.Join(pkgGen.Module, mainPath))
+	if err != nil {
+		return err
+	}
+	if !isExist {
+		if err = pkgGen.TemplateGenerator.Generate(mock, mockMainTplName, mainPath, false); err != nil {
+			return err
+		}
+	}
+	return pkgGen.TemplateGenerator.Generate(mock, mockTplName, mock.FilePath, false)
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/cmd/hz/generator/model"
+)
+
+func TestGenMock(t *testing.T) {
+	pkgGen := &HttpPackageGenerator{
+		ProjPackage: "example.com/demo",
+		Mock:        true,
+		TemplateGenerator: TemplateGenerator{
+			OutputDir: t.TempDir(),
+		},
+	}
+	if err := pkgGen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	models := map[string]*model.Model{"user": {PackageName: "user", Package: "example.com/demo/biz/model/user"}}
+	getUser := &HttpMethod{
+		Name: "GetUser", HTTPMethod: "GET", Path: "/user/:id", GenHandler: true, Models: models,
+		RequestTypeName: "user.GetUserReq", ReturnTypeName: "user.User", ExampleResponse: `{"id":1,"name":"alice"}`,
+	}
+	pkg := &HttpPackage{IdlName: "user_api.proto", Package: "user", Services: []*Service{{
+		Name: "UserService",
+		Methods: []*HttpMethod{
+			getUser,
+			{Name: "GetUser", HTTPMethod: "Any", Path: "/v1/user/:id", Models: models},
+			{Name: "WatchUser", HTTPMethod: "GET", Path: "/user/:id/watch", GenHandler: true, Stream: StreamSSE, Models: models},
+		},
+	}}}
+	if err := pkgGen.genMock(pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range pkgGen.Files() {
+		if _, err := format.Source([]byte(f.Content)); err != nil {
+			t.Errorf("format '%s' failed, err: %v\n%s", f.Path, err, f.Content)
+		}
+		files[f.Path] = f.Content
+	}
+	if !strings.Contains(files["mock/main.go"], "for _, register := range mockRegisters {") {
+		t.Errorf("want the main of the mock server, got files: %v", files)
+	}
+	mock := files["mock/user_api_mock.go"]
+	for _, want := range []string{
+		`user "example.com/demo/biz/model/user"`,
+		"mockRegisters = append(mockRegisters, registerUserApiMock)",
+		`r.Handle("GET", "/user/:id", mockUserApiGetUser)`,
+		`r.Any("/v1/user/:id", mockUserApiGetUser)`,
+		"if err := c.BindAndValidate(&req); err != nil {",
+		`json.Unmarshal([]byte("{\"id\":1,\"name\":\"alice\"}"), resp)`,
+	} {
+		if !strings.Contains(mock, want) {
+			t.Errorf("want %q in the mock handlers:\n%s", want, mock)
+		}
+	}
+	if strings.Count(mock, "func mockUserApiGetUser(") != 1 || strings.Contains(mock, "WatchUser") {
+		t.Errorf("want the mock handler of the unary method only:\n%s", mock)
+	}
+}
@@ -18,6 +18,8 @@ package generator
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -75,8 +77,19 @@ func switchBackend(backend meta.Backend) Backend {
 	return loadThirdPartyBackend(string(backend))
 }
 
+// loadThirdPartyBackend loads the backend from a template directory, or a plugin binary
+// named as the backend or prefixed with BackendPluginPrefix in $PATH. Both of them only
+// provide the templates, the models are rendered with the templates by cwgo
 func loadThirdPartyBackend(plugin string) Backend {
-	panic("no implement yet!")
+	if fi, err := os.Stat(plugin); err == nil && fi.IsDir() {
+		return newDirBackend(plugin)
+	}
+	for _, name := range []string{plugin, BackendPluginPrefix + plugin} {
+		if path, err := exec.LookPath(name); err == nil {
+			return newPluginBackend(path)
+		}
+	}
+	return nil
 }
 
 /**********************Generating*************************/
@@ -130,7 +143,11 @@ func (pkgGen *HttpPackageGenerator) GenModel(data *model.Model, gen bool) error
 		if updatePackage {
 			data.Package = util.SubPackage(pkgGen.ProjPackage, modelDir)
 		}
-		data.FilePath = filepath.Join(modelDir, util.BaseNameAndTrim(data.FilePath)+".go")
+		suffix := defaultFileSuffix
+		if fs, ok := pkgGen.loadedBackend.(interface{ FileSuffix() string }); ok {
+			suffix = fs.FileSuffix()
+		}
+		data.FilePath = filepath.Join(modelDir, util.BaseNameAndTrim(data.FilePath)+suffix)
 
 		pkgGen.processedModels[data] = true
 	}
@@ -17,6 +17,10 @@
 package generator
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"text/template"
 
@@ -238,3 +242,133 @@ func TestIdlGenerator_GenModel(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadThirdPartyBackend(t *testing.T) {
+	dir := t.TempDir()
+	tpls := map[string]string{
+		"file.ts.tpl": `{{template "header" .}}{{if HasOption "MarshalEnumToText"}}// enum as text{{end}}`,
+		"header.tpl":  `// {{.PackageName}} {{ROOT.FilePath}}`,
+	}
+	for name, content := range tpls {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	self := &HttpPackageGenerator{
+		ModelDir: "biz/model",
+		Options:  []Option{OptionMarshalEnumToText},
+	}
+	if err := self.LoadBackend(meta.Backend(dir)); err != nil {
+		t.Fatal(err)
+	}
+	data := &model.Model{
+		FilePath:    "idl/main.proto",
+		Package:     "psm",
+		PackageName: "psm",
+		Variables:   []model.Variable{{Scope: &model.Model{}, Name: "x"}},
+	}
+	if err := self.GenModel(data, true); err != nil {
+		t.Fatal(err)
+	}
+
+	files := self.Files()
+	if len(files) != 1 {
+		t.Fatalf("want 1 file, got %d", len(files))
+	}
+	if want := filepath.Join("biz/model/psm", "main.ts"); files[0].Path != want {
+		t.Errorf("want path %s, got %s", want, files[0].Path)
+	}
+	if want := "// psm biz/model/psm/main.ts// enum as text"; files[0].Content != want {
+		t.Errorf("want content %q, got %q", want, files[0].Content)
+	}
+
+	if err := self.LoadBackend(meta.Backend("not_exist_backend")); err == nil {
+		t.Error("want error of the backend not found")
+	}
+}
+
+// testBackendPluginEnv makes the test binary run as the backend plugin, see linkBackendPlugin
+const testBackendPluginEnv = "CWGO_TEST_BACKEND_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testBackendPluginEnv) != "" {
+		runTestBackendPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runTestBackendPlugin responds the templates which print the options of the request
+func runTestBackendPlugin() {
+	var req struct {
+		Options []string `json:"options"`
+	}
+	resp := map[string]interface{}{}
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		resp["error"] = err.Error()
+	} else if len(req.Options) == 0 {
+		resp["error"] = "no options"
+	} else {
+		resp["suffix"] = ".txt"
+		resp["templates"] = map[string]string{
+			"file":   `{{template "header" .}} {{range Options}}{{.}};{{end}}`,
+			"header": "// " + strings.Join(req.Options, ",") + " {{.PackageName}} {{ROOT.FilePath}}",
+		}
+	}
+	json.NewEncoder(os.Stdout).Encode(resp)
+}
+
+// linkBackendPlugin links the test binary as the plugin into a directory in $PATH
+func linkBackendPlugin(t *testing.T, name string) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(t.TempDir(), name)
+	if err = os.Symlink(exe, bin); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(testBackendPluginEnv, "1")
+	t.Setenv("PATH", filepath.Dir(bin)+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLoadPluginBackend(t *testing.T) {
+	linkBackendPlugin(t, BackendPluginPrefix+"testplugin")
+
+	self := &HttpPackageGenerator{
+		ModelDir: "biz/model",
+		Options:  []Option{OptionMarshalEnumToText},
+	}
+	if err := self.LoadBackend(meta.Backend("testplugin")); err != nil {
+		t.Fatal(err)
+	}
+	data := &model.Model{
+		FilePath:    "idl/main.thrift",
+		Package:     "psm",
+		PackageName: "psm",
+		Variables:   []model.Variable{{Scope: &model.Model{}, Name: "x"}},
+	}
+	if err := self.GenModel(data, true); err != nil {
+		t.Fatal(err)
+	}
+
+	files := self.Files()
+	if len(files) != 1 {
+		t.Fatalf("want 1 file, got %d", len(files))
+	}
+	if want := filepath.Join("biz/model/psm", "main.txt"); files[0].Path != want {
+		t.Errorf("want path %s, got %s", want, files[0].Path)
+	}
+	opt := string(OptionMarshalEnumToText)
+	if want := "// " + opt + " psm biz/model/psm/main.txt " + opt + ";"; files[0].Content != want {
+		t.Errorf("want content %q, got %q", want, files[0].Content)
+	}
+
+	// the error in the response of the plugin
+	self = &HttpPackageGenerator{ModelDir: "biz/model"}
+	err := self.LoadBackend(meta.Backend("testplugin"))
+	if err == nil || !strings.Contains(err.Error(), "no options") {
+		t.Errorf("want error of the plugin, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/util"
+)
+
+// Swagger is the rendering data of the handlers of the openapi document and swagger ui
+type Swagger struct {
+	FilePath    string
+	PackageName string
+	IdlName     string
+	Name        string // prefix of the handlers
+	VarName     string // prefix of the embedded files
+	DocFile     string
+	DocPath     string
+	PageFile    string
+	PagePath    string
+}
+
+const swaggerPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+<script>
+  window.onload = () => {
+    window.ui = SwaggerUIBundle({
+      url: "openapi.yaml",
+      dom_id: "#swagger-ui",
+    });
+  };
+</script>
+</body>
+</html>
+`
+
+// genOpenAPI writes the openapi document of the idl alongside the handlers, and registers the routes of
+// the document and swagger ui as "/swagger/{idl}/openapi.yaml" and "/swagger/{idl}/index.html" if need
+func (pkgGen *HttpPackageGenerator) genOpenAPI(pkg *HttpPackage, root *RouterNode) error {
+	handlerDir := util.SubDir(pkgGen.HandlerDir, pkg.Package)
+	idlName := util.ToSnakeCase(util.BaseNameAndTrim(pkg.IdlName))
+	docFile := idlName + "_openapi.yaml"
+	pkgGen.files = append(pkgGen.files, File{filepath.Join(handlerDir, docFile), string(pkgGen.OpenAPI), false, ""})
+
+	if info := pkgGen.tplsInfo[swaggerTplName]; !pkgGen.SwaggerUI || info == nil || info.Disable {
+		return nil
+	}
+	handlerPackage := util.SubPackage(pkgGen.ProjPackage, filepath.Join(pkgGen.Module, handlerDir))
+	name := util.ToCamelCase(idlName)
+	swagger := Swagger{
+		FilePath:    filepath.Join(handlerDir, idlName+"_swagger.go"),
+		PackageName: util.SplitPackage(handlerPackage, ""),
+		IdlName:     pkg.IdlName,
+		Name:        name,
+		VarName:     strings.ToLower(name[:1]) + name[1:],
+		DocFile:     docFile,
+		DocPath:     "/swagger/" + idlName + "/openapi.yaml",
+		PageFile:    idlName + "_swagger.html",
+		PagePath:    "/swagger/" + idlName + "/index.html",
+	}
+	pkgGen.files = append(pkgGen.files, File{filepath.Join(handlerDir, swagger.PageFile), fmt.Sprintf(swaggerPage, pkg.IdlName), false, ""})
+
+	methods := []*HttpMethod{
+		{Name: swagger.Name + "OpenAPI", HTTPMethod: "GET", Path: swagger.DocPath},
+		{Name: swagger.Name + "SwaggerUI", HTTPMethod: "GET", Path: swagger.PagePath},
+	}
+	for _, m := range methods {
+		// the handlers by method are in the packages of their own, so the package of the swagger needs an unique alias
+		handlerPkg := ""
+		if pkgGen.HandlerByMethod {
+			handlerPkg = handlerPackage
+		} else {
+			m.RefPackage = handlerPackage
+			m.RefPackageAlias = util.BaseName(handlerPackage, "")
+		}
+		if err := root.Update(m, swagger.PackageName, handlerPkg, pkgGen.SortRouter); err != nil {
+			return fmt.Errorf("register swagger route '%s' failed, err: %v", m.Path, err)
+		}
+	}
+	return pkgGen.TemplateGenerator.Generate(swagger, swaggerTplName, swagger.FilePath, false)
+}
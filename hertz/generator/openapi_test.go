@@ -0,0 +1,72 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGenOpenAPI(t *testing.T) {
+	pkgGen := &HttpPackageGenerator{
+		HandlerDir:  "biz/handler",
+		RouterDir:   "biz/router",
+		ProjPackage: "example.com/demo",
+		OpenAPI:     []byte("openapi: 3.0.3\n"),
+		SwaggerUI:   true,
+		TemplateGenerator: TemplateGenerator{
+			OutputDir: t.TempDir(),
+		},
+	}
+	if err := pkgGen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	root := NewRouterTree()
+	if err := pkgGen.genOpenAPI(&HttpPackage{IdlName: "user_api.proto", Package: "user"}, root); err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range pkgGen.Files() {
+		files[f.Path] = f.Content
+	}
+	if files["biz/handler/user/user_api_openapi.yaml"] != "openapi: 3.0.3\n" {
+		t.Errorf("want the openapi document alongside the handlers, got files: %v", files)
+	}
+	handler := files["biz/handler/user/user_api_swagger.go"]
+	for _, want := range []string{"package user", "//go:embed user_api_openapi.yaml", "//go:embed user_api_swagger.html", "func UserApiSwaggerUI("} {
+		if !strings.Contains(handler, want) {
+			t.Errorf("want %q in the swagger handler:\n%s", want, handler)
+		}
+	}
+	if !strings.Contains(files["biz/handler/user/user_api_swagger.html"], `url: "openapi.yaml"`) {
+		t.Errorf("want the swagger ui page, got files: %v", files)
+	}
+
+	var handlers []string
+	root.DFS(0, func(layer int, node *RouterNode) error {
+		if node.Handler != "" {
+			handlers = append(handlers, node.HttpMethod+" "+node.Handler)
+		}
+		return nil
+	})
+	sort.Strings(handlers)
+	if strings.Join(handlers, ",") != "GET user.UserApiOpenAPI,GET user.UserApiSwaggerUI" {
+		t.Errorf("unexpected swagger routes: %v", handlers)
+	}
+}
@@ -46,6 +46,7 @@ type Service struct {
 	BaseDomain    string         // base domain for client code
 	ServiceGroup  string         // service level router group
 	ServiceGenDir string         // handler_dir for handler_by_service
+	TSTypes       []*TSType      // types of the typescript client, they are resolved only for "--lang ts"
 }
 
 // HttpPackageGenerator is used to record the configuration related to generating hertz http code.
@@ -65,6 +66,7 @@ type HttpPackageGenerator struct {
 	ForceClientDir string // client dir without namespace for "client" command
 	BaseDomain     string // request domain for "client" command
 	QueryEnumAsInt bool   // client code use number for query parameter
+	ClientLang     string // language of the client for "client" command, go is used if it is empty
 	ServiceGenDir  string
 
 	NeedModel            bool
@@ -72,6 +74,10 @@ type HttpPackageGenerator struct {
 	SnakeStyleMiddleware bool // use snake name style for middleware
 	SortRouter           bool
 
+	OpenAPI   []byte // openapi document of the idl, it is generated alongside the handlers
+	SwaggerUI bool   // register the swagger ui of the openapi document in the router
+	Mock      bool   // generate the mock server which responds the examples of the responses
+
 	loadedBackend   Backend
 	curModel        *model.Model
 	processedModels map[*model.Model]bool
@@ -185,15 +191,31 @@ func (pkgGen *HttpPackageGenerator) Generate(pkg *HttpPackage) error {
 	routerDir := util.SubDir(pkgGen.RouterDir, pkg.Package)
 	routerPackage := util.SubPackage(pkgGen.ProjPackage, filepath.Join(pkgGen.Module, routerDir))
 
+	if err := pkgGen.checkRouteConflicts(pkg, routerDir); err != nil {
+		return err
+	}
+
 	root := NewRouterTree()
 	if err := pkgGen.genHandler(pkg, handlerDir, handlerPackage, root); err != nil {
 		return err
 	}
 
+	if len(pkgGen.OpenAPI) != 0 {
+		if err := pkgGen.genOpenAPI(pkg, root); err != nil {
+			return err
+		}
+	}
+
 	if err := pkgGen.genRouter(pkg, root, handlerPackage, routerDir, routerPackage); err != nil {
 		return err
 	}
 
+	if pkgGen.Mock {
+		if err := pkgGen.genMock(pkg); err != nil {
+			return err
+		}
+	}
+
 	if err := pkgGen.genCustomizedFile(pkg); err != nil {
 		return err
 	}
@@ -22,11 +22,23 @@ var (
 	middlewareSingleTplName = "middleware_single.go"
 	handlerTplName          = "handler.go"
 	handlerSingleTplName    = "handler_single.go"
+	streamHandlerTplName    = "stream_handler.go" // handlers of the streaming methods
 	modelTplName            = "model.go"
 	registerTplName         = "register.go"
-	clientTplName           = "client.go"       // generate a default client for server
-	hertzClientTplName      = "hertz_client.go" // underlying client for client command
-	idlClientName           = "idl_client.go"   // client of service for quick call
+	clientTplName           = "client.go"            // generate a default client for server
+	hertzClientTplName      = "hertz_client.go"      // underlying client for client command
+	idlClientName           = "idl_client.go"        // client of service for quick call
+	idlClientExtName        = "idl_client_ext.go"    // extension of the client, which is generated once
+	idlClientPolicyName     = "idl_client_policy.go" // timeout and retry policies of the methods from the idl
+	idlClientStreamName     = "idl_client_stream.go" // client of the server streaming methods
+	idlClientTSName         = "idl_client.ts"        // typescript client of service for "--lang ts"
+	idlClientTSTypesName    = "idl_client_types.ts"  // typescript types of the requests and responses
+	swaggerTplName          = "swagger.go"           // handlers of the openapi document and swagger ui
+	mockTplName             = "mock.go"              // mock handlers of the idl which respond the examples
+	mockMainTplName         = "mock_main.go"         // main of the mock server, which is generated once
+
+	middlewareRegistryTplName = "middleware_registry.go" // registry of the middlewares of the idl annotations, which is generated once
+	middlewareFactoryTplName  = "middleware_factory.go"  // factory of a middleware appended to the registry
 
 	insertPointNew        = "//INSERT_POINT: DO NOT DELETE THIS LINE!"
 	insertPointPatternNew = `//INSERT_POINT\: DO NOT DELETE THIS LINE\!`
@@ -38,11 +50,23 @@ var templateNameSet = map[string]string{
 	middlewareSingleTplName: middlewareSingleTplName,
 	handlerTplName:          handlerTplName,
 	handlerSingleTplName:    handlerSingleTplName,
+	streamHandlerTplName:    streamHandlerTplName,
 	modelTplName:            modelTplName,
 	registerTplName:         registerTplName,
 	clientTplName:           clientTplName,
 	hertzClientTplName:      hertzClientTplName,
 	idlClientName:           idlClientName,
+	idlClientExtName:        idlClientExtName,
+	idlClientPolicyName:     idlClientPolicyName,
+	idlClientStreamName:     idlClientStreamName,
+	idlClientTSName:         idlClientTSName,
+	idlClientTSTypesName:    idlClientTSTypesName,
+	swaggerTplName:          swaggerTplName,
+	mockTplName:             mockTplName,
+	mockMainTplName:         mockMainTplName,
+
+	middlewareRegistryTplName: middlewareRegistryTplName,
+	middlewareFactoryTplName:  middlewareFactoryTplName,
 }
 
 func IsDefaultPackageTpl(name string) bool {
@@ -105,10 +129,13 @@ import (
     {{- range $k, $v := .HandlerPackages}}
         {{$k}} "{{$v}}"
     {{- end}}
+    {{- if .MiddlewarePackage}}
+        middleware "{{.MiddlewarePackage}}"
+    {{- end}}
 )
 
 /*
- This file will register all the routes of the services in the master idl.
+ This file will register all the routes of the services in the master idl '{{$.IdlName}}'.
  And it will update automatically when you use the "update" command for the idl.
  So don't modify the contents of the file, or your code will be deleted when it is updated.
  */
@@ -120,10 +147,18 @@ import (
 
 {{define "G"}}
 {{- if ne .Handler ""}}
+	{{- if .Middlewares}}
+	{{- .GroupName}}.{{.HttpMethod}}("{{.Path}}", append(append(middleware.Get({{range $i, $mw := .Middlewares}}{{if $i}}, {{end}}"{{$mw}}"{{end}}), {{.HandlerMiddleware}}Mw()...), {{.Handler}})...)
+	{{- else}}
 	{{- .GroupName}}.{{.HttpMethod}}("{{.Path}}", append({{.HandlerMiddleware}}Mw(), {{.Handler}})...)
+	{{- end}}
 {{- end}}
 {{- if ne (len .Children) 0}}
+	{{- if .GroupMiddlewares}}
+{{.MiddleWare}} := {{template "g" .}}.Group("{{.Path}}", append(middleware.Get({{range $i, $mw := .GroupMiddlewares}}{{if $i}}, {{end}}"{{$mw}}"{{end}}), {{.GroupMiddleware}}Mw()...)...)
+	{{- else}}
 {{.MiddleWare}} := {{template "g" .}}.Group("{{.Path}}", {{.GroupMiddleware}}Mw()...)
+	{{- end}}
 {{- end}}
 {{- range $_, $router := .Children}}
 {{- if ne .Handler ""}}
@@ -223,6 +258,43 @@ func New{{.ServiceName}}Client(opt ...config.ClientOption) (*{{.ServiceName}}Cli
 	}, nil
 }
 		`,
+		},
+		{
+			Path:   defaultClientDir + sp + idlClientExtName,
+			Delims: [2]string{"{{", "}}"},
+			Body: `// Code generated by hertz generator once, it will not be updated, please put the custom code here.
+
+package {{.PackageName}}
+
+func init() {
+	// customize the default client of {{.ServiceName}}, eg:
+	// _ = ConfigDefaultClient(WithHeader(http.Header{"X-Caller": []string{"my-service"}}))
+}
+`,
+		},
+		{
+			Path:   defaultClientDir + sp + idlClientPolicyName,
+			Delims: [2]string{"{{", "}}"},
+			Body: `// Code generated by hertz generator.
+
+package {{.PackageName}}
+{{$timeout := false}}{{range .ClientMethods}}{{if .Timeout}}{{$timeout = true}}{{end}}{{end}}
+{{- if $timeout}}
+import "time"
+{{end}}
+func init() {
+{{- range .ClientMethods}}{{if or .Timeout .RetryTimes}}
+	methodPolicies[policyKey("{{.HTTPMethod}}", "{{.Path}}")] = MethodPolicy{
+		{{- if .Timeout}}
+		Timeout: {{.Timeout.Milliseconds}} * time.Millisecond,
+		{{- end}}
+		{{- if .RetryTimes}}
+		Retry: &RetryPolicy{MaxRetryTimes: {{.RetryTimes}}},
+		{{- end}}
+	}
+{{- end}}{{end}}
+}
+`,
 		},
 		{
 			Path:   defaultHandlerDir + sp + handlerSingleTplName,
@@ -243,6 +315,46 @@ func {{.Name}}(ctx context.Context, c *app.RequestContext) {
 
 	c.{{.Serializer}}(consts.StatusOK, resp)
 }
+`,
+		},
+		{
+			Path:   defaultHandlerDir + sp + streamHandlerTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   streamHandlerTpl,
+		},
+		{
+			Path:   defaultHandlerDir + sp + swaggerTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body: `// Code generated by hertz generator. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+var (
+	//go:embed {{.DocFile}}
+	{{.VarName}}Doc []byte
+	//go:embed {{.PageFile}}
+	{{.VarName}}Page []byte
+)
+
+// {{.Name}}OpenAPI returns the openapi document of {{.IdlName}}.
+// @router {{.DocPath}} [GET]
+func {{.Name}}OpenAPI(ctx context.Context, c *app.RequestContext) {
+	c.Data(consts.StatusOK, "application/yaml; charset=utf-8", {{.VarName}}Doc)
+}
+
+// {{.Name}}SwaggerUI returns the swagger ui of {{.IdlName}}.
+// @router {{.PagePath}} [GET]
+func {{.Name}}SwaggerUI(ctx context.Context, c *app.RequestContext) {
+	c.Data(consts.StatusOK, "text/html; charset=utf-8", {{.VarName}}Page)
+}
 `,
 		},
 		{
@@ -253,6 +365,61 @@ func {{.MiddleWare}}Mw() []app.HandlerFunc {
 	// your code...
 	return nil
 }
+`,
+		},
+		{
+			Path:   defaultRouterDir + sp + middlewareRegistryTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body: `// Code generated by hertz generator.
+
+package {{$.PackageName}}
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// factories maps the names in the "middleware" annotations of the idl to the factories of the middlewares,
+// the new names are added by the "update" command, please implement the factories.
+var factories = map[string]func() app.HandlerFunc{
+	` + insertPointNew + `
+{{- range .Middlewares}}
+	"{{.Name}}": {{.Func}},
+{{- end}}
+}
+
+// Get returns the middlewares of the names in order, the factories returning nil are skipped.
+func Get(names ...string) []app.HandlerFunc {
+	mws := make([]app.HandlerFunc, 0, len(names))
+	for _, name := range names {
+		factory, ok := factories[name]
+		if !ok {
+			panic(fmt.Sprintf("middleware '%s' is not registered", name))
+		}
+		if mw := factory(); mw != nil {
+			mws = append(mws, mw)
+		}
+	}
+	return mws
+}
+{{range .Middlewares}}
+// {{.Func}} creates the middleware "{{.Name}}" of the idl.
+func {{.Func}}() app.HandlerFunc {
+	// your code...
+	return nil
+}
+{{end}}`,
+		},
+		{
+			Path:   defaultRouterDir + sp + middlewareFactoryTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body: `
+// {{.Func}} creates the middleware "{{.Name}}" of the idl.
+func {{.Func}}() app.HandlerFunc {
+	// your code...
+	return nil
+}
 `,
 		},
 		{
@@ -265,14 +432,179 @@ func {{.MiddleWare}}Mw() []app.HandlerFunc {
 			Delims: [2]string{"{{", "}}"},
 			Body:   idlClientTpl,
 		},
+		{
+			Path:   defaultRouterDir + sp + idlClientStreamName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   idlClientStreamTpl,
+		},
+		{
+			Path:   defaultMockDir + sp + mockTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   mockTpl,
+		},
+		{
+			Path:   defaultMockDir + sp + mockMainTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body: `// Code generated by hertz generator once, it will not be updated, please put the custom code here.
+
+package main
+
+import (
+	"flag"
+
+	"github.com/cloudwego/hertz/pkg/app/server"
+)
+
+// mockRegisters register the routes of the mock handlers of every idl
+var mockRegisters []func(r *server.Hertz)
+
+// the mock server responds the examples of the responses after the requests are validated, run it by "go run ./mock"
+func main() {
+	addr := flag.String("addr", ":8888", "the address of the mock server")
+	flag.Parse()
+
+	h := server.Default(server.WithHostPorts(*addr))
+	for _, register := range mockRegisters {
+		register(h)
+	}
+	h.Spin()
+}
+`,
+		},
+		{
+			Path:   defaultRouterDir + sp + idlClientTSName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   idlClientTSTpl,
+		},
+		{
+			Path:   defaultRouterDir + sp + idlClientTSTypesName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   idlClientTSTypesTpl,
+		},
 	},
 }
 
+var streamHandlerTpl = `// Code generated by hertz generator.
+
+package {{.PackageName}}
+{{$sse := false}}{{$websocket := false}}
+{{- range .Methods}}{{if eq .Stream "sse"}}{{$sse = true}}{{else}}{{$websocket = true}}{{end}}{{end}}
+import (
+	"context"
+{{- if $sse}}
+	"encoding/json"
+{{- end}}
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+{{- if $sse}}
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/hertz-contrib/sse"
+{{- end}}
+{{- if $websocket}}
+	"github.com/hertz-contrib/websocket"
+{{- end}}
+
+{{- range $k, $v := .Imports}}
+	{{$k}} "{{$v.Package}}"
+{{- end}}
+)
+
+{{range $_, $MethodInfo := .Methods}}
+{{- if eq $MethodInfo.Stream "sse"}}
+{{$MethodInfo.Comment}}
+func {{$MethodInfo.Name}}(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req {{$MethodInfo.RequestTypeName}}
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	stream := New{{$MethodInfo.Name}}Stream(c)
+	// todo: send the responses by the stream, the stream is closed after the handler returns
+	err = stream.Send(new({{$MethodInfo.ReturnTypeName}}))
+	if err != nil {
+		hlog.CtxErrorf(ctx, "send the response of {{$MethodInfo.Name}} failed, err: %v", err)
+	}
+}
+
+// {{$MethodInfo.Name}}Stream sends the responses of {{$MethodInfo.Name}} as the server-sent events
+type {{$MethodInfo.Name}}Stream struct {
+	stream *sse.Stream
+}
+
+func New{{$MethodInfo.Name}}Stream(c *app.RequestContext) *{{$MethodInfo.Name}}Stream {
+	return &{{$MethodInfo.Name}}Stream{stream: sse.NewStream(c)}
+}
+
+// Send sends the response as the data of an event in json
+func (s *{{$MethodInfo.Name}}Stream) Send(resp *{{$MethodInfo.ReturnTypeName}}) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return s.stream.Publish(&sse.Event{Data: data})
+}
+{{- else}}
+// {{$MethodInfo.Name}}Upgrader upgrades the requests of {{$MethodInfo.Name}} to websocket, it can be customized, eg: CheckOrigin
+var {{$MethodInfo.Name}}Upgrader = websocket.HertzUpgrader{}
+
+{{$MethodInfo.Comment}}
+func {{$MethodInfo.Name}}(ctx context.Context, c *app.RequestContext) {
+	err := {{$MethodInfo.Name}}Upgrader.Upgrade(c, func(conn *websocket.Conn) {
+		stream := New{{$MethodInfo.Name}}Stream(conn)
+		// todo: receive the requests and send the responses by the stream
+		for {
+			_, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			if err = stream.Send(new({{$MethodInfo.ReturnTypeName}})); err != nil {
+				hlog.CtxErrorf(ctx, "send the response of {{$MethodInfo.Name}} failed, err: %v", err)
+				return
+			}
+		}
+	})
+	if err != nil {
+		hlog.CtxErrorf(ctx, "upgrade the request of {{$MethodInfo.Name}} to websocket failed, err: %v", err)
+	}
+}
+
+// {{$MethodInfo.Name}}Stream receives the requests and sends the responses of {{$MethodInfo.Name}} by websocket
+type {{$MethodInfo.Name}}Stream struct {
+	conn *websocket.Conn
+}
+
+func New{{$MethodInfo.Name}}Stream(conn *websocket.Conn) *{{$MethodInfo.Name}}Stream {
+	return &{{$MethodInfo.Name}}Stream{conn: conn}
+}
+
+// Recv receives a request in json, it returns an error when the connection is closed
+func (s *{{$MethodInfo.Name}}Stream) Recv() (*{{$MethodInfo.RequestTypeName}}, error) {
+	req := new({{$MethodInfo.RequestTypeName}})
+	if err := s.conn.ReadJSON(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Send sends the response in json
+func (s *{{$MethodInfo.Name}}Stream) Send(resp *{{$MethodInfo.ReturnTypeName}}) error {
+	return s.conn.WriteJSON(resp)
+}
+{{- end}}
+{{end}}
+`
+
 var hertzClientTpl = `// Code generated by hz.
 
 package {{.PackageName}}
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
@@ -283,6 +615,8 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	hertz_client "github.com/cloudwego/hertz/pkg/app/client"
 	"github.com/cloudwego/hertz/pkg/common/config"
@@ -325,11 +659,16 @@ type Option struct {
 type Options struct {
 	hostUrl               string
 	doer                  client.Doer
+	streamDoer            client.Doer
 	header                http.Header
 	requestBodyBind       bindRequestBodyFunc
 	responseResultDecider ResponseResultDecider
 	middlewares           []hertz_client.Middleware
 	clientOption          []config.ClientOption
+	timeout               time.Duration
+	retryPolicy           *RetryPolicy
+	circuitBreaker        CircuitBreaker
+	methodPolicies        map[string]MethodPolicy
 }
 
 func getOptions(ops ...Option) *Options {
@@ -354,7 +693,8 @@ func WithHertzClientMiddleware(mws ...hertz_client.Middleware) Option {
 	}}
 }
 
-// WithHertzClient is used to register a custom hertz client
+// WithHertzClient is used to register a custom hertz client, it is also used by the streaming methods,
+// so the response body stream should be enabled for them
 func WithHertzClient(client client.Doer) Option {
 	return Option{func(op *Options) {
 		op.doer = client
@@ -375,6 +715,37 @@ func WithResponseResultDecider(decider ResponseResultDecider) Option {
 	}}
 }
 
+// WithTimeout configures the timeout of every request, the timeout of the method policy has a higher priority
+func WithTimeout(timeout time.Duration) Option {
+	return Option{func(op *Options) {
+		op.timeout = timeout
+	}}
+}
+
+// WithRetry configures the retry policy of every request, the retry of the method policy has a higher priority
+func WithRetry(policy RetryPolicy) Option {
+	return Option{func(op *Options) {
+		op.retryPolicy = &policy
+	}}
+}
+
+// WithCircuitBreaker configures the circuit breaker, the requests are rejected with ErrCircuitOpen when the circuit is open
+func WithCircuitBreaker(cb CircuitBreaker) Option {
+	return Option{func(op *Options) {
+		op.circuitBreaker = cb
+	}}
+}
+
+// WithMethodPolicy configures the timeout and retry policy of the method, it overrides the policy from the idl annotations
+func WithMethodPolicy(httpMethod, path string, policy MethodPolicy) Option {
+	return Option{func(op *Options) {
+		if op.methodPolicies == nil {
+			op.methodPolicies = make(map[string]MethodPolicy)
+		}
+		op.methodPolicies[policyKey(httpMethod, path)] = policy
+	}}
+}
+
 func withHostUrl(HostUrl string) Option {
 	return Option{func(op *Options) {
 		op.hostUrl = HostUrl
@@ -385,20 +756,143 @@ func withHostUrl(HostUrl string) Option {
 type cli struct {
 	hostUrl               string
 	doer                  client.Doer
+	streamDoer            client.Doer
 	header                http.Header
 	bindRequestBody       bindRequestBodyFunc
 	responseResultDecider ResponseResultDecider
+	timeout               time.Duration
+	retryPolicy           *RetryPolicy
+	circuitBreaker        CircuitBreaker
+	methodPolicies        map[string]MethodPolicy
 
 	beforeRequest []beforeRequestFunc
 	afterResponse []afterResponseFunc
 }
 
+// Resilience of client
+var ErrCircuitOpen = errors.NewPublic("circuit breaker is open, the request is rejected")
+
+// RetryPolicy is the retry policy of the requests
+type RetryPolicy struct {
+	// MaxRetryTimes is the max times of the retry, the request is not retried if it is 0
+	MaxRetryTimes int
+	// Delay is the interval between the retries
+	Delay time.Duration
+	// RetryIf decides whether the request is retried, it retries the errors and the 5xx responses
+	// of the idempotent methods by default, the POST and PATCH requests are not retried
+	RetryIf func(rawResponse *protocol.Response, err error) bool
+}
+
+func (p *RetryPolicy) shouldRetry(attempt int, httpMethod string, rawResponse *protocol.Response, err error) bool {
+	if p == nil || attempt >= p.MaxRetryTimes {
+		return false
+	}
+	if p.RetryIf != nil {
+		return p.RetryIf(rawResponse, err)
+	}
+	return isIdempotent(httpMethod) && isFailure(rawResponse, err)
+}
+
+// isIdempotent returns true if the request of the method can be sent more than once safely
+func isIdempotent(httpMethod string) bool {
+	switch strings.ToUpper(httpMethod) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// MethodPolicy is the timeout and retry policy of a method, which is generated from the idl annotations
+type MethodPolicy struct {
+	Timeout time.Duration
+	Retry   *RetryPolicy
+}
+
+// methodPolicies is the policies from the idl annotations, the key is "{http method} {path}"
+var methodPolicies = map[string]MethodPolicy{}
+
+func policyKey(httpMethod, path string) string {
+	return strings.ToUpper(httpMethod) + " " + path
+}
+
+// CircuitBreaker decides whether the request of a method is allowed by the results of the previous requests
+type CircuitBreaker interface {
+	Allow(httpMethod, path string) bool
+	Record(httpMethod, path string, rawResponse *protocol.Response, err error)
+}
+
+// NewCircuitBreaker returns a circuit breaker of the methods, the circuit of a method is opened after
+// the failureThreshold consecutive failures. It is half-open after the openTimeout, only one request is
+// allowed to probe it, the circuit is closed if the probe succeeds, or opened again if the probe fails
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) CircuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		circuits:         make(map[string]*circuit),
+	}
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool // a request is probing the half-open circuit
+}
+
+type circuitBreaker struct {
+	sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+	circuits         map[string]*circuit
+}
+
+func (cb *circuitBreaker) Allow(httpMethod, path string) bool {
+	cb.Lock()
+	defer cb.Unlock()
+	c, ok := cb.circuits[policyKey(httpMethod, path)]
+	if !ok || c.failures < cb.failureThreshold {
+		return true
+	}
+	if c.probing || time.Now().Before(c.openUntil) {
+		return false
+	}
+	c.probing = true
+	return true
+}
+
+func (cb *circuitBreaker) Record(httpMethod, path string, rawResponse *protocol.Response, err error) {
+	cb.Lock()
+	defer cb.Unlock()
+	key := policyKey(httpMethod, path)
+	c, ok := cb.circuits[key]
+	if !ok {
+		c = &circuit{}
+		cb.circuits[key] = c
+	}
+	c.probing = false
+	if !isFailure(rawResponse, err) {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures >= cb.failureThreshold {
+		c.openUntil = time.Now().Add(cb.openTimeout)
+	}
+}
+
+// isFailure returns true if there is an error or the status code is 5xx
+func isFailure(rawResponse *protocol.Response, err error) bool {
+	return err != nil || rawResponse == nil || rawResponse.StatusCode() >= http.StatusInternalServerError
+}
+
 func (c *cli) Use(mws ...hertz_client.Middleware) error {
 	u, ok := c.doer.(use)
 	if !ok {
 		return errors.NewPublic("doer does not support middleware, choose the right doer.")
 	}
 	u.Use(mws...)
+	if s, ok := c.streamDoer.(use); ok && c.streamDoer != c.doer {
+		s.Use(mws...)
+	}
 	return nil
 }
 
@@ -415,14 +909,27 @@ func newClient(opts *Options) (*cli, error) {
 			return nil, err
 		}
 		opts.doer = cli
+		// the body of the response is read as a stream by the streaming methods
+		streamOption := append(append([]config.ClientOption{}, opts.clientOption...), hertz_client.WithResponseBodyStream(true))
+		if opts.streamDoer, err = hertz_client.NewClient(streamOption...); err != nil {
+			return nil, err
+		}
+	}
+	if opts.streamDoer == nil {
+		opts.streamDoer = opts.doer
 	}
 
 	c := &cli{
 		hostUrl:               opts.hostUrl,
 		doer:                  opts.doer,
+		streamDoer:            opts.streamDoer,
 		header:                opts.header,
 		bindRequestBody:       opts.requestBodyBind,
 		responseResultDecider: opts.responseResultDecider,
+		timeout:               opts.timeout,
+		retryPolicy:           opts.retryPolicy,
+		circuitBreaker:        opts.circuitBreaker,
+		methodPolicies:        opts.methodPolicies,
 		beforeRequest: []beforeRequestFunc{
 			parseRequestURL,
 			parseRequestHeader,
@@ -441,7 +948,31 @@ func newClient(opts *Options) (*cli, error) {
 	return c, nil
 }
 
+// policy returns the timeout and retry policy of the request, the method policy has a higher priority
+func (c *cli) policy(req *request) (time.Duration, *RetryPolicy) {
+	timeout, retry := c.timeout, c.retryPolicy
+	policy, ok := c.methodPolicies[policyKey(req.method, req.path)]
+	if !ok {
+		policy, ok = methodPolicies[policyKey(req.method, req.path)]
+	}
+	if ok {
+		if policy.Timeout > 0 {
+			timeout = policy.Timeout
+		}
+		if policy.Retry != nil {
+			retry = policy.Retry
+		}
+	}
+	return timeout, retry
+}
+
 func (c *cli) execute(req *request) (*response, error) {
+	timeout, retry := c.policy(req)
+	if timeout > 0 {
+		// the timeout of the request options has a higher priority
+		req.requestOptions = append([]config.RequestOption{config.WithRequestTimeout(timeout)}, req.requestOptions...)
+	}
+
 	var err error
 	for _, f := range c.beforeRequest {
 		if err = f(c, req); err != nil {
@@ -449,22 +980,135 @@ func (c *cli) execute(req *request) (*response, error) {
 		}
 	}
 
+	for attempt := 0; ; attempt++ {
+		if c.circuitBreaker != nil && !c.circuitBreaker.Allow(req.method, req.path) {
+			return nil, ErrCircuitOpen
+		}
+		response, err := c.do(req)
+		if c.circuitBreaker != nil {
+			c.circuitBreaker.Record(req.method, req.path, response.rawResponse, err)
+		}
+		if !retry.shouldRetry(attempt, req.method, response.rawResponse, err) {
+			if err != nil {
+				return response, err
+			}
+			return c.handleResponse(response)
+		}
+
+		if retry.Delay > 0 {
+			ctx := req.context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			select {
+			case <-ctx.Done():
+				return response, ctx.Err()
+			case <-time.After(retry.Delay):
+			}
+		}
+		// the raw request can not be reused after it is sent
+		if err = createHTTPRequest(c, req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *cli) do(req *request) (*response, error) {
 	if hostHeader := req.header.Get("Host"); hostHeader != "" {
 		req.rawRequest.Header.SetHost(hostHeader)
 	}
 
 	resp := protocol.Response{}
 
-	err = c.doer.Do(req.ctx, req.rawRequest, &resp)
+	err := c.doer.Do(req.ctx, req.rawRequest, &resp)
 
 	response := &response{
 		request:     req,
 		rawResponse: &resp,
 	}
 
-	if err != nil {
-		return response, err
+	return response, err
+}
+
+// stream sends the request and returns the response whose body is not read, the body is read as a stream
+// by the caller, the timeout and retry policies are not applied to the streams
+func (c *cli) stream(req *request) (*response, error) {
+	for _, f := range c.beforeRequest {
+		if err := f(c, req); err != nil {
+			return nil, err
+		}
+	}
+	if hostHeader := req.header.Get("Host"); hostHeader != "" {
+		req.rawRequest.Header.SetHost(hostHeader)
+	}
+	resp := &protocol.Response{}
+	if err := c.streamDoer.Do(req.ctx, req.rawRequest, resp); err != nil {
+		return nil, err
 	}
+	response := &response{
+		request:     req,
+		rawResponse: resp,
+	}
+	if c.responseResultDecider(resp.StatusCode(), resp) {
+		// the error response is not a stream, it is read entirely
+		if _, err := c.handleResponse(response); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("the stream is rejected with status code %d", resp.StatusCode())
+	}
+	return response, nil
+}
+
+// eventReader reads the server-sent events from the body stream of the response
+type eventReader struct {
+	reader      *bufio.Reader
+	rawResponse *protocol.Response
+}
+
+func newEventReader(rawResponse *protocol.Response) *eventReader {
+	return &eventReader{reader: bufio.NewReader(rawResponse.BodyStream()), rawResponse: rawResponse}
+}
+
+// next returns the name and the data of the next event, it returns io.EOF when the stream is finished
+func (r *eventReader) next() (event string, data []byte, err error) {
+	var buf bytes.Buffer
+	hasData := false
+	for {
+		line, err := r.reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		// an event is dispatched by the empty line, the incomplete event at the end of the stream is discarded
+		if line == "" && hasData {
+			return event, buf.Bytes(), nil
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if line == "" || strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			if hasData {
+				buf.WriteByte('\n')
+			}
+			buf.WriteString(value)
+			hasData = true
+		}
+	}
+}
+
+func (r *eventReader) close() error {
+	return r.rawResponse.CloseBodyStream()
+}
+
+func (c *cli) handleResponse(response *response) (*response, error) {
+	resp := response.rawResponse
 
 	body, err := resp.BodyE()
 	if err != nil {
@@ -546,6 +1190,7 @@ func (r *response) header() http.Header {
 type request struct {
 	client         *cli
 	url            string
+	path           string
 	method         string
 	queryEnumAsInt bool
 	queryParam     url.Values
@@ -671,13 +1316,23 @@ func (r *request) setRequestOption(option ...config.RequestOption) *request {
 func (r *request) execute(method, url string) (*response, error) {
 	r.method = method
 	r.url = url
+	r.path = url
 	return r.client.execute(r)
 }
 
+func (r *request) stream(method, url string) (*response, error) {
+	r.method = method
+	r.url = url
+	r.path = url
+	return r.client.stream(r)
+}
+
 func parseRequestURL(c *cli, r *request) error {
 	if len(r.pathParam) > 0 {
 		for p, v := range r.pathParam {
 			r.url = strings.Replace(r.url, ":"+p, url.PathEscape(v), -1)
+			// the catch-all parameter keeps the slashes in the value
+			r.url = strings.Replace(r.url, "*"+p, strings.ReplaceAll(url.PathEscape(v), "%2F", "/"), -1)
 		}
 	}
 
@@ -972,3 +1627,289 @@ func {{$MethodInfo.Name}}(context context.Context, req *{{$MethodInfo.RequestTyp
 }
 {{end}}
 `
+
+var idlClientStreamTpl = `// Code generated by hertz generator.
+
+package {{.PackageName}}
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/protocol"
+{{- range $k, $v := .Imports}}
+	{{$k}} "{{$v.Package}}"
+{{- end}}
+)
+
+// {{.ServiceName}}StreamClient is the client of the server streaming methods, which read the server-sent events
+type {{.ServiceName}}StreamClient interface {
+	{{range $_, $MethodInfo := .ClientMethods}}
+		{{$MethodInfo.Name}}(context context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...config.RequestOption) (stream *{{$MethodInfo.Name}}Stream, err error)
+	{{end}}
+}
+
+func New{{.ServiceName}}StreamClient(hostUrl string, ops ...Option) ({{.ServiceName}}StreamClient, error) {
+	opts := getOptions(append(ops, withHostUrl(hostUrl))...)
+	cli, err := newClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &{{.ServiceName}}Client{
+		client: cli,
+	}, nil
+}
+
+{{range $_, $MethodInfo := .ClientMethods}}
+// {{$MethodInfo.Name}}Stream receives the responses of {{$MethodInfo.Name}}, it should be closed after use
+type {{$MethodInfo.Name}}Stream struct {
+	reader *eventReader
+}
+
+// Recv returns the next response, it returns io.EOF when the stream is finished
+func (s *{{$MethodInfo.Name}}Stream) Recv() (*{{$MethodInfo.ReturnTypeName}}, error) {
+	_, data, err := s.reader.next()
+	if err != nil {
+		return nil, err
+	}
+	resp := &{{$MethodInfo.ReturnTypeName}}{}
+	if err = json.Unmarshal(data, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RawResponse returns the response of the stream, its body is read by Recv
+func (s *{{$MethodInfo.Name}}Stream) RawResponse() *protocol.Response {
+	return s.reader.rawResponse
+}
+
+func (s *{{$MethodInfo.Name}}Stream) Close() error {
+	return s.reader.close()
+}
+
+func (s *{{$.ServiceName}}Client) {{$MethodInfo.Name}}(context context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...config.RequestOption) (stream *{{$MethodInfo.Name}}Stream, err error) {
+	ret, err := s.client.r().
+		setContext(context).
+		setQueryParams(map[string]interface{}{
+			{{$MethodInfo.QueryParamsCode}}
+		}).
+		setPathParams(map[string]string{
+			{{$MethodInfo.PathParamsCode}}
+		}).
+		setHeaders(map[string]string{
+			{{$MethodInfo.HeaderParamsCode}}
+		}).
+		setHeader("Accept", "text/event-stream").
+		setFormParams(map[string]string{
+			{{$MethodInfo.FormValueCode}}
+		}).
+		setFormFileParams(map[string]string{
+			{{$MethodInfo.FormFileCode}}
+		}).
+		{{$MethodInfo.BodyParamsCode}}
+		setRequestOption(reqOpt...).
+		stream("{{if EqualFold $MethodInfo.HTTPMethod "Any"}}POST{{else}}{{ $MethodInfo.HTTPMethod }}{{end}}", "{{$MethodInfo.Path}}")
+	if err != nil {
+		return nil, err
+	}
+	return &{{$MethodInfo.Name}}Stream{reader: newEventReader(ret.rawResponse)}, nil
+}
+{{end}}
+`
+
+var idlClientTSTypesTpl = `// Code generated by hertz generator.
+{{range .TSTypes}}
+{{- if .Comment}}{{range Split .Comment "\n"}}
+// {{.}}{{end}}{{end}}
+{{- if .Values}}
+export enum {{.Name}} {
+{{- range .Values}}
+  {{.Name}} = {{.Value}},
+{{- end}}
+}
+{{else}}
+export interface {{.Name}} {
+{{- range .Fields}}
+{{- if .Comment}}{{range Split .Comment "\n"}}
+  // {{.}}{{end}}{{end}}
+  {{.Name}}{{if not .Required}}?{{end}}: {{.Type}};
+{{- end}}
+}
+{{end}}
+{{- end}}`
+
+var idlClientTSTpl = `// Code generated by hertz generator.
+
+import * as types from "./{{base .FilePath | trimSuffix ".ts"}}_types";
+
+export class HttpError extends Error {
+  readonly status: number;
+  readonly body: string;
+
+  constructor(status: number, body: string) {
+    super(` + "`http request failed with status ${status}: ${body}`" + `);
+    this.name = "HttpError";
+    this.status = status;
+    this.body = body;
+  }
+}
+
+export interface ClientOptions {
+  headers?: Record<string, string>; // headers of every request
+  init?: RequestInit; // default options of fetch, eg: credentials
+  fetch?: typeof fetch;
+}
+
+type Params = Record<string, unknown>;
+
+interface Request {
+  method: string;
+  path: string;
+  pathParams: Params;
+  queryParams: Params;
+  headers: Params;
+  formParams: Params;
+  body?: unknown;
+}
+
+export class {{.ServiceName}}Client {
+  private readonly baseUrl: string;
+  private readonly options: ClientOptions;
+
+  constructor(baseUrl: string = "{{.BaseDomain}}", options: ClientOptions = {}) {
+    this.baseUrl = baseUrl.replace(/\/+$/, "");
+    this.options = options;
+  }
+{{range .ClientMethods}}
+  async {{untitle .Name}}(req: types.{{.RequestTypeRawName}}, init: RequestInit = {}): Promise<types.{{.ReturnTypeRawName}}> {
+    return this.request<types.{{.ReturnTypeRawName}}>({
+      method: "{{if EqualFold .HTTPMethod "Any"}}POST{{else}}{{.HTTPMethod}}{{end}}",
+      path: "{{.Path}}",
+      pathParams: {
+{{- range .Params}}{{if eq .In "path"}}
+        {{printf "%q" .Name}}: req[{{printf "%q" .Field}}],
+{{- end}}{{end}}
+      },
+      queryParams: {
+{{- range .Params}}{{if eq .In "query"}}
+        {{printf "%q" .Name}}: req[{{printf "%q" .Field}}],
+{{- end}}{{end}}
+      },
+      headers: {
+{{- range .Params}}{{if eq .In "header"}}
+        {{printf "%q" .Name}}: req[{{printf "%q" .Field}}],
+{{- end}}{{end}}
+      },
+      formParams: {
+{{- range .Params}}{{if or (eq .In "form") (eq .In "file")}}
+        {{printf "%q" .Name}}: req[{{printf "%q" .Field}}],
+{{- end}}{{end}}
+      },
+{{- if .BodyParamsCode}}
+      body: req,
+{{- end}}
+    }, init);
+  }
+{{end}}
+  private async request<T>(req: Request, init: RequestInit): Promise<T> {
+    // the catch-all parameter keeps the slashes in it
+    const path = req.path.replace(/([:*])(\w+)/g, (_, kind: string, name: string) => {
+      const value = String(req.pathParams[name] ?? "");
+      return kind === "*" ? encodeURI(value) : encodeURIComponent(value);
+    });
+    const query = new URLSearchParams();
+    for (const [name, value] of Object.entries(req.queryParams)) {
+      for (const v of Array.isArray(value) ? value : [value]) {
+        if (v !== undefined && v !== null) {
+          query.append(name, String(v));
+        }
+      }
+    }
+    const headers = new Headers(this.options.headers);
+    new Headers(init.headers).forEach((value, name) => headers.set(name, value));
+    for (const [name, value] of Object.entries(req.headers)) {
+      if (value !== undefined && value !== null) {
+        headers.set(name, String(value));
+      }
+    }
+
+    let body: BodyInit | undefined;
+    const form = Object.entries(req.formParams).filter(([, value]) => value !== undefined && value !== null);
+    if (form.length > 0) {
+      const data = new FormData();
+      for (const [name, value] of form) {
+        data.append(name, value instanceof Blob ? value : String(value));
+      }
+      body = data;
+    } else if (req.body !== undefined && req.method !== "GET" && req.method !== "HEAD") {
+      headers.set("Content-Type", "application/json");
+      body = JSON.stringify(req.body);
+    }
+
+    const search = query.toString();
+    const doFetch = this.options.fetch ?? fetch;
+    const resp = await doFetch(this.baseUrl + path + (search ? "?" + search : ""), {
+      ...this.options.init,
+      ...init,
+      method: req.method,
+      headers,
+      body,
+    });
+    const text = await resp.text();
+    if (!resp.ok) {
+      throw new HttpError(resp.status, text);
+    }
+    return (text ? JSON.parse(text) : {}) as T;
+  }
+}
+`
+
+var mockTpl = `// Code generated by hertz generator. DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+{{- range $k, $v := .Imports}}
+	{{$k}} "{{$v.Package}}"
+{{- end}}
+)
+
+func init() {
+	mockRegisters = append(mockRegisters, register{{.Name}}Mock)
+}
+
+// register{{.Name}}Mock registers the mock handlers of {{.IdlName}}
+func register{{.Name}}Mock(r *server.Hertz) {
+{{- range .Methods}}
+	{{- if EqualFold .HTTPMethod "Any"}}
+	r.Any("{{.Path}}", mock{{$.Name}}{{.Name}})
+	{{- else}}
+	r.Handle("{{upper .HTTPMethod}}", "{{.Path}}", mock{{$.Name}}{{.Name}})
+	{{- end}}
+{{- end}}
+}
+{{range .Methods}}{{if .GenHandler}}
+// mock{{$.Name}}{{.Name}} validates the request of {{.Name}} and responds the example of the response
+func mock{{$.Name}}{{.Name}}(ctx context.Context, c *app.RequestContext) {
+	var req {{.RequestTypeName}}
+	if err := c.BindAndValidate(&req); err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := new({{.ReturnTypeName}})
+	if err := json.Unmarshal([]byte({{printf "%q" .ExampleResponse}}), resp); err != nil {
+		c.String(consts.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(consts.StatusOK, resp)
+}
+{{end}}{{end}}`
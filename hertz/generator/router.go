@@ -19,8 +19,13 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
 	"io/ioutil"
 	"math"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -33,10 +38,12 @@ import (
 )
 
 type Router struct {
-	FilePath        string
-	PackageName     string
-	HandlerPackages map[string]string // {{basename}}:{{import_path}}
-	Router          *RouterNode
+	FilePath          string
+	PackageName       string
+	IdlName           string
+	HandlerPackages   map[string]string // {{basename}}:{{import_path}}
+	MiddlewarePackage string            // import path of the middleware registry, it is set if any route has the middlewares
+	Router            *RouterNode
 }
 
 type RouterNode struct {
@@ -54,6 +61,9 @@ type RouterNode struct {
 	HandlerPackage      string
 	HandlerPackageAlias string
 	HttpMethod          string
+	Middlewares         []string // names of the middlewares of the idl annotations, they are created by the registry
+	ServiceMiddlewares  []string // names of the middlewares of the service of the route
+	GroupMiddlewares    []string // names of the service middlewares shared by all the routes of the group
 }
 
 type RegisterInfo struct {
@@ -234,6 +244,8 @@ func (routerNode *RouterNode) Insert(name string, method *HttpMethod, handlerTyp
 				}
 			}
 			c.HttpMethod = getHttpMethod(method.HTTPMethod)
+			c.Middlewares = method.Middlewares
+			c.ServiceMiddlewares = method.ServiceMiddlewares
 		}
 		if cur.Children == nil {
 			cur.Children = make([]*RouterNode, 0, 1)
@@ -403,14 +415,67 @@ func stringsIncludes(strs []string, str string) bool {
 	return false
 }
 
+// GroupServiceMiddlewares registers the service middlewares shared by all the routes of a group on the group,
+// the middlewares registered by the groups are removed from the routes, the applied are the ones of the parents
+func (routerNode *RouterNode) GroupServiceMiddlewares(applied []string) {
+	// the route of the node is registered on the group of the parent
+	if len(routerNode.Handler) != 0 {
+		routerNode.Middlewares = excludeMiddlewares(routerNode.Middlewares, applied)
+	}
+	if len(routerNode.Children) == 0 {
+		return
+	}
+	var shared []string
+	first := true
+	var collect func(node *RouterNode)
+	collect = func(node *RouterNode) {
+		if len(node.Handler) != 0 {
+			if first {
+				shared, first = append([]string{}, node.ServiceMiddlewares...), false
+			} else {
+				var kept []string
+				for _, mw := range shared {
+					if stringsIncludes(node.ServiceMiddlewares, mw) {
+						kept = append(kept, mw)
+					}
+				}
+				shared = kept
+			}
+		}
+		for _, c := range node.Children {
+			collect(c)
+		}
+	}
+	for _, c := range routerNode.Children {
+		collect(c)
+	}
+	routerNode.GroupMiddlewares = excludeMiddlewares(shared, applied)
+	applied = append(append([]string{}, applied...), routerNode.GroupMiddlewares...)
+	for _, c := range routerNode.Children {
+		c.GroupServiceMiddlewares(applied)
+	}
+}
+
+func excludeMiddlewares(mws, excluded []string) []string {
+	var out []string
+	for _, mw := range mws {
+		if !stringsIncludes(excluded, mw) {
+			out = append(out, mw)
+		}
+	}
+	return out
+}
+
 func (pkgGen *HttpPackageGenerator) genRouter(pkg *HttpPackage, root *RouterNode, handlerPackage, routerDir, routerPackage string) error {
 	err := root.DyeGroupName(pkgGen.SnakeStyleMiddleware)
 	if err != nil {
 		return err
 	}
+	root.GroupServiceMiddlewares(nil)
 	router := Router{
 		FilePath:    filepath.Join(routerDir, util.BaseNameAndTrim(pkg.IdlName)+".go"),
 		PackageName: filepath.Base(routerDir),
+		IdlName:     pkg.IdlName,
 		HandlerPackages: map[string]string{
 			util.BaseName(handlerPackage, ""): handlerPackage,
 		},
@@ -423,6 +488,9 @@ func (pkgGen *HttpPackageGenerator) genRouter(pkg *HttpPackage, root *RouterNode
 			logs.Infof("handler package: %s -- %s", node.HandlerPackageAlias, node.HandlerPackage)
 			handlerMap[node.HandlerPackageAlias] = node.HandlerPackage
 		}
+		if len(node.Middlewares) != 0 || len(node.GroupMiddlewares) != 0 {
+			router.MiddlewarePackage = util.SubPackage(pkgGen.ProjPackage, filepath.Join(pkgGen.Module, pkgGen.middlewareDir()))
+		}
 		return nil
 	}
 	root.DFS(0, hook)
@@ -466,6 +534,9 @@ func (pkgGen *HttpPackageGenerator) genRouter(pkg *HttpPackage, root *RouterNode
 	if err := pkgGen.updateMiddlewareReg(router, middlewareTplName, filepath.Join(routerDir, "middleware.go")); err != nil {
 		return fmt.Errorf("generate middleware %s failed, err: %v", filepath.Join(routerDir, "middleware.go"), err.Error())
 	}
+	if err := pkgGen.updateMiddlewareRegistry(pkg); err != nil {
+		return fmt.Errorf("generate middleware registry %s failed, err: %v", filepath.Join(pkgGen.middlewareDir(), "registry.go"), err.Error())
+	}
 
 	if err := pkgGen.updateRegister(routerPackage, pkgGen.RouterDir, pkg.Package); err != nil {
 		return fmt.Errorf("update register for %s failed, err: %v", filepath.Join(routerDir, registerTplName), err.Error())
@@ -545,3 +616,189 @@ func convertToMiddlewareName(path string) string {
 	path = strings.ToLower(path)
 	return path
 }
+
+// Route is a route of a method of the idl, it is used to detect the conflicts of the routes at generation time,
+// instead of the panic when the routes are registered by hertz
+type Route struct {
+	HttpMethod string
+	Path       string
+	Method     string // name of the method of the idl
+	IdlName    string // the idl of the method, or the router file if the idl is unknown
+}
+
+func (r Route) String() string {
+	return fmt.Sprintf("route '%s %s' of method '%s' in '%s'", r.HttpMethod, r.Path, r.Method, r.IdlName)
+}
+
+// Conflicts reports whether the routes match the same requests, the names of the path parameters are ignored
+// and the "Any" route matches all the http methods
+func (r Route) Conflicts(o Route) bool {
+	if !strings.EqualFold(r.HttpMethod, o.HttpMethod) && !strings.EqualFold(r.HttpMethod, "Any") && !strings.EqualFold(o.HttpMethod, "Any") {
+		return false
+	}
+	return RoutePattern(r.Path) == RoutePattern(o.Path)
+}
+
+// RoutePattern returns the path with the names of the parameters dropped, the paths of the same pattern match the same requests
+func RoutePattern(p string) string {
+	segs := strings.Split(p, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") {
+			segs[i] = ":"
+		} else if strings.HasPrefix(seg, "*") {
+			segs[i] = "*"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// checkRouteConflicts checks the routes of the idl against each other and the routes of the other idls,
+// which are parsed from the router files generated before
+func (pkgGen *HttpPackageGenerator) checkRouteConflicts(pkg *HttpPackage, routerDir string) error {
+	if pkgGen.tplsInfo[routerTplName].Disable {
+		return nil
+	}
+	routerFile := filepath.Join(routerDir, util.BaseNameAndTrim(pkg.IdlName)+".go")
+	registered, err := loadRegisteredRoutes(filepath.Join(pkgGen.Module, pkgGen.RouterDir), filepath.Join(pkgGen.Module, routerFile))
+	if err != nil {
+		return err
+	}
+	for _, s := range pkg.Services {
+		for _, m := range s.Methods {
+			route := Route{HttpMethod: getHttpMethod(m.HTTPMethod), Path: m.Path, Method: m.Name, IdlName: pkg.IdlName}
+			for _, r := range registered {
+				if route.Conflicts(r) {
+					return fmt.Errorf("%s conflicts with %s", route, r)
+				}
+			}
+			registered = append(registered, route)
+		}
+	}
+	return nil
+}
+
+// regRouterIdl matches the idl in the comment of the router file
+var regRouterIdl = regexp.MustCompile(`master idl '([^']+)'`)
+
+// loadRegisteredRoutes parses the routes registered by the "Register" of the router files in the router dir, except the skipped one
+func loadRegisteredRoutes(routerDir, skip string) ([]Route, error) {
+	isExist, err := util.PathExist(routerDir)
+	if err != nil || !isExist {
+		return nil, err
+	}
+	var routes []Route
+	err = filepath.WalkDir(routerDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() || filepath.Ext(name) != ".go" || name == registerTplName || name == middlewareTplName || filepath.Clean(p) == filepath.Clean(skip) {
+			return nil
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), p, nil, parser.ParseComments)
+		if err != nil {
+			logs.Warnf("parse router file '%s' failed, the routes in it are not checked, err: %v", p, err)
+			return nil
+		}
+		idlName := p
+		for _, c := range file.Comments {
+			if match := regRouterIdl.FindStringSubmatch(c.Text()); match != nil {
+				idlName = match[1]
+				break
+			}
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "Register" || fn.Body == nil || len(fn.Type.Params.List) != 1 || len(fn.Type.Params.List[0].Names) != 1 {
+				continue
+			}
+			groups := map[string]string{fn.Type.Params.List[0].Names[0].Name: ""}
+			routes = append(routes, registeredRoutes(fn.Body.List, groups, idlName)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load the routes in '%s' failed, err: %v", routerDir, err)
+	}
+	return routes, nil
+}
+
+// registeredRoutes resolves the routes of the statements of the router template, such as:
+//
+//	_user := root.Group("/user", _userMw()...)
+//	_user.GET("/:id", append(_getuserMw(), user.GetUser)...)
+func registeredRoutes(stmts []ast.Stmt, groups map[string]string, idlName string) []Route {
+	var routes []Route
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *ast.BlockStmt:
+			routes = append(routes, registeredRoutes(st.List, groups, idlName)...)
+		case *ast.AssignStmt:
+			if len(st.Lhs) != 1 || len(st.Rhs) != 1 {
+				continue
+			}
+			lhs, ok := st.Lhs[0].(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if group, sub, _, ok := routerCall(st.Rhs[0], groups); ok && sub == "Group" {
+				groups[lhs.Name] = group
+			}
+		case *ast.ExprStmt:
+			group, method, call, ok := routerCall(st.X, groups)
+			if !ok || method == "Group" || len(call.Args) == 0 {
+				continue
+			}
+			route := Route{HttpMethod: method, Path: group, IdlName: idlName}
+			// the handler is the last argument of "append"
+			if args, ok := call.Args[len(call.Args)-1].(*ast.CallExpr); ok && len(args.Args) != 0 {
+				if sel, ok := args.Args[len(args.Args)-1].(*ast.SelectorExpr); ok {
+					route.Method = sel.Sel.Name
+				}
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// routerCall resolves the call of "{{group}}.{{method}}("{{path}}", ...)", the returned path is joined with the path of the group
+func routerCall(expr ast.Expr, groups map[string]string) (string, string, *ast.CallExpr, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", "", nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", nil, false
+	}
+	recv, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", "", nil, false
+	}
+	group, ok := groups[recv.Name]
+	if !ok {
+		return "", "", nil, false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", "", nil, false
+	}
+	rel, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", "", nil, false
+	}
+	return joinRoutePath(group, rel), sel.Sel.Name, call, true
+}
+
+// joinRoutePath joins the paths like the groups of hertz, the trailing slash is kept
+func joinRoutePath(group, rel string) string {
+	if rel == "" {
+		return group
+	}
+	p := path.Join("/", group, rel)
+	if strings.HasSuffix(rel, "/") && !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	return p
+}
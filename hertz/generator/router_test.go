@@ -0,0 +1,182 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckRouteConflicts(t *testing.T) {
+	pkgGen := &HttpPackageGenerator{
+		ProjPackage: "example.com/demo",
+		RouterDir:   "biz/router",
+		Module:      t.TempDir(),
+	}
+	if err := pkgGen.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the router of the other idl generated before
+	order := &HttpPackage{IdlName: "order/order.proto", Package: "order", Services: []*Service{{
+		Name: "OrderService",
+		Methods: []*HttpMethod{
+			{Name: "GetOrder", HTTPMethod: "GET", Path: "/order/:id"},
+			{Name: "ListOrder", HTTPMethod: "Any", Path: "/orders/"},
+		},
+	}}}
+	root := NewRouterTree()
+	for _, m := range order.Services[0].Methods {
+		if err := root.Update(m, "order.OrderService", "", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pkgGen.genRouter(order, root, "example.com/demo/biz/handler/order", "biz/router/order", "example.com/demo/biz/router/order"); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range pkgGen.Files() {
+		path := filepath.Join(pkgGen.Module, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f.Content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		methods []*HttpMethod
+		err     string
+	}{
+		{
+			methods: []*HttpMethod{{Name: "GetUser", HTTPMethod: "GET", Path: "/user/:id"}, {Name: "UpdateUser", HTTPMethod: "POST", Path: "/user/:id"}},
+		},
+		{
+			methods: []*HttpMethod{{Name: "GetUser", HTTPMethod: "GET", Path: "/user/:id"}, {Name: "QueryUser", HTTPMethod: "GET", Path: "/user/:name"}},
+			err:     "route 'GET /user/:name' of method 'QueryUser' in 'user.proto' conflicts with route 'GET /user/:id' of method 'GetUser' in 'user.proto'",
+		},
+		{
+			methods: []*HttpMethod{{Name: "GetUserOrder", HTTPMethod: "GET", Path: "/order/:uid"}},
+			err:     "route 'GET /order/:uid' of method 'GetUserOrder' in 'user.proto' conflicts with route 'GET /order/:id' of method 'GetOrder' in 'order/order.proto'",
+		},
+		{
+			methods: []*HttpMethod{{Name: "ListUserOrder", HTTPMethod: "DELETE", Path: "/orders/"}},
+			err:     "route 'DELETE /orders/' of method 'ListUserOrder' in 'user.proto' conflicts with route 'Any /orders/' of method 'ListOrder' in 'order/order.proto'",
+		},
+	}
+	for _, c := range cases {
+		pkg := &HttpPackage{IdlName: "user.proto", Package: "user", Services: []*Service{{Name: "UserService", Methods: c.methods}}}
+		err := pkgGen.checkRouteConflicts(pkg, "biz/router/user")
+		if c.err == "" && err != nil {
+			t.Errorf("want no conflict, got: %v", err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("want error %q, got: %v", c.err, err)
+		}
+	}
+
+	// the routes of the idl itself are regenerated, they are not the conflicts
+	if err := pkgGen.checkRouteConflicts(order, "biz/router/order"); err != nil {
+		t.Errorf("want no conflict for the router of the idl itself, got: %v", err)
+	}
+}
+
+func TestGenRouterMiddlewares(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(dir)
+
+	gen := func(methods ...*HttpMethod) map[string]string {
+		pkgGen := &HttpPackageGenerator{ProjPackage: "example.com/demo", RouterDir: "biz/router"}
+		if err := pkgGen.Init(); err != nil {
+			t.Fatal(err)
+		}
+		pkg := &HttpPackage{IdlName: "user.proto", Package: "user", Services: []*Service{{Name: "UserService", Methods: methods}}}
+		root := NewRouterTree()
+		for _, m := range methods {
+			if err := root.Update(m, "user.UserService", "", false); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := pkgGen.genRouter(pkg, root, "example.com/demo/biz/handler/user", "biz/router/user", "example.com/demo/biz/router/user"); err != nil {
+			t.Fatal(err)
+		}
+		files := make(map[string]string)
+		for _, f := range pkgGen.Files() {
+			if _, err := format.Source([]byte(f.Content)); err != nil {
+				t.Errorf("format '%s' failed, err: %v\n%s", f.Path, err, f.Content)
+			}
+			files[f.Path] = f.Content
+			if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(f.Path, []byte(f.Content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return files
+	}
+
+	// the service middleware "trace" is shared by all the routes of the group "/user", it is registered on the group
+	files := gen(
+		&HttpMethod{Name: "GetUser", HTTPMethod: "GET", Path: "/user/:id", Middlewares: []string{"trace", "auth", "rate-limit"}, ServiceMiddlewares: []string{"trace"}},
+		&HttpMethod{Name: "ListUser", HTTPMethod: "GET", Path: "/user/list", Middlewares: []string{"trace"}, ServiceMiddlewares: []string{"trace"}},
+		&HttpMethod{Name: "Ping", HTTPMethod: "GET", Path: "/ping"},
+	)
+	router := files[filepath.Join("biz/router/user", "user.go")]
+	for _, want := range []string{
+		`middleware "example.com/demo/biz/middleware"`,
+		`_user := root.Group("/user", append(middleware.Get("trace"), _userMw()...)...)`,
+		`append(append(middleware.Get("auth", "rate-limit"), _getuserMw()...), user.UserService.GetUser)...)`,
+		`.GET("/list", append(_listuserMw(), user.UserService.ListUser)...)`,
+		`.GET("/ping", append(_pingMw(), user.UserService.Ping)...)`,
+	} {
+		if !strings.Contains(router, want) {
+			t.Errorf("want %q in the router:\n%s", want, router)
+		}
+	}
+	registry := files[filepath.Join("biz/middleware", "registry.go")]
+	for _, want := range []string{`"auth": Auth,`, `"rate-limit": RateLimit,`, "func RateLimit() app.HandlerFunc {"} {
+		if !strings.Contains(registry, want) {
+			t.Errorf("want %q in the registry:\n%s", want, registry)
+		}
+	}
+
+	// the new middlewares are added to the registry, and the implemented ones are kept
+	implemented := strings.Replace(registry, "func Auth() app.HandlerFunc {\n\t// your code...\n\treturn nil", "func Auth() app.HandlerFunc {\n\treturn jwt()", 1)
+	if err = os.WriteFile(filepath.Join("biz/middleware", "registry.go"), []byte(implemented), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files = gen(&HttpMethod{Name: "GetUser", HTTPMethod: "GET", Path: "/user/:id", Middlewares: []string{"auth", "audit"}})
+	registry = files[filepath.Join("biz/middleware", "registry.go")]
+	for _, want := range []string{"return jwt()", `"audit": Audit,`, "func Audit() app.HandlerFunc {", `"rate-limit": RateLimit,`} {
+		if !strings.Contains(registry, want) {
+			t.Errorf("want %q in the updated registry:\n%s", want, registry)
+		}
+	}
+	if strings.Count(registry, `"auth": Auth,`) != 1 {
+		t.Errorf("want the registered middleware once:\n%s", registry)
+	}
+}
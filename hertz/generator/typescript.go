@@ -0,0 +1,84 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generator
+
+import (
+	"path/filepath"
+
+	"github.com/cloudwego/hertz/cmd/hz/util"
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+)
+
+// ClientLangTS generates the typescript client instead of the go client for "client" command
+const ClientLangTS = "ts"
+
+// TSType is an interface or an enum of the typescript client, it is mapped from a model of the idl
+type TSType struct {
+	Name    string
+	Comment string
+	Fields  []TSField     // properties of the interface
+	Values  []TSEnumValue // values of the enum, the type is an enum if it is not empty
+}
+
+type TSField struct {
+	Name     string // name of the property, it is quoted if it is not an identifier
+	Type     string
+	Comment  string
+	Required bool
+}
+
+type TSEnumValue struct {
+	Name  string
+	Value int32
+}
+
+// genTSClient generates the fetch-based client and the types of the requests and responses for every service
+func (pkgGen *HttpPackageGenerator) genTSClient(pkg *HttpPackage, clientDir string) error {
+	for _, s := range pkg.Services {
+		cliDir := util.SubDir(clientDir, util.ToSnakeCase(s.Name))
+		if len(pkgGen.ForceClientDir) != 0 {
+			cliDir = pkgGen.ForceClientDir
+		}
+		baseDomain := s.BaseDomain
+		if len(pkgGen.BaseDomain) != 0 {
+			baseDomain = pkgGen.BaseDomain
+		}
+		methods := make([]*ClientMethod, 0, len(s.ClientMethods))
+		for _, m := range s.ClientMethods {
+			if m.Stream != "" {
+				logs.Warnf("the typescript client of the streaming method '%s' is not generated", m.Name)
+				continue
+			}
+			methods = append(methods, m)
+		}
+		client := ClientFile{
+			FilePath:      filepath.Join(cliDir, util.ToSnakeCase(s.Name)+".ts"),
+			ServiceName:   util.ToCamelCase(s.Name),
+			ClientMethods: methods,
+			BaseDomain:    baseDomain,
+			TSTypes:       s.TSTypes,
+		}
+		typesPath := filepath.Join(cliDir, util.ToSnakeCase(s.Name)+"_types.ts")
+		if err := pkgGen.TemplateGenerator.Generate(client, idlClientTSTypesName, typesPath, false); err != nil {
+			return err
+		}
+		if err := pkgGen.TemplateGenerator.Generate(client, idlClientTSName, client.FilePath, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
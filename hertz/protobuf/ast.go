@@ -19,7 +19,6 @@ package protobuf
 import (
 	"fmt"
 	"path/filepath"
-	"sort"
 	"strings"
 
 	"github.com/cloudwego/hertz/cmd/hz/generator/model"
@@ -135,20 +134,19 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 		if val, ok := servicePathAnno.(string); ok {
 			servicePath = val
 		}
+		// the middlewares of the service are applied to all the routes of the service before the ones of the method
+		serviceMws, err := getMiddlewares(s.GetOptions(), "service '"+s.GetName()+"'")
+		if err != nil {
+			return nil, err
+		}
 		for _, m := range ms {
-			rs := getAllOptions(HttpMethodOptions, m.GetOptions())
-			if len(rs) == 0 {
-				continue
+			httpOpts, err := getHttpRoutes(m.GetOptions(), ast.GetPackage()+"."+s.GetName()+"."+m.GetName())
+			if err != nil {
+				return nil, err
 			}
-			httpOpts := httpOptions{}
-			for k, v := range rs {
-				httpOpts = append(httpOpts, httpOption{
-					method: k,
-					path:   v.(string),
-				})
+			if len(httpOpts) == 0 {
+				continue
 			}
-			// turn the map into a slice and sort it to make sure getting the results in the same order every time
-			sort.Sort(httpOpts)
 
 			var handlerOutDir string
 			genPath := getCompatibleAnnotation(m.GetOptions(), api.E_HandlerPath, api.E_HandlerPathCompatible)
@@ -196,13 +194,28 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 				serializer = sv.(string)
 			}
 
+			methodMws, err := getMiddlewares(m.GetOptions(), "method '"+s.GetName()+"."+m.GetName()+"'")
+			if err != nil {
+				return nil, err
+			}
+
 			method := &generator.HttpMethod{
-				Name:       util.CamelString(m.GetName()),
-				HTTPMethod: httpOpts[0].method,
-				Path:       httpOpts[0].path,
-				Serializer: serializer,
-				OutputDir:  handlerOutDir,
-				GenHandler: true,
+				Name:               util.CamelString(m.GetName()),
+				HTTPMethod:         httpOpts[0].method,
+				Path:               httpOpts[0].path,
+				Serializer:         serializer,
+				OutputDir:          handlerOutDir,
+				GenHandler:         true,
+				Middlewares:        generator.MergeMiddlewares(append([]string{}, serviceMws...), methodMws...),
+				ServiceMiddlewares: serviceMws,
+			}
+			if m.GetClientStreaming() {
+				method.Stream = generator.StreamWebSocket
+				if httpOpts[0].method != "GET" {
+					logs.Warnf("the websocket of the streaming method '%s' can only be opened by 'GET', but it is routed by '%s'", m.GetName(), httpOpts[0].method)
+				}
+			} else if m.GetServerStreaming() {
+				method.Stream = generator.StreamSSE
 			}
 
 			goOptMapAlias := make(map[string]string, 1)
@@ -253,12 +266,20 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 			}
 
 			if cmdType == meta.CmdClient {
+				if method.Stream == generator.StreamWebSocket {
+					logs.Warnf("the client of the websocket method '%s' is not generated, please use a websocket client", m.GetName())
+					continue
+				}
 				clientMethod := &generator.ClientMethod{}
 				clientMethod.HttpMethod = method
 				err := parseAnnotationToClient(clientMethod, gen, ast, m)
 				if err != nil {
 					return nil, err
 				}
+				err = parseClientPolicy(clientMethod, m)
+				if err != nil {
+					return nil, err
+				}
 				clientMethods = append(clientMethods, clientMethod)
 			}
 		}
@@ -281,6 +302,49 @@ func getCompatibleAnnotation(options proto.Message, anno, compatibleAnno *protoi
 	return nil
 }
 
+// the method options of the client policy in the api.proto of cwgo, they are not in the api.pb.go of hz,
+// so the extensions are built from the descriptors of the idl
+const (
+	clientTimeoutOption protoreflect.FullName = "api.timeout"
+	clientRetryOption   protoreflect.FullName = "api.retry"
+)
+
+// isClientPolicyExtension reports whether the extension is the client policy of the methods
+func isClientPolicyExtension(x protoreflect.FieldDescriptor) bool {
+	if name := x.FullName(); name != clientTimeoutOption && name != clientRetryOption {
+		return false
+	}
+	return x.ContainingMessage().FullName() == "google.protobuf.MethodOptions" && x.Kind() == protoreflect.StringKind
+}
+
+// parseClientPolicy parses the timeout and retry annotations of the method, such as:
+//
+//	rpc Hello(Req) returns(Resp) {
+//	  option (api.get) = "/hello";
+//	  option (api.timeout) = "500ms";
+//	  option (api.retry) = "2";
+//	}
+func parseClientPolicy(clientMethod *generator.ClientMethod, m *descriptorpb.MethodDescriptorProto) error {
+	resolved := resolveOptions(m.GetOptions(), "method '"+m.GetName()+"'")
+	get := func(name protoreflect.FullName) (string, bool) {
+		v, ok := getOption(resolved, name)
+		return v.String(), ok
+	}
+
+	var err error
+	if val, ok := get(clientTimeoutOption); ok {
+		if clientMethod.Timeout, err = generator.ParseTimeout(val, m.GetName()); err != nil {
+			return err
+		}
+	}
+	if val, ok := get(clientRetryOption); ok {
+		if clientMethod.RetryTimes, err = generator.ParseRetryTimes(val, m.GetName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen.Plugin, ast *descriptorpb.FileDescriptorProto, m *descriptorpb.MethodDescriptorProto) error {
 	file, exist := gen.FilesByPath[ast.GetName()]
 	if !exist {
@@ -307,12 +371,24 @@ func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen
 			queryAnnos := proto.GetExtension(f.Desc.Options(), api.E_Query)
 			val := checkSnakeName(queryAnnos.(string))
 			clientMethod.QueryParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
+			addClientParam(clientMethod, generator.ParamInQuery, val, f)
+		}
+
+		if name, ok := httpRulePathTag(f.Desc); ok && hasPathParam(clientMethod.Path, name) {
+			hasAnnotation = true
+			addClientParam(clientMethod, generator.ParamInPath, name, f)
+			if isStringFieldType {
+				clientMethod.PathParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", name, f.GoName)
+			} else {
+				clientMethod.PathParamsCode += fmt.Sprintf("%q: fmt.Sprint(req.Get%s()),\n", name, f.GoName)
+			}
 		}
 
 		if proto.HasExtension(f.Desc.Options(), api.E_Path) {
 			hasAnnotation = true
 			pathAnnos := proto.GetExtension(f.Desc.Options(), api.E_Path)
 			val := pathAnnos.(string)
+			addClientParam(clientMethod, generator.ParamInPath, val, f)
 			if isStringFieldType {
 				clientMethod.PathParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
 			} else {
@@ -324,6 +400,7 @@ func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen
 			hasAnnotation = true
 			headerAnnos := proto.GetExtension(f.Desc.Options(), api.E_Header)
 			val := headerAnnos.(string)
+			addClientParam(clientMethod, generator.ParamInHeader, val, f)
 			if isStringFieldType {
 				clientMethod.HeaderParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
 			} else {
@@ -335,6 +412,7 @@ func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen
 			hasAnnotation = true
 			hasFormAnnotation = true
 			val := checkSnakeName(formAnnos.(string))
+			addClientParam(clientMethod, generator.ParamInForm, val, f)
 			if isStringFieldType {
 				clientMethod.FormValueCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
 			} else {
@@ -352,6 +430,7 @@ func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen
 			hasFormAnnotation = true
 			val := fileAnnos.(string)
 			clientMethod.FormFileCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
+			addClientParam(clientMethod, generator.ParamInFile, val, f)
 		}
 		if proto.HasExtension(f.Desc.Options(), api.E_Cookie) {
 			hasAnnotation = true
@@ -359,12 +438,20 @@ func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen
 		}
 		if !hasAnnotation && strings.EqualFold(clientMethod.HTTPMethod, "get") {
 			clientMethod.QueryParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", checkSnakeName(string(f.Desc.Name())), f.GoName)
+			addClientParam(clientMethod, generator.ParamInQuery, checkSnakeName(string(f.Desc.Name())), f)
 		}
 	}
 	clientMethod.BodyParamsCode = meta.SetBodyParam
 	if hasBodyAnnotation && hasFormAnnotation {
 		clientMethod.FormValueCode = ""
 		clientMethod.FormFileCode = ""
+		params := clientMethod.Params[:0]
+		for _, p := range clientMethod.Params {
+			if p.In != generator.ParamInForm && p.In != generator.ParamInFile {
+				params = append(params, p)
+			}
+		}
+		clientMethod.Params = params
 	}
 	if !hasBodyAnnotation && hasFormAnnotation {
 		clientMethod.BodyParamsCode = ""
@@ -373,6 +460,14 @@ func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen
 	return nil
 }
 
+func addClientParam(clientMethod *generator.ClientMethod, in, name string, f *protogen.Field) {
+	clientMethod.Params = append(clientMethod.Params, generator.ClientParam{
+		In:    in,
+		Name:  name,
+		Field: jsonName(f.Desc),
+	})
+}
+
 func getMethod(file *protogen.File, m *descriptorpb.MethodDescriptorProto) (*protogen.Method, error) {
 	for _, f := range file.Services {
 		for _, method := range f.Methods {
@@ -656,6 +751,9 @@ func parseDefaultValue(typ descriptorpb.FieldDescriptorProto_Type, val string) (
 }
 
 func isPointer(f *descriptorpb.FieldDescriptorProto, isProto3 bool) bool {
+	if wkt, ok := getWellKnownModelType(f); ok {
+		return wkt.pointer
+	}
 	if f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE || f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_BYTES {
 		return false
 	}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hu-1996/cwgo/hertz/generator"
+)
+
+func TestParseClientPolicy(t *testing.T) {
+	gen := loadTestIDL(t, "client_policy.proto")
+	for _, x := range gen.FilesByPath["api.proto"].Extensions {
+		// 50331~50360 is reserved by hz
+		if name, num := x.Desc.Name(), x.Desc.Number(); (name == "timeout" || name == "retry") && num >= 50331 && num <= 50360 {
+			t.Fatalf("want the option number of '%s' out of the range of hz, got %d", name, num)
+		}
+	}
+
+	methods := gen.FilesByPath["client_policy.proto"].Proto.GetService()[0].GetMethod()
+	clientMethod := &generator.ClientMethod{}
+	if err := parseClientPolicy(clientMethod, methods[0]); err != nil {
+		t.Fatal(err)
+	}
+	if clientMethod.Timeout != 1500*time.Millisecond || clientMethod.RetryTimes != 3 {
+		t.Errorf("want timeout 1.5s and retry 3, got %v and %d", clientMethod.Timeout, clientMethod.RetryTimes)
+	}
+	for _, m := range methods[1:] {
+		if err := parseClientPolicy(&generator.ClientMethod{}, m); err == nil {
+			t.Errorf("want error for the options of '%s'", m.GetName())
+		}
+	}
+
+	// the options are ignored if the idl does not import the extensions
+	_ = collectExtensions(nil)
+	clientMethod = &generator.ClientMethod{}
+	if err := parseClientPolicy(clientMethod, methods[0]); err != nil || clientMethod.Timeout != 0 {
+		t.Errorf("want the options ignored, got %v, err: %v", clientMethod.Timeout, err)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// idlExtensions resolves the options which are not in the api.pb.go of hz, such as the validate rules, the examples,
+// the middlewares and the client policy. The go packages of them are not linked, so the options are unknown fields
+// and the extensions are built from the descriptors of the idl.
+var idlExtensions = new(protoregistry.Types)
+
+// idlExtensionFilters select the extensions imported by the idl which are resolved
+var idlExtensionFilters = []func(x protoreflect.FieldDescriptor) bool{
+	isValidateExtension,
+	isExampleExtension,
+	isMiddlewareExtension,
+	isClientPolicyExtension,
+}
+
+// collectExtensions registers the extensions imported by the idl, it is called once for each request
+func collectExtensions(files []*protogen.File) error {
+	idlExtensions = new(protoregistry.Types)
+	for _, f := range files {
+		for _, x := range f.Extensions {
+			for _, accept := range idlExtensionFilters {
+				if !accept(x.Desc) {
+					continue
+				}
+				if err := idlExtensions.RegisterExtension(dynamicpb.NewExtensionType(x.Desc)); err != nil {
+					return fmt.Errorf("register extension '%s' failed, err: %v", x.Desc.FullName(), err)
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// resolveFieldOptions re-parses the options of the field to resolve the unknown fields by the extensions
+func resolveFieldOptions(f protoreflect.FieldDescriptor) protoreflect.Message {
+	return resolveOptions(f.Options(), "field '"+string(f.FullName())+"'")
+}
+
+// resolveOptions re-parses the options of the descriptor to resolve the unknown fields by the extensions
+func resolveOptions(opts proto.Message, desc string) protoreflect.Message {
+	if opts == nil || idlExtensions.NumExtensions() == 0 {
+		return nil
+	}
+	b, err := proto.Marshal(opts)
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+	resolved := opts.ProtoReflect().New().Interface()
+	if err = (proto.UnmarshalOptions{Resolver: idlExtensions}).Unmarshal(b, resolved); err != nil {
+		logs.Warnf("parse the options of %s failed, err: %v", desc, err)
+		return nil
+	}
+	return resolved.ProtoReflect()
+}
+
+// getOption returns the value of the extension in the resolved options
+func getOption(resolved protoreflect.Message, name protoreflect.FullName) (protoreflect.Value, bool) {
+	if resolved == nil {
+		return protoreflect.Value{}, false
+	}
+	xt, err := idlExtensions.FindExtensionByName(name)
+	if err != nil {
+		return protoreflect.Value{}, false
+	}
+	x := xt.TypeDescriptor()
+	if x.ContainingMessage().FullName() != resolved.Descriptor().FullName() || !resolved.Has(x) {
+		return protoreflect.Value{}, false
+	}
+	return resolved.Get(x), true
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// httpRulePathFields is the request fields bound by the path of the google.api.http annotations,
+// the "path" tags are injected into the fields of the generated models
+var httpRulePathFields = map[protoreflect.FullName]string{}
+
+// getHttpRoutes returns the routes of the method from the api.* and google.api.http annotations
+func getHttpRoutes(opts protoreflect.ProtoMessage, method string) (httpOptions, error) {
+	rs := getAllOptions(HttpMethodOptions, opts)
+	routes := make(httpOptions, 0, len(rs))
+	for k, v := range rs {
+		routes = append(routes, httpOption{method: k, path: v.(string)})
+	}
+
+	rules, err := getHttpRules(opts, method)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		routes = append(routes, httpOption{method: rule.method, path: rule.path})
+	}
+	// turn the map into a slice and sort it to make sure getting the results in the same order every time
+	sort.Sort(routes)
+	return routes, nil
+}
+
+// HttpRoute is a route of the method in the idl
+type HttpRoute struct {
+	Method string
+	Path   string
+}
+
+// GetHttpRoutes returns the routes of the method from its options, it is used to check the idl without generating the code
+func GetHttpRoutes(opts protoreflect.ProtoMessage, method string) ([]HttpRoute, error) {
+	routes, err := getHttpRoutes(opts, method)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]HttpRoute, 0, len(routes))
+	for _, r := range routes {
+		out = append(out, HttpRoute{Method: r.method, Path: r.path})
+	}
+	return out, nil
+}
+
+// httpRule is a route of the google.api.http annotation
+type httpRule struct {
+	method     string
+	path       string   // the path of hertz
+	pathFields []string // the request fields bound by the path
+	body       string
+}
+
+// getHttpRules converts the google.api.http annotation and its additional bindings of the method to the routes of hertz
+func getHttpRules(opts protoreflect.ProtoMessage, method string) ([]*httpRule, error) {
+	if opts == nil || !proto.HasExtension(opts, annotations.E_Http) {
+		return nil, nil
+	}
+	rule, ok := proto.GetExtension(opts, annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil, nil
+	}
+
+	var out []*httpRule
+	for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+		var method, tpl string
+		switch pattern := r.GetPattern().(type) {
+		case *annotations.HttpRule_Get:
+			method, tpl = "GET", pattern.Get
+		case *annotations.HttpRule_Put:
+			method, tpl = "PUT", pattern.Put
+		case *annotations.HttpRule_Post:
+			method, tpl = "POST", pattern.Post
+		case *annotations.HttpRule_Delete:
+			method, tpl = "DELETE", pattern.Delete
+		case *annotations.HttpRule_Patch:
+			method, tpl = "PATCH", pattern.Patch
+		case *annotations.HttpRule_Custom:
+			method, tpl = strings.ToUpper(pattern.Custom.GetKind()), pattern.Custom.GetPath()
+		default:
+			continue
+		}
+		path, fields, err := convertHttpTemplate(tpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid google.api.http path '%s' of method '%s', err: %v", tpl, method, err)
+		}
+		if body := r.GetBody(); body != "" && body != "*" {
+			logs.Warnf("the body '%s' of method '%s' is bound as the json field '%s' of the request, not the whole body", body, method, body)
+		}
+		out = append(out, &httpRule{method: method, path: path, pathFields: fields, body: r.GetBody()})
+	}
+	return out, nil
+}
+
+// convertHttpTemplate converts the path template of google.api.http to the path of hertz, such as:
+//
+//	/v1/{name}                  -> /v1/:name
+//	/v1/{name=users/*}          -> /v1/*name
+//	/v1/{parent=users/*}/books  -> /v1/users/:parent/books
+//	/v1/files/{path=**}         -> /v1/files/*path
+//	/v1/{name}:cancel           -> /v1/:name/cancel
+func convertHttpTemplate(tpl string) (path string, fields []string, err error) {
+	if !strings.HasPrefix(tpl, "/") {
+		return "", nil, fmt.Errorf("the path template must start with '/'")
+	}
+
+	// split the verb and segments, the slashes in the variables are not the separators
+	var (
+		segs  []string
+		verb  string
+		depth int
+		start = 1
+	)
+	for i := 1; i <= len(tpl); i++ {
+		if i == len(tpl) {
+			segs = append(segs, tpl[start:])
+			break
+		}
+		switch c := tpl[i]; {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case c == '/' && depth == 0:
+			segs = append(segs, tpl[start:i])
+			start = i + 1
+		case c == ':' && depth == 0:
+			segs = append(segs, tpl[start:i])
+			verb = tpl[i+1:]
+			i = len(tpl)
+		}
+		if depth < 0 || depth > 1 {
+			return "", nil, fmt.Errorf("unbalanced braces")
+		}
+	}
+	if depth != 0 {
+		return "", nil, fmt.Errorf("unbalanced braces")
+	}
+
+	var out []string
+	anonymous := 0
+	for i, seg := range segs {
+		last := i == len(segs)-1 && verb == ""
+		switch {
+		case seg == "":
+			return "", nil, fmt.Errorf("empty segment")
+		case seg == "*":
+			anonymous++
+			out = append(out, fmt.Sprintf(":param%d", anonymous))
+		case seg == "**":
+			if !last {
+				return "", nil, fmt.Errorf("'**' must be the last segment")
+			}
+			out = append(out, "*param")
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			field, sub := seg[1:len(seg)-1], "*"
+			if idx := strings.Index(field, "="); idx >= 0 {
+				field, sub = field[:idx], field[idx+1:]
+			}
+			name := strings.ReplaceAll(field, ".", "_")
+			if field != name {
+				logs.Warnf("the nested field '%s' in the path template '%s' can not be bound, it is named as '%s'", field, tpl, name)
+			}
+			fields = append(fields, field)
+			switch {
+			case sub == "*":
+				out = append(out, ":"+name)
+			case sub == "**" || last:
+				// the value of the variable contains the slashes, so it is matched by the catch-all parameter
+				if !last {
+					return "", nil, fmt.Errorf("the variable '%s' matching '%s' must be the last segment", field, sub)
+				}
+				out = append(out, "*"+name)
+			default:
+				parts := strings.Split(sub, "/")
+				for j, p := range parts {
+					if p == "**" {
+						return "", nil, fmt.Errorf("the variable '%s' matching '%s' must be the last segment", field, sub)
+					}
+					if p == "*" && j == len(parts)-1 {
+						p = ":" + name
+					} else if p == "*" {
+						anonymous++
+						p = fmt.Sprintf(":param%d", anonymous)
+					}
+					out = append(out, p)
+				}
+				logs.Warnf("the variable '%s' of the path template '%s' is bound to the last segment of '%s'", field, tpl, sub)
+			}
+		case strings.ContainsAny(seg, "{}"):
+			return "", nil, fmt.Errorf("invalid segment '%s'", seg)
+		default:
+			out = append(out, seg)
+		}
+	}
+	if verb != "" {
+		logs.Warnf("the verb ':%s' of the path template '%s' is converted to the segment '/%s'", verb, tpl, verb)
+		out = append(out, verb)
+	}
+	return "/" + strings.Join(out, "/"), fields, nil
+}
+
+// collectHttpRulePathFields collects the request fields bound by the path of the google.api.http annotations
+func collectHttpRulePathFields(files []*protogen.File) error {
+	httpRulePathFields = map[protoreflect.FullName]string{}
+	for _, f := range files {
+		for _, s := range f.Services {
+			for _, m := range s.Methods {
+				rules, err := getHttpRules(m.Desc.Options(), string(m.Desc.FullName()))
+				if err != nil {
+					return err
+				}
+				for _, rule := range rules {
+					for _, name := range rule.pathFields {
+						if fd := m.Input.Desc.Fields().ByName(protoreflect.Name(name)); fd != nil {
+							httpRulePathFields[fd.FullName()] = name
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// httpRulePathTag returns the path parameter bound to the field by the google.api.http annotations,
+// the binding annotations of the field take precedence over it
+func httpRulePathTag(f protoreflect.FieldDescriptor) (string, bool) {
+	name, ok := httpRulePathFields[f.FullName()]
+	if !ok {
+		return "", false
+	}
+	for k := range BindingTags {
+		if checkFirstOption(k, f.Options()) != nil {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// hasPathParam reports whether the parameter is in the path of hertz
+func hasPathParam(path, name string) bool {
+	for _, seg := range strings.Split(path, "/") {
+		if seg == ":"+name || seg == "*"+name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConvertHttpTemplate(t *testing.T) {
+	cases := []struct {
+		tpl    string
+		path   string
+		fields []string
+		err    bool
+	}{
+		{tpl: "/v1/users", path: "/v1/users"},
+		{tpl: "/v1/users/{id}", path: "/v1/users/:id", fields: []string{"id"}},
+		{tpl: "/v1/users/{id=*}/books/{book}", path: "/v1/users/:id/books/:book", fields: []string{"id", "book"}},
+		{tpl: "/v1/{name=users/*}", path: "/v1/*name", fields: []string{"name"}},
+		{tpl: "/v1/{parent=users/*}/books", path: "/v1/users/:parent/books", fields: []string{"parent"}},
+		{tpl: "/v1/files/{path=**}", path: "/v1/files/*path", fields: []string{"path"}},
+		{tpl: "/v1/users/{id}:cancel", path: "/v1/users/:id/cancel", fields: []string{"id"}},
+		{tpl: "/v1/*/users", path: "/v1/:param1/users"},
+		{tpl: "/v1/{user.id}", path: "/v1/:user_id", fields: []string{"user.id"}},
+		{tpl: "v1/users", err: true},
+		{tpl: "/v1/{id", err: true},
+		{tpl: "/v1/{path=**}/books", err: true},
+		{tpl: "/v1//users", err: true},
+	}
+	for _, c := range cases {
+		path, fields, err := convertHttpTemplate(c.tpl)
+		if c.err {
+			if err == nil {
+				t.Errorf("want error for '%s', but got '%s'", c.tpl, path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("convert '%s' failed: %v", c.tpl, err)
+			continue
+		}
+		if path != c.path || !reflect.DeepEqual(fields, c.fields) {
+			t.Errorf("convert '%s': want '%s' %v, but got '%s' %v", c.tpl, c.path, c.fields, path, fields)
+		}
+	}
+}
+
+func TestHttpRule(t *testing.T) {
+	gen := loadTestIDL(t, "http_rule.proto")
+	file := gen.FilesByPath["http_rule.proto"]
+	if err := collectHttpRulePathFields(gen.Files); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { httpRulePathFields = map[protoreflect.FullName]string{} }()
+
+	routes, err := getHttpRoutes(file.Proto.GetService()[0].GetMethod()[0].GetOptions(), "user.UserService.UpdateUser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := httpOptions{{method: "PATCH", path: "/v1/*name"}, {method: "PUT", path: "/v1/users/:name"}}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("want routes %v, but got %v", want, routes)
+	}
+
+	fields := file.Messages[0].Fields
+	if name, ok := httpRulePathTag(fields[0].Desc); !ok || name != "name" {
+		t.Errorf("want the field 'name' bound by the path, but got '%s'", name)
+	}
+	if _, ok := httpRulePathTag(fields[1].Desc); ok {
+		t.Errorf("the field 'nickname' is not bound by the path")
+	}
+	var tags structTags
+	if err = injectTagsToStructTags(fields[0].Desc, &tags, true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tags, structTags{{"json", "name,omitempty"}, {"path", "name"}}) {
+		t.Errorf("want the json and path tags of the field 'name', but got %v", tags)
+	}
+
+	out, err := genOpenAPI(gen, "http_rule.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := &openAPI{}
+	if err = yaml.Unmarshal(out, doc); err != nil {
+		t.Fatal(err)
+	}
+	put := doc.Paths["/v1/users/{name}"]["put"]
+	if put == nil || len(put.Parameters) != 2 || put.RequestBody == nil {
+		t.Fatalf("want the operation of 'PUT /v1/users/{name}' with the path, header and body in:\n%s", out)
+	}
+	if body := put.RequestBody.Content["application/json"]; body.Schema.Properties["name"] != nil || body.Schema.Properties["nickname"] == nil {
+		t.Errorf("want the body without the path field 'name' in:\n%s", out)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"github.com/hu-1996/cwgo/hertz/generator"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// the middlewares of the methods and services are the options of the api.proto of cwgo,
+// the names are separated by the commas, eg:
+//
+//	service UserService {
+//	  option (api.service_middleware) = "trace";
+//
+//	  rpc GetUser(GetUserReq) returns (User) {
+//	    option (api.get) = "/user/:id";
+//	    option (api.middleware) = "auth,ratelimit";
+//	  }
+//	}
+const (
+	methodMiddlewareOption  protoreflect.FullName = "api.middleware"
+	serviceMiddlewareOption protoreflect.FullName = "api.service_middleware"
+)
+
+// isMiddlewareExtension reports whether the extension is the middlewares of the methods or services
+func isMiddlewareExtension(x protoreflect.FieldDescriptor) bool {
+	switch {
+	case x.FullName() == methodMiddlewareOption && x.ContainingMessage().FullName() == "google.protobuf.MethodOptions":
+	case x.FullName() == serviceMiddlewareOption && x.ContainingMessage().FullName() == "google.protobuf.ServiceOptions":
+	default:
+		return false
+	}
+	return x.Kind() == protoreflect.StringKind && !x.IsList()
+}
+
+// getMiddlewares returns the names of the middlewares in the options of the method or service
+func getMiddlewares(opts proto.Message, desc string) ([]string, error) {
+	resolved := resolveOptions(opts, desc)
+	if resolved == nil {
+		return nil, nil
+	}
+	name := methodMiddlewareOption
+	if resolved.Descriptor().FullName() == "google.protobuf.ServiceOptions" {
+		name = serviceMiddlewareOption
+	}
+	v, ok := getOption(resolved, name)
+	if !ok {
+		return nil, nil
+	}
+	return generator.ParseMiddlewares(v.String(), desc)
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetMiddlewares(t *testing.T) {
+	gen := loadTestIDL(t, "middleware.proto")
+	for _, x := range gen.FilesByPath["api.proto"].Extensions {
+		// 50331~50360 and 50731~50760 are reserved by hz
+		num := x.Desc.Number()
+		if x.Desc.Name() == "middleware" && num >= 50331 && num <= 50360 {
+			t.Fatalf("want the method option number out of the range of hz, got %d", num)
+		}
+		if x.Desc.Name() == "service_middleware" && num >= 50731 && num <= 50760 {
+			t.Fatalf("want the service option number out of the range of hz, got %d", num)
+		}
+	}
+
+	service := gen.FilesByPath["middleware.proto"].Proto.GetService()[0]
+	methods := service.GetMethod()
+	mws, err := getMiddlewares(methods[0].GetOptions(), "method 'UserService.GetUser'")
+	if err != nil || !reflect.DeepEqual(mws, []string{"auth", "ratelimit"}) {
+		t.Errorf("want the middlewares of the method, got: %v, err: %v", mws, err)
+	}
+	mws, err = getMiddlewares(service.GetOptions(), "service 'UserService'")
+	if err != nil || !reflect.DeepEqual(mws, []string{"trace"}) {
+		t.Errorf("want the middlewares of the service, got: %v, err: %v", mws, err)
+	}
+	if mws, err = getMiddlewares(methods[1].GetOptions(), "method 'UserService.Ping'"); err != nil || len(mws) != 0 {
+		t.Errorf("want no middleware, got: %v, err: %v", mws, err)
+	}
+	if _, err = getMiddlewares(methods[2].GetOptions(), "method 'UserService.Invalid'"); err == nil {
+		t.Error("want the error of the invalid middleware")
+	}
+
+	// the extensions named middleware in the other packages are not the options of cwgo
+	gen = loadTestIDL(t, "other_middleware.proto")
+	opts := gen.FilesByPath["other_middleware.proto"].Proto.GetService()[0].GetMethod()[0].GetOptions()
+	if mws, err = getMiddlewares(opts, "method 'UserService.GetUser'"); err != nil || len(mws) != 0 {
+		t.Errorf("want the option of the other package ignored, got: %v, err: %v", mws, err)
+	}
+}
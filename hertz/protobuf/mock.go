@@ -0,0 +1,209 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/util"
+	"github.com/hu-1996/cwgo/hertz/generator"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// exampleTimestamp is the example of google.protobuf.Timestamp which is mapped to time.Time
+const exampleTimestamp = "2006-01-02T15:04:05Z"
+
+// isExampleExtension reports whether the extension is the example of the fields for the mock server, the example is
+// the string extension of the field options named "example" in any package, or the "example" of the message extension
+// such as "grpc.gateway.protoc_gen_openapiv2.options.openapiv2_field", it is used as json if it is valid
+func isExampleExtension(x protoreflect.FieldDescriptor) bool {
+	return x.ContainingMessage().FullName() == "google.protobuf.FieldOptions" && exampleOption(x) != nil
+}
+
+// exampleOption returns the string field of the example in the extension
+func exampleOption(x protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if x.IsList() || x.IsMap() {
+		return nil
+	}
+	if x.Kind() == protoreflect.StringKind && x.Name() == "example" {
+		return x
+	}
+	if x.Kind() == protoreflect.MessageKind {
+		if f := x.Message().Fields().ByName("example"); f != nil && f.Kind() == protoreflect.StringKind && !f.IsList() {
+			return f
+		}
+	}
+	return nil
+}
+
+// getExample returns the example of the field in json from its options
+func getExample(f protoreflect.FieldDescriptor) (json.RawMessage, bool) {
+	opts := resolveFieldOptions(f)
+	if opts == nil {
+		return nil, false
+	}
+	var example string
+	idlExtensions.RangeExtensions(func(xt protoreflect.ExtensionType) bool {
+		x := xt.TypeDescriptor()
+		if !isExampleExtension(x) || !opts.Has(x) {
+			return true
+		}
+		if ef := exampleOption(x); ef == x {
+			example = opts.Get(x).String()
+		} else {
+			example = opts.Get(x).Message().Get(ef).String()
+		}
+		return example == ""
+	})
+	if example == "" {
+		return nil, false
+	}
+	if json.Valid([]byte(example)) {
+		return json.RawMessage(example), true
+	}
+	b, _ := json.Marshal(example)
+	return b, true
+}
+
+// setExampleResponses sets the examples of the responses of the methods for the mock server
+func setExampleResponses(file *protogen.File, services []*generator.Service) error {
+	for _, s := range services {
+		for _, ps := range file.Services {
+			if string(ps.Desc.Name()) != s.Name {
+				continue
+			}
+			for _, pm := range ps.Methods {
+				example, err := json.Marshal(exampleMessage(pm.Output, nil))
+				if err != nil {
+					return fmt.Errorf("marshal the example of '%s' failed, err: %v", pm.Output.Desc.FullName(), err)
+				}
+				for _, m := range s.Methods {
+					if m.Name == util.CamelString(string(pm.Desc.Name())) {
+						m.ExampleResponse = string(example)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// exampleMessage returns the example of the message in the json of the go model, the recursive fields are skipped
+func exampleMessage(m *protogen.Message, parents []protoreflect.FullName) map[string]interface{} {
+	parents = append(parents, m.Desc.FullName())
+	out := make(map[string]interface{}, len(m.Fields))
+	for _, f := range m.Fields {
+		// the oneof is an interface in the go model, which can not be unmarshalled
+		if oneof := f.Desc.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			continue
+		}
+		name := jsonName(f.Desc)
+		if name == "-" {
+			continue
+		}
+		if example, ok := getExample(f.Desc); ok {
+			out[name] = example
+			continue
+		}
+		if v, ok := exampleField(f, parents); ok {
+			out[name] = v
+		}
+	}
+	return out
+}
+
+func exampleField(f *protogen.Field, parents []protoreflect.FullName) (interface{}, bool) {
+	if f.Desc.IsMap() {
+		v, ok := exampleValue(f.Message.Fields[1], parents)
+		if !ok {
+			return nil, false
+		}
+		return map[string]interface{}{fmt.Sprint(exampleScalar(f.Message.Fields[0])): v}, true
+	}
+	if _, ok := getWellKnownType(f.Desc); ok {
+		return exampleWellKnown(f.Message), true
+	}
+	v, ok := exampleValue(f, parents)
+	if !ok {
+		return nil, false
+	}
+	if f.Desc.IsList() {
+		return []interface{}{v}, true
+	}
+	// the numbers are quoted by the json tag with ",string"
+	if strings.Contains(modelJsonTag(f.Desc), ",string") && f.Desc.Kind() != protoreflect.StringKind {
+		return fmt.Sprint(v), true
+	}
+	return v, true
+}
+
+func exampleValue(f *protogen.Field, parents []protoreflect.FullName) (interface{}, bool) {
+	if f.Desc.Kind() != protoreflect.MessageKind && f.Desc.Kind() != protoreflect.GroupKind {
+		return exampleScalar(f), true
+	}
+	for _, p := range parents {
+		if p == f.Message.Desc.FullName() {
+			return nil, false
+		}
+	}
+	return exampleMessage(f.Message, parents), true
+}
+
+// exampleScalar returns the non-zero example, so that it is not omitted by the json of the go model
+func exampleScalar(f *protogen.Field) interface{} {
+	switch f.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return true
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return []byte("bytes")
+	case protoreflect.EnumKind:
+		for _, v := range f.Enum.Values {
+			if v.Desc.Number() != 0 {
+				return int32(v.Desc.Number())
+			}
+		}
+		return 0
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return 1.5
+	default:
+		return 1
+	}
+}
+
+// exampleWellKnown returns the example of the idiomatic go type of the well-known type
+func exampleWellKnown(m *protogen.Message) interface{} {
+	switch m.Desc.FullName() {
+	case "google.protobuf.Timestamp":
+		return exampleTimestamp
+	case durationFullName:
+		return "1s"
+	case "google.protobuf.Struct":
+		return map[string]interface{}{"key": "value"}
+	case "google.protobuf.Value":
+		return "value"
+	case "google.protobuf.ListValue":
+		return []interface{}{"value"}
+	default:
+		// the wrappers
+		return exampleScalar(m.Fields[0])
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/hu-1996/cwgo/hertz/generator"
+)
+
+func TestSetExampleResponses(t *testing.T) {
+	gen := loadTestIDL(t, "mock.proto")
+
+	method := &generator.HttpMethod{Name: "GetUser"}
+	services := []*generator.Service{{Name: "UserService", Methods: []*generator.HttpMethod{method}}}
+	if err := setExampleResponses(gen.FilesByPath["mock.proto"], services); err != nil {
+		t.Fatal(err)
+	}
+	// the recursive field "friends" is skipped
+	want := `{"id":1,"name":"alice","status":1,"tags":["string"]}`
+	if method.ExampleResponse != want {
+		t.Errorf("want the example %s, got: %s", want, method.ExampleResponse)
+	}
+}
@@ -0,0 +1,389 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/protobuf/api"
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/runtime/protoimpl"
+	"gopkg.in/yaml.v3"
+)
+
+const openAPIVersion = "3.0.3"
+
+type openAPI struct {
+	OpenAPI    string                           `yaml:"openapi"`
+	Info       openAPIInfo                      `yaml:"info"`
+	Paths      map[string]map[string]*operation `yaml:"paths"`
+	Components openAPIComponents                `yaml:"components,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*schema `yaml:"schemas,omitempty"`
+}
+
+type operation struct {
+	Tags        []string             `yaml:"tags,omitempty"`
+	Summary     string               `yaml:"summary,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	OperationID string               `yaml:"operationId"`
+	Parameters  []*parameter         `yaml:"parameters,omitempty"`
+	RequestBody *requestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*response `yaml:"responses"`
+}
+
+type parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Schema      *schema `yaml:"schema"`
+}
+
+type requestBody struct {
+	Content map[string]*mediaType `yaml:"content"`
+}
+
+type response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*mediaType `yaml:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `yaml:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `yaml:"$ref,omitempty"`
+	Type                 string             `yaml:"type,omitempty"`
+	Format               string             `yaml:"format,omitempty"`
+	Description          string             `yaml:"description,omitempty"`
+	Enum                 []int32            `yaml:"enum,omitempty"`
+	Items                *schema            `yaml:"items,omitempty"`
+	Properties           map[string]*schema `yaml:"properties,omitempty"`
+	AdditionalProperties *schema            `yaml:"additionalProperties,omitempty"`
+	Required             []string           `yaml:"required,omitempty"`
+}
+
+// openAPIBuilder builds the openapi document from the http annotations of the services,
+// the parameters are resolved from the binding annotations of the request fields
+type openAPIBuilder struct {
+	doc *openAPI
+}
+
+// genOpenAPI generates the openapi 3 document of the services in the idl
+func genOpenAPI(gen *protogen.Plugin, idl string) ([]byte, error) {
+	file, exist := gen.FilesByPath[idl]
+	if !exist {
+		return nil, fmt.Errorf("file(%s) can not exist", idl)
+	}
+	b := &openAPIBuilder{doc: &openAPI{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:   string(file.Desc.Package()),
+			Version: "1.0.0",
+		},
+		Paths:      make(map[string]map[string]*operation),
+		Components: openAPIComponents{Schemas: make(map[string]*schema)},
+	}}
+	if b.doc.Info.Title == "" {
+		b.doc.Info.Title = idl
+	}
+
+	for _, s := range file.Services {
+		for _, m := range s.Methods {
+			routes, err := getHttpRoutes(m.Desc.Options(), string(m.Desc.FullName()))
+			if err != nil {
+				return nil, err
+			}
+			for _, route := range routes {
+				if route.method == "Any" {
+					logs.Warnf("the 'any' route '%s' of method '%s' can not be described by openapi, skip it", route.path, m.Desc.Name())
+					continue
+				}
+				path, vars := openAPIPath(route.path)
+				if b.doc.Paths[path] == nil {
+					b.doc.Paths[path] = make(map[string]*operation)
+				}
+				b.doc.Paths[path][strings.ToLower(route.method)] = b.operation(s, m, route.method, vars, len(routes) > 1)
+			}
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(b.doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// openAPIPath converts the hertz path such as "/user/:id/*file" to "/user/{id}/{file}"
+func openAPIPath(path string) (string, []string) {
+	var vars []string
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			vars = append(vars, seg[1:])
+			segs[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segs, "/"), vars
+}
+
+func (b *openAPIBuilder) operation(s *protogen.Service, m *protogen.Method, httpMethod string, pathVars []string, multiRoutes bool) *operation {
+	summary, description := splitComment(comment(string(m.Comments.Leading)))
+	op := &operation{
+		Tags:        []string{s.GoName},
+		Summary:     summary,
+		Description: description,
+		OperationID: s.GoName + "_" + m.GoName,
+		Responses: map[string]*response{
+			"200": {
+				Description: "OK",
+				Content: map[string]*mediaType{
+					"application/json": {Schema: b.ref(m.Output)},
+				},
+			},
+		},
+	}
+	if m.Desc.IsStreamingServer() && !m.Desc.IsStreamingClient() {
+		// the responses are the data of the server-sent events
+		op.Responses["200"].Content = map[string]*mediaType{"text/event-stream": {Schema: b.ref(m.Output)}}
+	}
+	if multiRoutes {
+		// keep the operation id unique for the methods with multiple routes
+		op.OperationID += "_" + strings.ToLower(httpMethod)
+	}
+
+	payload := isPayloadMethod(httpMethod)
+	body := &schema{Type: "object", Properties: make(map[string]*schema)}
+	form := &schema{Type: "object", Properties: make(map[string]*schema)}
+	params := make(map[string]bool)
+	for _, f := range m.Input.Fields {
+		opts := f.Desc.Options()
+		desc := fieldComment(f)
+		if name, in := bindingParam(f.Desc); in != "" {
+			param := &parameter{Name: name, In: in, Description: desc, Schema: b.fieldSchema(f)}
+			param.Required = in == "path" || f.Desc.Cardinality() == protoreflect.Required
+			op.Parameters = append(op.Parameters, param)
+			params[in+":"+name] = true
+			continue
+		}
+		if v := checkFirstOption(api.E_RawBody, opts); v != nil {
+			op.RequestBody = &requestBody{Content: map[string]*mediaType{
+				"application/octet-stream": {Schema: &schema{Type: "string", Format: "binary", Description: desc}},
+			}}
+			continue
+		}
+		prop := b.fieldSchema(f)
+		prop.Description = desc
+		if v := checkFirstOption(api.E_Form, opts); v != nil {
+			addProperty(form, v.(string), prop, f.Desc)
+		} else if v := checkFirstOption(api.E_FormCompatible, opts); v != nil {
+			addProperty(form, v.(string), prop, f.Desc)
+		} else if v := checkFirstOption(api.E_Body, opts); v != nil {
+			addProperty(body, v.(string), prop, f.Desc)
+		} else if payload {
+			addProperty(body, jsonName(f.Desc), prop, f.Desc)
+		} else {
+			// the fields without annotation are bound from the query for the methods without body
+			name := checkSnakeName(string(f.Desc.Name()))
+			op.Parameters = append(op.Parameters, &parameter{Name: name, In: "query", Description: desc, Schema: b.fieldSchema(f)})
+			params["query:"+name] = true
+		}
+	}
+	for _, v := range pathVars {
+		if !params["path:"+v] {
+			op.Parameters = append(op.Parameters, &parameter{Name: v, In: "path", Required: true, Schema: &schema{Type: "string"}})
+		}
+	}
+
+	if len(body.Properties) != 0 || len(form.Properties) != 0 {
+		if op.RequestBody == nil {
+			op.RequestBody = &requestBody{Content: make(map[string]*mediaType)}
+		}
+		if len(body.Properties) != 0 {
+			op.RequestBody.Content["application/json"] = &mediaType{Schema: body}
+		}
+		if len(form.Properties) != 0 {
+			op.RequestBody.Content["multipart/form-data"] = &mediaType{Schema: form}
+			op.RequestBody.Content["application/x-www-form-urlencoded"] = &mediaType{Schema: form}
+		}
+	}
+	return op
+}
+
+// bindingParam returns the name and location of the parameter from the binding annotation of the field
+func bindingParam(f protoreflect.FieldDescriptor) (name, in string) {
+	for _, p := range []struct {
+		ext *protoimpl.ExtensionInfo
+		in  string
+	}{
+		{api.E_Path, "path"},
+		{api.E_Query, "query"},
+		{api.E_Header, "header"},
+		{api.E_Cookie, "cookie"},
+	} {
+		if v := checkFirstOption(p.ext, f.Options()); v != nil {
+			return v.(string), p.in
+		}
+	}
+	if name, ok := httpRulePathTag(f); ok {
+		return name, "path"
+	}
+	return "", ""
+}
+
+func addProperty(s *schema, name string, prop *schema, f protoreflect.FieldDescriptor) {
+	s.Properties[name] = prop
+	if f.Cardinality() == protoreflect.Required {
+		s.Required = append(s.Required, name)
+	}
+}
+
+func isPayloadMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "DELETE":
+		return false
+	}
+	return true
+}
+
+// modelJsonTag returns the json tag of the field in the generated model, it's the final tag injected
+// by the annotations, such as api.body, api.go_tag and api.none
+func modelJsonTag(f protoreflect.FieldDescriptor) string {
+	var tags structTags
+	if err := injectTagsToStructTags(f, &tags, true, nil); err == nil {
+		for _, t := range tags {
+			if t[0] == "json" {
+				return t[1]
+			}
+		}
+	}
+	return reflectJsonTag(f).Value
+}
+
+// jsonName returns the json name of the field in the generated model, "-" means it's not encoded by json
+func jsonName(f protoreflect.FieldDescriptor) string {
+	return strings.Split(modelJsonTag(f), ",")[0]
+}
+
+func (b *openAPIBuilder) ref(m *protogen.Message) *schema {
+	name := string(m.Desc.FullName())
+	if _, exist := b.doc.Components.Schemas[name]; !exist {
+		s := &schema{
+			Type:        "object",
+			Description: comment(string(m.Comments.Leading)),
+			Properties:  make(map[string]*schema),
+		}
+		// register it before resolving the fields for the recursive messages
+		b.doc.Components.Schemas[name] = s
+		for _, f := range m.Fields {
+			name := jsonName(f.Desc)
+			if name == "-" {
+				continue
+			}
+			prop := b.fieldSchema(f)
+			prop.Description = fieldComment(f)
+			addProperty(s, name, prop, f.Desc)
+		}
+	}
+	return &schema{Ref: "#/components/schemas/" + name}
+}
+
+func (b *openAPIBuilder) fieldSchema(f *protogen.Field) *schema {
+	if f.Desc.IsMap() {
+		return &schema{Type: "object", AdditionalProperties: b.valueSchema(f.Message.Fields[1])}
+	}
+	if f.Desc.IsList() {
+		return &schema{Type: "array", Items: b.valueSchema(f)}
+	}
+	return b.valueSchema(f)
+}
+
+func (b *openAPIBuilder) valueSchema(f *protogen.Field) *schema {
+	switch f.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return &schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &schema{Type: "integer", Format: "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &schema{Type: "integer", Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &schema{Type: "integer", Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &schema{Type: "number", Format: "double"}
+	case protoreflect.BytesKind:
+		return &schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		// the enums are encoded as the numbers by the generated models
+		s := &schema{Type: "integer", Format: "int32"}
+		names := make([]string, 0, len(f.Enum.Values))
+		for _, v := range f.Enum.Values {
+			s.Enum = append(s.Enum, int32(v.Desc.Number()))
+			names = append(names, fmt.Sprintf("%d: %s", v.Desc.Number(), v.Desc.Name()))
+		}
+		s.Description = strings.Join(names, ", ")
+		return s
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return b.ref(f.Message)
+	default:
+		return &schema{Type: "string"}
+	}
+}
+
+func fieldComment(f *protogen.Field) string {
+	if c := comment(string(f.Comments.Leading)); c != "" {
+		return c
+	}
+	return comment(string(f.Comments.Trailing))
+}
+
+// comment trims the spaces of every line of the comment
+func comment(c string) string {
+	lines := strings.Split(strings.TrimSpace(c), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitComment splits the first line of the comment as the summary
+func splitComment(c string) (summary, description string) {
+	parts := strings.SplitN(c, "\n", 2)
+	summary = parts[0]
+	if len(parts) == 2 {
+		description = strings.TrimSpace(parts[1])
+	}
+	return
+}
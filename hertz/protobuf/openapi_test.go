@@ -0,0 +1,72 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenOpenAPI(t *testing.T) {
+	gen := loadTestIDL(t, "openapi.proto")
+
+	out, err := genOpenAPI(gen, "openapi.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := &openAPI{}
+	if err = yaml.Unmarshal(out, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	get := doc.Paths["/user/{id}"]["get"]
+	if get == nil {
+		t.Fatalf("want the operation of 'GET /user/{id}' in:\n%s", out)
+	}
+	params := make(map[string]*parameter)
+	for _, p := range get.Parameters {
+		params[p.In+":"+p.Name] = p
+	}
+	if p := params["path:id"]; p == nil || !p.Required || p.Schema.Format != "int64" {
+		t.Errorf("want the required int64 path parameter 'id' in:\n%s", out)
+	}
+	if params["header:X-Token"] == nil || params["query:fields"] == nil {
+		t.Errorf("want the header 'X-Token' and the default query 'fields' in:\n%s", out)
+	}
+
+	post := doc.Paths["/user"]["post"]
+	if post == nil || post.RequestBody == nil {
+		t.Fatalf("want the operation of 'POST /user' with body in:\n%s", out)
+	}
+	body := post.RequestBody.Content["application/json"]
+	if body == nil || body.Schema.Properties["user_name"] == nil || body.Schema.Properties["age"] == nil {
+		t.Errorf("want the json body with 'user_name' and 'age' in:\n%s", out)
+	}
+	if form := post.RequestBody.Content["multipart/form-data"]; form == nil || form.Schema.Properties["avatar"] == nil {
+		t.Errorf("want the form with 'avatar' in:\n%s", out)
+	}
+	if resp := post.Responses["200"]; resp == nil || resp.Content["application/json"].Schema.Ref != "#/components/schemas/user.User" {
+		t.Errorf("want the response of the schema 'user.User' in:\n%s", out)
+	}
+	if doc.Components.Schemas["user.User"] == nil || len(doc.Components.Schemas["user.User"].Properties) != 3 {
+		t.Errorf("want the schema 'user.User' without the field of api.none in:\n%s", out)
+	}
+	if doc.Components.Schemas["user.User"].Properties["nickname"] == nil {
+		t.Errorf("want the property 'nickname' of api.go_tag in:\n%s", out)
+	}
+}
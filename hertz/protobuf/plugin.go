@@ -80,6 +80,10 @@ type Plugin struct {
 	IdlClientDir string
 	RmTags       RemoveTags
 	PkgMap       map[string]string
+	OpenAPI      bool   // generate the openapi document of the idl
+	SwaggerUI    bool   // register the swagger ui of the openapi document
+	ClientLang   string // language of the client for "client" command
+	Mock         bool   // generate the mock server of the idl
 	logger       *logs.StdLogger
 }
 
@@ -184,6 +188,19 @@ func (plugin *Plugin) parseArgs(param string) (*config.Argument, error) {
 	plugin.OutDir = args.OutDir
 	plugin.PkgMap = args.OptPkgMap
 	plugin.UseDir = args.Use
+
+	// the options of cwgo are not in the argument of hz
+	extra, err := util.MapForm(params)
+	if err != nil {
+		return nil, err
+	}
+	plugin.SwaggerUI = len(extra["SwaggerUI"]) != 0 && extra["SwaggerUI"][0] == "true"
+	plugin.OpenAPI = plugin.SwaggerUI || len(extra["OpenAPI"]) != 0 && extra["OpenAPI"][0] == "true"
+	wellKnownGoType = len(extra["WellKnownGoType"]) != 0 && extra["WellKnownGoType"][0] == "true"
+	plugin.Mock = len(extra["Mock"]) != 0 && extra["Mock"][0] == "true"
+	if len(extra["ClientLang"]) != 0 {
+		plugin.ClientLang = extra["ClientLang"][0]
+	}
 	return args, nil
 }
 
@@ -217,8 +234,19 @@ func (plugin *Plugin) Handle(req *pluginpb.CodeGeneratorRequest, args *config.Ar
 	}
 	rpath := strings.TrimPrefix(plugin.OutDir, cpath+"/")
 	plugin.Module = rpath
-	// plugin start working
-	err = plugin.GenerateFiles(gen)
+	if err = collectHttpRulePathFields(gen.Files); err != nil {
+		return err
+	}
+	if err = checkWellKnownRequests(gen.Files); err != nil {
+		return err
+	}
+	if err = collectExtensions(gen.Files); err != nil {
+		return err
+	}
+	// plugin start working, the go models are not needed by the typescript client
+	if plugin.ClientLang != generator.ClientLangTS {
+		err = plugin.GenerateFiles(gen)
+	}
 	if err != nil {
 		// Error within the plugin will be responded by the plugin.
 		// But if the plugin does not response correctly, the error is returned to the upper level.
@@ -350,6 +378,7 @@ func (plugin *Plugin) fixModelPathAndPackage(pkg string) (impt, path string) {
 func (plugin *Plugin) GenerateFiles(pluginPb *protogen.Plugin) error {
 	idl := pluginPb.Request.FileToGenerate[len(pluginPb.Request.FileToGenerate)-1]
 	pluginPb.SupportedFeatures = gengo.SupportedFeatures
+	durationFiles = map[protogen.GoImportPath]bool{}
 	for _, f := range pluginPb.Files {
 		if f.Proto.GetName() == idl {
 			err := plugin.GenerateFile(pluginPb, f)
@@ -439,8 +468,15 @@ func generateFile(gen *protogen.Plugin, file *protogen.File, rmTags RemoveTags,
 		}
 	}
 	genExtensions(g, f)
+	if wellKnownGoType {
+		if err = genDurationFile(gen, f, filepath.Dir(filename)); err != nil {
+			return nil, err
+		}
+	}
 
+	restore := hideWellKnownDescriptors(f)
 	genReflectFileDescriptor(gen, g, f)
+	restore()
 
 	return g, nil
 }
@@ -463,10 +499,13 @@ func genMessage(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, rmTags R
 	g.P("}")
 	g.P()
 
+	restore := hideWellKnownFields(m.Message)
 	genMessageKnownFunctions(g, f, m)
 	genMessageDefaultDecls(g, f, m)
 	genMessageMethods(g, f, m)
 	genMessageOneofWrapperTypes(g, f, m)
+	restore()
+	genWellKnownGetters(g, m)
 	return nil
 }
 
@@ -515,14 +554,22 @@ func genMessageField(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, fie
 		sf.append(oneof.GoName)
 		return nil
 	}
-	goType, pointer := fieldGoType(g, f, field)
+	var tags structTags
+	wkt, isWellKnown := getWellKnownType(field.Desc)
+	goType, pointer := "", false
+	if isWellKnown {
+		// the field of the idiomatic go type has no protobuf tag, it is ignored by the reflection of protobuf
+		goType = wkt.goTypeName(g)
+	} else {
+		goType, pointer = fieldGoType(g, f, field)
+		tags = structTags{
+			{"protobuf", fieldProtobufTagValue(field)},
+			//{"json", fieldJSONTagValue(field)},
+		}
+	}
 	if pointer {
 		goType = "*" + goType
 	}
-	tags := structTags{
-		{"protobuf", fieldProtobufTagValue(field)},
-		//{"json", fieldJSONTagValue(field)},
-	}
 	if field.Desc.IsMap() {
 		key := field.Message.Fields[0]
 		val := field.Message.Fields[1]
@@ -587,6 +634,11 @@ func (plugin *Plugin) getIdlInfo(ast *descriptorpb.FileDescriptorProto, deps map
 	for _, s := range services {
 		models.MergeArray(s.Models)
 	}
+	if plugin.ClientLang == generator.ClientLangTS {
+		for _, s := range services {
+			s.TSTypes = getTSTypes(plugin.FilesByPath[ast.GetName()], s)
+		}
+	}
 
 	return &generator.HttpPackage{
 		Services: services,
@@ -646,10 +698,26 @@ func (plugin *Plugin) genHttpPackage(ast *descriptorpb.FileDescriptorProto, deps
 		QueryEnumAsInt:       args.QueryEnumAsInt,
 		SnakeStyleMiddleware: args.SnakeStyleMiddleware,
 		SortRouter:           args.SortRouter,
+		ClientLang:           plugin.ClientLang,
 	}
 
 	if args.ModelBackend != "" {
 		sg.Backend = meta.Backend(args.ModelBackend)
+		// the golang models are generated by protoc-gen-go, the third-party backend generates the extra model files
+		sg.NeedModel = sg.Backend != meta.BackendGolang
+	}
+	if plugin.OpenAPI && args.CmdType != meta.CmdClient {
+		sg.OpenAPI, err = genOpenAPI(plugin.Plugin, ast.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("generate openapi document error: %v", err)
+		}
+		sg.SwaggerUI = plugin.SwaggerUI
+	}
+	if plugin.Mock && args.CmdType != meta.CmdClient {
+		if err = setExampleResponses(plugin.FilesByPath[ast.GetName()], idl.Services); err != nil {
+			return nil, fmt.Errorf("generate mock responses error: %v", err)
+		}
+		sg.Mock = true
 	}
 	generator.SetDefaultTemplateConfig()
 
@@ -22,10 +22,56 @@ import (
 	"testing"
 
 	"github.com/cloudwego/hertz/cmd/hz/meta"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
+// loadTestIDL parses the idl in test_data with the api.proto of cwgo, and returns the plugin of the request like protoc,
+// the extensions imported by the idl are collected until the end of the test
+func loadTestIDL(t *testing.T, idl string) *protogen.Plugin {
+	p := protoparse.Parser{ImportPaths: []string{"test_data", "api"}, LookupImport: desc.LoadFileDescriptor}
+	fds, err := p.ParseFiles(idl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &pluginpb.CodeGeneratorRequest{FileToGenerate: []string{idl}}
+	added := make(map[string]bool)
+	var addFile func(fd *desc.FileDescriptor)
+	addFile = func(fd *desc.FileDescriptor) {
+		if added[fd.GetName()] {
+			return
+		}
+		added[fd.GetName()] = true
+		for _, dep := range fd.GetDependencies() {
+			addFile(dep)
+		}
+		req.ProtoFile = append(req.ProtoFile, fd.AsFileDescriptorProto())
+	}
+	addFile(fds[0])
+
+	// the options of the extensions which are not linked are the unknown fields in the request of protoc
+	in, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = &pluginpb.CodeGeneratorRequest{}
+	if err = proto.Unmarshal(in, req); err != nil {
+		t.Fatal(err)
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = collectExtensions(gen.Files); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = collectExtensions(nil) })
+	return gen
+}
+
 func TestPlugin_Handle(t *testing.T) {
 	in, err := ioutil.ReadFile("../testdata/request_protoc.out")
 	if err != nil {
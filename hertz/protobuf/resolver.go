@@ -200,6 +200,9 @@ func (resolver *Resolver) getBaseType(f *descriptorpb.FieldDescriptorProto, nest
 	if bt != nil {
 		return checkListType(bt, f.GetLabel()), nil
 	}
+	if wkt, ok := getWellKnownModelType(f); ok {
+		return wkt.modelType(resolver.mainPkg.Model), nil
+	}
 
 	nt := getNestedType(f, nested)
 	if nt != nil {
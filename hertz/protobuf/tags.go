@@ -335,6 +335,11 @@ func defaultBindingStructTags(f protoreflect.FieldDescriptor) []model.Tag {
 			return out[:1]
 		}
 	}
+	// the field bound by the path of google.api.http is regarded as annotated by api.path
+	if _, ok := httpRulePathTag(f); ok {
+		out[0] = reflectJsonTag(f)
+		return out[:1]
+	}
 
 	if v := checkFirstOption(api.E_Body, opts); v != nil {
 		val := getStructJsonValue(f, v.(string))
@@ -396,12 +401,22 @@ func injectTagsToStructTags(f protoreflect.FieldDescriptor, out *structTags, nee
 		}
 	}
 
+	if name, ok := httpRulePathTag(f); ok {
+		tags = append(tags, tag(BindingTags[api.E_Path], name))
+	}
+
 	// validator tags
+	hasValidator := false
 	for k, v := range ValidatorTags {
 		if vv := checkFirstOption(k, as); vv != nil {
+			hasValidator = true
 			tags = append(tags, tag(v, vv))
 		}
 	}
+	// the validate rules of protoc-gen-validate and buf.validate are translated if "api.vd" is absent
+	if expr := validateExpr(f); expr != "" && !hasValidator {
+		tags = append(tags, tag(ValidatorTags[api.E_Vd], expr))
+	}
 
 	if v := checkFirstOption(api.E_GoTag, as); v != nil {
 		gts := util.SplitGoTags(v.(string))
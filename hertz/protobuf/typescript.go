@@ -0,0 +1,157 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/cloudwego/hertz/cmd/hz/util"
+	"github.com/hu-1996/cwgo/hertz/generator"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsWellKnownTypes are the typescript types of the json of the idiomatic go types, see wellKnownTypes
+var tsWellKnownTypes = map[protoreflect.FullName]string{
+	"google.protobuf.Timestamp": "string",
+	"google.protobuf.Duration":  "string",
+
+	"google.protobuf.DoubleValue": "number",
+	"google.protobuf.FloatValue":  "number",
+	"google.protobuf.Int64Value":  "number",
+	"google.protobuf.UInt64Value": "number",
+	"google.protobuf.Int32Value":  "number",
+	"google.protobuf.UInt32Value": "number",
+	"google.protobuf.BoolValue":   "boolean",
+	"google.protobuf.StringValue": "string",
+	"google.protobuf.BytesValue":  "string",
+
+	"google.protobuf.Struct":    "Record<string, unknown>",
+	"google.protobuf.Value":     "unknown",
+	"google.protobuf.ListValue": "unknown[]",
+}
+
+// tsBuilder maps the messages and enums to the typescript types, the types are in the json of the go models
+type tsBuilder struct {
+	types []*generator.TSType
+	seen  map[protoreflect.FullName]bool
+}
+
+// getTSTypes returns the types of the requests and responses of the client methods in the service,
+// and the types referred by them
+func getTSTypes(file *protogen.File, service *generator.Service) []*generator.TSType {
+	methods := make(map[string]bool, len(service.ClientMethods))
+	for _, m := range service.ClientMethods {
+		methods[m.Name] = true
+	}
+	b := &tsBuilder{seen: make(map[protoreflect.FullName]bool)}
+	for _, s := range file.Services {
+		if string(s.Desc.Name()) != service.Name {
+			continue
+		}
+		for _, m := range s.Methods {
+			if methods[util.CamelString(string(m.Desc.Name()))] {
+				b.message(m.Input)
+				b.message(m.Output)
+			}
+		}
+	}
+	return b.types
+}
+
+func (b *tsBuilder) message(m *protogen.Message) string {
+	name := m.GoIdent.GoName
+	if b.seen[m.Desc.FullName()] {
+		return name
+	}
+	b.seen[m.Desc.FullName()] = true
+	t := &generator.TSType{
+		Name:    name,
+		Comment: comment(string(m.Comments.Leading)),
+	}
+	b.types = append(b.types, t)
+	for _, f := range m.Fields {
+		prop := jsonName(f.Desc)
+		if prop == "-" {
+			continue
+		}
+		if !tsIdentifier.MatchString(prop) {
+			prop = strconv.Quote(prop)
+		}
+		t.Fields = append(t.Fields, generator.TSField{
+			Name:     prop,
+			Type:     b.fieldType(f),
+			Comment:  fieldComment(f),
+			Required: descriptorpb.FieldDescriptorProto_Label(f.Desc.Cardinality()) == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED,
+		})
+	}
+	return name
+}
+
+func (b *tsBuilder) enum(e *protogen.Enum) string {
+	name := e.GoIdent.GoName
+	if b.seen[e.Desc.FullName()] {
+		return name
+	}
+	b.seen[e.Desc.FullName()] = true
+	t := &generator.TSType{
+		Name:    name,
+		Comment: comment(string(e.Comments.Leading)),
+	}
+	for _, v := range e.Values {
+		t.Values = append(t.Values, generator.TSEnumValue{
+			Name:  string(v.Desc.Name()),
+			Value: int32(v.Desc.Number()),
+		})
+	}
+	b.types = append(b.types, t)
+	return name
+}
+
+func (b *tsBuilder) fieldType(f *protogen.Field) string {
+	if f.Desc.IsMap() {
+		return "Record<string, " + b.valueType(f.Message.Fields[1]) + ">"
+	}
+	if f.Desc.IsList() {
+		return b.valueType(f) + "[]"
+	}
+	if _, ok := getWellKnownType(f.Desc); ok {
+		return tsWellKnownTypes[f.Desc.Message().FullName()]
+	}
+	return b.valueType(f)
+}
+
+func (b *tsBuilder) valueType(f *protogen.Field) string {
+	switch f.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		// the bytes are encoded in base64 by the go models
+		return "string"
+	case protoreflect.EnumKind:
+		// the enums are encoded as the numbers by the go models
+		return b.enum(f.Enum)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return b.message(f.Message)
+	default:
+		return "number"
+	}
+}
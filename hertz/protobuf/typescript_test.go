@@ -0,0 +1,60 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/hu-1996/cwgo/hertz/generator"
+)
+
+func TestGetTSTypes(t *testing.T) {
+	gen := loadTestIDL(t, "typescript.proto")
+	wellKnownGoType = true
+	defer func() { wellKnownGoType = false }()
+
+	service := &generator.Service{
+		Name:          "UserService",
+		ClientMethods: []*generator.ClientMethod{{HttpMethod: &generator.HttpMethod{Name: "GetUser"}}},
+	}
+	types := make(map[string]*generator.TSType)
+	for _, typ := range getTSTypes(gen.FilesByPath["typescript.proto"], service) {
+		types[typ.Name] = typ
+	}
+	if len(types) != 3 || types["GetUserReq"] == nil || types["User"] == nil {
+		t.Fatalf("want the types of the request, the response and the enum, got: %v", types)
+	}
+	if status := types["Status"]; status == nil || len(status.Values) != 2 || status.Values[1].Name != "BANNED" || status.Values[1].Value != 1 {
+		t.Errorf("want the enum 'Status', got: %+v", status)
+	}
+	fields := make(map[string]string)
+	for _, f := range types["User"].Fields {
+		fields[f.Name] = f.Type
+	}
+	for name, want := range map[string]string{
+		"id":         "number",
+		"status":     "Status",
+		"tags":       "string[]",
+		"labels":     "Record<string, string>",
+		"created_at": "string",
+		"friends":    "User[]",
+	} {
+		if fields[name] != want {
+			t.Errorf("want the type '%s' of the field '%s', got: '%s'", want, name, fields[name])
+		}
+	}
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ValidateRuleExtensions is the field options of protoc-gen-validate and buf.validate which are translated to "vd" tags
+var ValidateRuleExtensions = []protoreflect.FullName{
+	"validate.rules",     // protoc-gen-validate
+	"buf.validate.field", // protovalidate
+}
+
+// isValidateExtension reports whether the extension is the validate rules of the fields
+func isValidateExtension(x protoreflect.FieldDescriptor) bool {
+	for _, name := range ValidateRuleExtensions {
+		if x.FullName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getValidateRules returns the validate rules of the field, it returns nil if the field has none
+func getValidateRules(f protoreflect.FieldDescriptor) protoreflect.Message {
+	resolved := resolveFieldOptions(f)
+	for _, name := range ValidateRuleExtensions {
+		if v, ok := getOption(resolved, name); ok {
+			return v.Message()
+		}
+	}
+	return nil
+}
+
+// validateExpr translates the validate rules of the field to the expression of "vd" tag,
+// the rules can not be expressed are skipped with a warning
+func validateExpr(f protoreflect.FieldDescriptor) string {
+	rules := getValidateRules(f)
+	if rules == nil {
+		return ""
+	}
+	var exprs []string
+	rangeRules(rules, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+		switch name := string(fd.Name()); {
+		case name == "required":
+			if v.Bool() {
+				exprs = append(exprs, requiredExpr(f))
+			}
+		case name == "message":
+			// protoc-gen-validate puts "required" in the message rules
+			if r := v.Message(); r.Has(r.Descriptor().Fields().ByName("required")) && r.Get(r.Descriptor().Fields().ByName("required")).Bool() {
+				exprs = append(exprs, requiredExpr(f))
+			}
+		case name == "any" || name == "duration" || name == "timestamp":
+			logs.Warnf("the validate rules of '%s' of field '%s' are not supported by 'vd' tag, skip them", name, f.FullName())
+		case fd.Message() != nil && strings.HasSuffix(string(fd.Message().Name()), "Rules"):
+			exprs = append(exprs, typedRuleExprs(f, name, v.Message())...)
+		default:
+			logs.Warnf("the validate rule '%s' of field '%s' is not supported by 'vd' tag, skip it", name, f.FullName())
+		}
+	})
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	for i, e := range exprs {
+		exprs[i] = "(" + e + ")"
+	}
+	return strings.Join(exprs, "&&")
+}
+
+func requiredExpr(f protoreflect.FieldDescriptor) string {
+	switch {
+	case f.IsList() || f.IsMap() || f.Kind() == protoreflect.StringKind || f.Kind() == protoreflect.BytesKind:
+		return "len($)>0"
+	case f.Kind() == protoreflect.MessageKind || f.Kind() == protoreflect.GroupKind:
+		return "$!=nil"
+	case f.Kind() == protoreflect.BoolKind:
+		return "$"
+	default:
+		return "$!=0"
+	}
+}
+
+// typedRuleExprs translates the rules of the type, such as "string" and "int64"
+func typedRuleExprs(f protoreflect.FieldDescriptor, typ string, rules protoreflect.Message) (exprs []string) {
+	// the length of string is counted by characters, and bytes by bytes
+	lenFunc := "len"
+	if typ == "string" {
+		lenFunc = "mblen"
+	}
+	rangeRules(rules, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+		name := string(fd.Name())
+		expr := ""
+		switch {
+		case typ == "bytes" && name != "len" && name != "min_len" && name != "max_len":
+			// the bytes can not be compared with the literals of "vd"
+		case name == "const":
+			expr = "$==" + validateLiteral(v)
+		case name == "lt":
+			expr = "$<" + validateLiteral(v)
+		case name == "lte":
+			expr = "$<=" + validateLiteral(v)
+		case name == "gt":
+			expr = "$>" + validateLiteral(v)
+		case name == "gte":
+			expr = "$>=" + validateLiteral(v)
+		case name == "in" || name == "not_in":
+			if list := v.List(); list.Len() != 0 {
+				values := make([]string, 0, list.Len())
+				for i := 0; i < list.Len(); i++ {
+					values = append(values, validateLiteral(list.Get(i)))
+				}
+				expr = "in($," + strings.Join(values, ",") + ")"
+				if name == "not_in" {
+					expr = "!" + expr
+				}
+			}
+		case name == "len":
+			expr = fmt.Sprintf("%s($)==%d", lenFunc, v.Uint())
+		case name == "min_len":
+			expr = fmt.Sprintf("%s($)>=%d", lenFunc, v.Uint())
+		case name == "max_len":
+			expr = fmt.Sprintf("%s($)<=%d", lenFunc, v.Uint())
+		case name == "len_bytes":
+			expr = fmt.Sprintf("len($)==%d", v.Uint())
+		case name == "min_bytes" || name == "min_items" || name == "min_pairs":
+			expr = fmt.Sprintf("len($)>=%d", v.Uint())
+		case name == "max_bytes" || name == "max_items" || name == "max_pairs":
+			expr = fmt.Sprintf("len($)<=%d", v.Uint())
+		case name == "pattern":
+			expr = "regexp(" + quoteValidateString(v.String()) + ")"
+		case name == "prefix":
+			expr = "regexp(" + quoteValidateString("^"+regexp.QuoteMeta(v.String())) + ")"
+		case name == "suffix":
+			expr = "regexp(" + quoteValidateString(regexp.QuoteMeta(v.String())+"$") + ")"
+		case name == "contains":
+			expr = "regexp(" + quoteValidateString(regexp.QuoteMeta(v.String())) + ")"
+		case name == "email":
+			if v.Bool() {
+				expr = "email($)"
+			}
+		case name == "defined_only":
+			if v.Bool() && f.Enum() != nil {
+				values := f.Enum().Values()
+				nums := make([]string, 0, values.Len())
+				for i := 0; i < values.Len(); i++ {
+					nums = append(nums, fmt.Sprint(int32(values.Get(i).Number())))
+				}
+				expr = "in($," + strings.Join(nums, ",") + ")"
+			}
+		}
+		if expr != "" {
+			exprs = append(exprs, expr)
+		} else if !isFalseRule(v) {
+			logs.Warnf("the validate rule '%s.%s' of field '%s' is not supported by 'vd' tag, skip it", typ, name, f.FullName())
+		}
+	})
+	return exprs
+}
+
+// rangeRules iterates the rules in the declared order, to make sure the expression is stable
+func rangeRules(rules protoreflect.Message, f func(fd protoreflect.FieldDescriptor, v protoreflect.Value)) {
+	fields := rules.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); rules.Has(fd) {
+			f(fd, rules.Get(fd))
+		}
+	}
+}
+
+// isFalseRule reports whether the rule is a disabled switch, such as "email: false"
+func isFalseRule(v protoreflect.Value) bool {
+	b, ok := v.Interface().(bool)
+	return ok && !b
+}
+
+func validateLiteral(v protoreflect.Value) string {
+	switch val := v.Interface().(type) {
+	case string:
+		return quoteValidateString(val)
+	case protoreflect.EnumNumber:
+		return fmt.Sprint(int32(val))
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func quoteValidateString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
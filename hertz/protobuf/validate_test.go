@@ -0,0 +1,39 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import "testing"
+
+func TestValidateExpr(t *testing.T) {
+	gen := loadTestIDL(t, "validate.proto")
+
+	fields := gen.FilesByPath["validate.proto"].Messages[0].Fields
+	if expr := validateExpr(fields[0].Desc); expr != `(mblen($)>=3)&&(regexp('^\w+$'))` {
+		t.Errorf("unexpected expression of field 'name': %s", expr)
+	}
+	if expr := validateExpr(fields[1].Desc); expr != `($>=18)&&(in($,18,20))` {
+		t.Errorf("unexpected expression of field 'age': %s", expr)
+	}
+
+	var tags structTags
+	if err := injectTagsToStructTags(fields[2].Desc, &tags, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0] != [2]string{"vd", "len($)<10"} {
+		t.Errorf("want the 'vd' tag of the annotation first, but got %v", tags)
+	}
+}
@@ -0,0 +1,344 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/generator/model"
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// wellKnownGoType maps the fields of the well-known types to the idiomatic go types in the models,
+// the fields are bound by http and json, but they are not in the protobuf wire format any more,
+// so they are rejected in the requests, see checkWellKnownRequests
+var wellKnownGoType = false
+
+var timeModel = &model.Model{PackageName: "time", Package: "time"}
+
+// wellKnownType is the idiomatic go type of a well-known type
+type wellKnownType struct {
+	ident   protogen.GoIdent // the type from other package
+	goType  string           // the builtin type
+	local   bool             // the type is generated in the package of the model, see genDurationFile
+	zero    string
+	pointer bool
+	model   *model.Type
+}
+
+var wellKnownTypes = map[protoreflect.FullName]wellKnownType{
+	"google.protobuf.Timestamp": {ident: timeIdent("Time"), zero: "time.Time{}", model: &model.Type{Name: "Time", Scope: timeModel, Kind: model.KindInvalid}},
+	"google.protobuf.Duration":  {goType: durationTypeName, local: true, zero: "0", model: &model.Type{Name: durationTypeName, Kind: model.KindInt64}},
+
+	"google.protobuf.DoubleValue": {goType: "float64", pointer: true, model: model.TypeFloat64},
+	"google.protobuf.FloatValue":  {goType: "float32", pointer: true, model: model.TypeFloat32},
+	"google.protobuf.Int64Value":  {goType: "int64", pointer: true, model: model.TypeInt64},
+	"google.protobuf.UInt64Value": {goType: "uint64", pointer: true, model: model.TypeUint64},
+	"google.protobuf.Int32Value":  {goType: "int32", pointer: true, model: model.TypeInt32},
+	"google.protobuf.UInt32Value": {goType: "uint32", pointer: true, model: model.TypeUint32},
+	"google.protobuf.BoolValue":   {goType: "bool", pointer: true, model: model.TypeBool},
+	"google.protobuf.StringValue": {goType: "string", pointer: true, model: model.TypeString},
+	"google.protobuf.BytesValue":  {goType: "[]byte", model: model.TypeBinary},
+
+	"google.protobuf.Struct":    {goType: "map[string]interface{}", model: &model.Type{Name: "map", Scope: &model.BaseModel, Kind: model.KindMap, Category: model.CategoryMap, Extra: []*model.Type{model.TypeString, interfaceType}}},
+	"google.protobuf.Value":     {goType: "interface{}", model: interfaceType},
+	"google.protobuf.ListValue": {goType: "[]interface{}", model: &model.Type{Name: "list", Scope: &model.BaseModel, Kind: model.KindSlice, Category: model.CategoryList, Extra: []*model.Type{interfaceType}}},
+}
+
+var interfaceType = &model.Type{Name: "interface{}", Scope: &model.BaseModel, Kind: model.KindInterface}
+
+const (
+	durationFullName protoreflect.FullName = "google.protobuf.Duration"
+	durationTypeName                       = "Duration"
+	durationFileName                       = "wkt_duration.go"
+)
+
+// durationFiles is the packages whose Duration type has been generated
+var durationFiles = map[protogen.GoImportPath]bool{}
+
+func timeIdent(name string) protogen.GoIdent {
+	return protogen.GoIdent{GoName: name, GoImportPath: "time"}
+}
+
+// getWellKnownType returns the idiomatic go type of the field, only the singular fields out of the oneof are mapped
+func getWellKnownType(f protoreflect.FieldDescriptor) (wellKnownType, bool) {
+	if !wellKnownGoType || f.Kind() != protoreflect.MessageKind || f.IsList() || f.IsMap() || f.ContainingOneof() != nil {
+		return wellKnownType{}, false
+	}
+	t, ok := wellKnownTypes[f.Message().FullName()]
+	return t, ok
+}
+
+// getWellKnownModelType is the same as getWellKnownType for the descriptor of the field
+func getWellKnownModelType(f *descriptorpb.FieldDescriptorProto) (wellKnownType, bool) {
+	if !wellKnownGoType || f.GetType() != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE ||
+		f.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED || f.OneofIndex != nil {
+		return wellKnownType{}, false
+	}
+	t, ok := wellKnownTypes[protoreflect.FullName(strings.TrimPrefix(f.GetTypeName(), "."))]
+	return t, ok
+}
+
+// modelType returns the type of the model, the local type is in the scope of the model package
+func (t wellKnownType) modelType(scope *model.Model) *model.Type {
+	if !t.local {
+		return t.model
+	}
+	typ := *t.model
+	typ.Scope = scope
+	return &typ
+}
+
+func (t wellKnownType) goTypeName(g *protogen.GeneratedFile) string {
+	goType := t.goType
+	if t.ident.GoName != "" {
+		goType = g.QualifiedGoIdent(t.ident)
+	}
+	if t.pointer {
+		goType = "*" + goType
+	}
+	return goType
+}
+
+func (t wellKnownType) zeroValue() string {
+	if t.zero == "" || t.pointer {
+		return "nil"
+	}
+	return t.zero
+}
+
+// hideWellKnownFields removes the fields of the idiomatic go types from the message temporarily,
+// so that the getters and reflection of protobuf are not generated for them
+func hideWellKnownFields(m *protogen.Message) (restore func()) {
+	fields := m.Fields
+	shown := make([]*protogen.Field, 0, len(fields))
+	for _, f := range fields {
+		if _, ok := getWellKnownType(f.Desc); !ok {
+			shown = append(shown, f)
+		}
+	}
+	m.Fields = shown
+	return func() { m.Fields = fields }
+}
+
+// genWellKnownGetters generates the getters of the fields of the idiomatic go types
+func genWellKnownGetters(g *protogen.GeneratedFile, m *messageInfo) {
+	for _, field := range m.Fields {
+		t, ok := getWellKnownType(field.Desc)
+		if !ok {
+			continue
+		}
+		g.P("func (x *", m.GoIdent, ") Get", field.GoName, "() ", t.goTypeName(g), " {")
+		g.P("if x != nil {")
+		g.P("return x.", field.GoName)
+		g.P("}")
+		g.P("return ", t.zeroValue())
+		g.P("}")
+		g.P()
+	}
+}
+
+// hideWellKnownDescriptors removes the fields of the idiomatic go types from the raw descriptor of the file
+// temporarily, the fields are not in the protobuf wire format because they have no protobuf go types
+func hideWellKnownDescriptors(f *fileInfo) (restore func()) {
+	hidden := make(map[protoreflect.FullName]bool)
+	var restores []func()
+	for _, m := range f.allMessages {
+		for _, field := range m.Fields {
+			if _, ok := getWellKnownType(field.Desc); ok {
+				hidden[field.Desc.FullName()] = true
+			}
+		}
+		restores = append(restores, hideWellKnownFields(m.Message))
+	}
+	origin := f.Proto
+	if len(hidden) != 0 {
+		logs.Warnf("the fields of the well-known types in '%s' are mapped to the go types, they are dropped from the protobuf wire format", f.Desc.Path())
+		desc := proto.Clone(origin).(*descriptorpb.FileDescriptorProto)
+		removeDescriptorFields(desc.GetMessageType(), desc.GetPackage(), hidden)
+		f.Proto = desc
+	}
+	return func() {
+		f.Proto = origin
+		for _, r := range restores {
+			r()
+		}
+	}
+}
+
+func removeDescriptorFields(msgs []*descriptorpb.DescriptorProto, scope string, hidden map[protoreflect.FullName]bool) {
+	for _, m := range msgs {
+		name := m.GetName()
+		if scope != "" {
+			name = scope + "." + name
+		}
+		fields := m.Field[:0]
+		for _, f := range m.Field {
+			if !hidden[protoreflect.FullName(name+"."+f.GetName())] {
+				fields = append(fields, f)
+			}
+		}
+		m.Field = fields
+		removeDescriptorFields(m.GetNestedType(), name, hidden)
+	}
+}
+
+// checkWellKnownRequests rejects the idiomatic go types in the requests of the services, hertz binds the request
+// from the protobuf body by its content type, but the fields of the go types are not in the protobuf wire format
+func checkWellKnownRequests(files []*protogen.File) error {
+	if !wellKnownGoType {
+		return nil
+	}
+	visited := make(map[protoreflect.FullName]bool)
+	for _, f := range files {
+		if !f.Generate {
+			continue
+		}
+		for _, s := range f.Services {
+			for _, m := range s.Methods {
+				if field := findWellKnownField(m.Input.Desc, visited); field != nil {
+					return fmt.Errorf("the field '%s' of the request of '%s' can't be mapped to the go type by wkt_go_type, "+
+						"it is dropped from the protobuf wire format but the request may be bound from the protobuf body", field.FullName(), m.Desc.FullName())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findWellKnownField returns the field mapped to the idiomatic go type in the message or the messages of its fields
+func findWellKnownField(m protoreflect.MessageDescriptor, visited map[protoreflect.FullName]bool) protoreflect.FieldDescriptor {
+	if visited[m.FullName()] {
+		return nil
+	}
+	visited[m.FullName()] = true
+	for i, fields := 0, m.Fields(); i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if _, ok := getWellKnownType(f); ok {
+			return f
+		}
+		if f.IsMap() {
+			f = f.MapValue()
+		}
+		if f.Message() != nil {
+			if found := findWellKnownField(f.Message(), visited); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// genDurationFile generates the Duration type in the package of the file once, it is the idiomatic go type of
+// google.protobuf.Duration. The json and text of it are the seconds with the suffix "s" like the json mapping
+// of protobuf, such as "1.5s", rather than the nanoseconds of time.Duration
+func genDurationFile(gen *protogen.Plugin, f *fileInfo, dir string) error {
+	if durationFiles[f.GoImportPath] || !usesDuration(f) {
+		return nil
+	}
+	for _, m := range f.allMessages {
+		if m.GoIdent.GoName == durationTypeName {
+			return fmt.Errorf("the message '%s' conflicts with the type '%s' of google.protobuf.Duration in '%s'", m.Desc.FullName(), durationTypeName, f.Desc.Path())
+		}
+	}
+	for _, e := range f.allEnums {
+		if e.GoIdent.GoName == durationTypeName {
+			return fmt.Errorf("the enum '%s' conflicts with the type '%s' of google.protobuf.Duration in '%s'", e.Desc.FullName(), durationTypeName, f.Desc.Path())
+		}
+	}
+	durationFiles[f.GoImportPath] = true
+
+	g := gen.NewGeneratedFile(filepath.Join(dir, durationFileName), f.GoImportPath)
+	var (
+		duration    = timeIdent("Duration")
+		second      = timeIdent("Second")
+		parse       = timeIdent("ParseDuration")
+		marshal     = protogen.GoImportPath("encoding/json").Ident("Marshal")
+		unmarshal   = protogen.GoImportPath("encoding/json").Ident("Unmarshal")
+		formatInt   = protogen.GoImportPath("strconv").Ident("FormatInt")
+		trimRight   = protogen.GoImportPath("strings").Ident("TrimRight")
+		sprintf     = protogen.GoImportPath("fmt").Ident("Sprintf")
+		typeName    = durationTypeName
+		receiver    = "d " + typeName
+		ptrReceiver = "d *" + typeName
+	)
+	g.P("// Code generated by hz. DO NOT EDIT.")
+	g.P()
+	g.P("package ", f.GoPackageName)
+	g.P()
+	g.P("// ", typeName, " is the google.protobuf.Duration in the models, it is encoded as the seconds with the suffix \"s\"")
+	g.P("// in json and text like the json mapping of protobuf, such as \"1.5s\"")
+	g.P("type ", typeName, " ", duration)
+	g.P()
+	g.P("// String returns the seconds of the duration with the suffix \"s\", such as \"1.5s\"")
+	g.P("func (", receiver, ") String() string {")
+	g.P("v, sign := ", duration, "(d), \"\"")
+	g.P("if v < 0 {")
+	g.P("v, sign = -v, \"-\"")
+	g.P("}")
+	g.P("s := sign + ", formatInt, "(int64(v/", second, "), 10)")
+	g.P("if nanos := v % ", second, "; nanos != 0 {")
+	g.P("s += ", trimRight, "(", sprintf, "(\".%09d\", nanos), \"0\")")
+	g.P("}")
+	g.P("return s + \"s\"")
+	g.P("}")
+	g.P()
+	g.P("func (", receiver, ") MarshalText() ([]byte, error) {")
+	g.P("return []byte(d.String()), nil")
+	g.P("}")
+	g.P()
+	g.P("// UnmarshalText parses the duration such as \"1.5s\", the other units of time.ParseDuration are accepted too")
+	g.P("func (", ptrReceiver, ") UnmarshalText(text []byte) error {")
+	g.P("v, err := ", parse, "(string(text))")
+	g.P("if err != nil {")
+	g.P("return err")
+	g.P("}")
+	g.P("*d = ", typeName, "(v)")
+	g.P("return nil")
+	g.P("}")
+	g.P()
+	g.P("func (", receiver, ") MarshalJSON() ([]byte, error) {")
+	g.P("return ", marshal, "(d.String())")
+	g.P("}")
+	g.P()
+	g.P("func (", ptrReceiver, ") UnmarshalJSON(data []byte) error {")
+	g.P("var s string")
+	g.P("if err := ", unmarshal, "(data, &s); err != nil {")
+	g.P("return err")
+	g.P("}")
+	g.P("return d.UnmarshalText([]byte(s))")
+	g.P("}")
+	return nil
+}
+
+// usesDuration reports whether the models of the file have the fields of google.protobuf.Duration
+func usesDuration(f *fileInfo) bool {
+	for _, m := range f.allMessages {
+		for _, field := range m.Fields {
+			if _, ok := getWellKnownType(field.Desc); ok && field.Desc.Message().FullName() == durationFullName {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protobuf
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+func TestWellKnownGoType(t *testing.T) {
+	gen := loadTestIDL(t, "wkt.proto")
+	wellKnownGoType = true
+	durationFiles = map[protogen.GoImportPath]bool{}
+	defer func() { wellKnownGoType = false }()
+
+	file := gen.FilesByPath["wkt.proto"]
+	g, err := generateFile(gen, file, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := g.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := strings.Join(strings.Fields(string(content)), " ")
+	for _, want := range []string{
+		"CreatedAt time.Time `form:\"created_at\" json:\"created_at,omitempty\"",
+		"Age *int64 `form:\"age\" json:\"age,omitempty\"",
+		"Extra map[string]interface{} `form:\"extra\" json:\"extra,omitempty\"",
+		"Logins []*timestamppb.Timestamp `protobuf:",
+		"func (x *User) GetCreatedAt() time.Time {",
+		"func (x *User) GetAge() *int64 {",
+		"Ttl Duration `form:\"ttl\" json:\"ttl,omitempty\"",
+		"func (x *User) GetTtl() Duration {",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want '%s' in:\n%s", want, content)
+		}
+	}
+	if strings.Contains(out, "user.User.created_at:type_name") {
+		t.Errorf("the field 'created_at' should not be in the protobuf descriptor:\n%s", content)
+	}
+	if len(file.Proto.GetMessageType()[0].GetField()) != 6 || len(file.Messages[0].Fields) != 6 {
+		t.Errorf("the fields of the idl should be restored after generating")
+	}
+
+	var duration string
+	for _, f := range gen.Response().GetFile() {
+		if filepath.Base(f.GetName()) == durationFileName {
+			duration = f.GetContent()
+		}
+	}
+	if duration == "" {
+		t.Fatalf("want the file '%s' of the Duration type", durationFileName)
+	}
+	checkDurationType(t, duration)
+}
+
+// checkDurationType type-checks the generated Duration type, the value is marshaled and the pointer is unmarshaled
+// by json and text
+func checkDurationType(t *testing.T, duration string) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, durationFileName, duration, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imp := importer.ForCompiler(fset, "source", nil)
+	pkg, err := (&types.Config{Importer: imp}).Check("example.com/user", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatalf("type-check the Duration type failed, err: %v, code:\n%s", err, duration)
+	}
+	obj := pkg.Scope().Lookup(durationTypeName)
+	if obj == nil {
+		t.Fatalf("want the type '%s' in:\n%s", durationTypeName, duration)
+	}
+	for _, c := range []struct {
+		pkg, iface string
+		typ        types.Type
+	}{
+		{"encoding/json", "Marshaler", obj.Type()},
+		{"encoding/json", "Unmarshaler", types.NewPointer(obj.Type())},
+		{"encoding", "TextMarshaler", obj.Type()},
+		{"encoding", "TextUnmarshaler", types.NewPointer(obj.Type())},
+		{"fmt", "Stringer", obj.Type()},
+	} {
+		p, err := imp.Import(c.pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iface := p.Scope().Lookup(c.iface).Type().Underlying().(*types.Interface)
+		if !types.Implements(c.typ, iface) {
+			t.Errorf("want '%s' implements %s.%s", c.typ, p.Name(), c.iface)
+		}
+	}
+}
+
+func TestWellKnownGoTypeRequest(t *testing.T) {
+	gen := loadTestIDL(t, "wkt_request.proto")
+	if err := checkWellKnownRequests(gen.Files); err != nil {
+		t.Fatalf("the option is disabled, got: %v", err)
+	}
+
+	wellKnownGoType = true
+	defer func() { wellKnownGoType = false }()
+	err := checkWellKnownRequests(gen.Files)
+	if err == nil || !strings.Contains(err.Error(), "'user.Info.created_at'") {
+		t.Fatalf("want the error of the nested field in the request, got: %v", err)
+	}
+}
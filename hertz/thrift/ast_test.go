@@ -0,0 +1,101 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thrift
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/cmd/hz/config"
+	"github.com/cloudwego/hertz/cmd/hz/generator/model"
+	"github.com/cloudwego/hertz/cmd/hz/meta"
+	"github.com/cloudwego/thriftgo/generator/backend"
+	"github.com/cloudwego/thriftgo/generator/golang"
+	"github.com/hu-1996/cwgo/hertz/generator"
+)
+
+// loadServices converts the services of the idl in test_data for the command
+func loadServices(t *testing.T, idl, cmdType string) ([]*generator.Service, error) {
+	ast := loadRequest(t, idl).AST
+	thriftgoUtil = golang.NewCodeUtils(backend.DummyLogFunc())
+	main := &model.Model{FilePath: ast.Filename, Package: "example.com/" + getGoPackage(ast, nil), PackageName: getGoPackage(ast, nil)}
+	rs, err := NewResolver(ast, main, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rs.LoadAll(ast); err != nil {
+		t.Fatal(err)
+	}
+	return astToService(ast, rs, &config.Argument{CmdType: cmdType})
+}
+
+func TestAstToServiceStream(t *testing.T) {
+	services, err := loadServices(t, "./test_data/stream.thrift", meta.CmdUpdate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || len(services[0].Methods) != 5 {
+		t.Fatalf("want the 5 methods of the service, got: %v", services)
+	}
+	want := map[string]string{
+		"Echo":   "",
+		"Unary":  "",
+		"Watch":  generator.StreamSSE,
+		"Upload": generator.StreamWebSocket,
+		"Chat":   generator.StreamWebSocket,
+	}
+	for _, m := range services[0].Methods {
+		if m.Stream != want[m.Name] {
+			t.Errorf("want the stream '%s' of the method '%s', got: '%s'", want[m.Name], m.Name, m.Stream)
+		}
+	}
+
+	if _, err = loadServices(t, "./test_data/invalid_stream.thrift", meta.CmdUpdate); err == nil {
+		t.Error("want the error of the invalid streaming mode")
+	}
+}
+
+func TestAstToServiceMiddlewares(t *testing.T) {
+	services, err := loadServices(t, "./test_data/middleware.thrift", meta.CmdClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || len(services[0].Methods) != 2 || len(services[0].ClientMethods) != 2 {
+		t.Fatalf("want the 2 methods of the service, got: %v", services)
+	}
+	want := map[string]string{
+		"GetUser":  "trace,auth,ratelimit",
+		"ListUser": "trace",
+	}
+	for _, m := range services[0].Methods {
+		if got := strings.Join(m.Middlewares, ","); got != want[m.Name] {
+			t.Errorf("want the middlewares '%s' of the method '%s', got: '%s'", want[m.Name], m.Name, got)
+		}
+		if got := strings.Join(m.ServiceMiddlewares, ","); got != "trace" {
+			t.Errorf("want the service middlewares 'trace' of the method '%s', got: '%s'", m.Name, got)
+		}
+	}
+	client := services[0].ClientMethods[0]
+	if client.Name != "GetUser" || client.Timeout != 500*time.Millisecond || client.RetryTimes != 2 {
+		t.Errorf("want the timeout 500ms and 2 retries of 'GetUser', got: %v, %d", client.Timeout, client.RetryTimes)
+	}
+
+	if _, err = loadServices(t, "./test_data/invalid_middleware.thrift", meta.CmdUpdate); err == nil {
+		t.Error("want the error of the invalid middleware")
+	}
+}
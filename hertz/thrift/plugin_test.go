@@ -0,0 +1,143 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thrift
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/cmd/hz/meta"
+	"github.com/cloudwego/thriftgo/parser"
+	"github.com/cloudwego/thriftgo/plugin"
+	"github.com/cloudwego/thriftgo/semantic"
+	"github.com/hu-1996/cwgo/hertz/generator"
+)
+
+// loadRequest builds the request of the plugin from the idl like thriftgo, the params are the arguments of hz
+func loadRequest(t *testing.T, idl string, params ...string) *plugin.Request {
+	ast, err := parser.ParseFile(idl, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := semantic.NewChecker(semantic.Options{FixWarnings: true})
+	if _, err = checker.CheckAll(ast); err != nil {
+		t.Fatal(err)
+	}
+	if err = semantic.ResolveSymbols(ast); err != nil {
+		t.Fatal(err)
+	}
+	return &plugin.Request{
+		Language:         "go",
+		OutputPath:       t.TempDir(),
+		Recursive:        true,
+		AST:              ast,
+		PluginParameters: params,
+	}
+}
+
+func TestRun(t *testing.T) {
+	out := t.TempDir()
+	plu := new(Plugin)
+	plu.setLogger()
+
+	plu.req = loadRequest(t, "./test_data/psm.thrift",
+		"CmdType=new", "Cwd="+out, "OutDir="+out, "IdlType=thrift", "IdlPaths=./test_data/psm.thrift", "Gomod=example.com/psm")
+
+	_, err := plu.parseArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := CheckTagOption(plu.args)
+
+	pkgInfo, err := plu.getPackageInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := plu.args
+	customPackageTemplate := args.CustomizePackage
+	pkg, err := args.GetGoPackage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlerDir, err := args.GetHandlerDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	routerDir, err := args.GetRouterDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelDir, err := args.GetModelDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientDir, err := args.GetClientDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sg := generator.HttpPackageGenerator{
+		ConfigPath: customPackageTemplate,
+		HandlerDir: handlerDir,
+		RouterDir:  routerDir,
+		ModelDir:   modelDir,
+		UseDir:     args.Use,
+		ClientDir:  clientDir,
+		TemplateGenerator: generator.TemplateGenerator{
+			OutputDir: args.OutDir,
+			Excludes:  args.Excludes,
+		},
+		ProjPackage:          pkg,
+		Options:              options,
+		HandlerByMethod:      args.HandlerByMethod,
+		CmdType:              args.CmdType,
+		ForceClientDir:       args.ForceClientDir,
+		BaseDomain:           args.BaseDomain,
+		QueryEnumAsInt:       args.QueryEnumAsInt,
+		SnakeStyleMiddleware: args.SnakeStyleMiddleware,
+		SortRouter:           args.SortRouter,
+	}
+	if args.ModelBackend != "" {
+		sg.Backend = meta.Backend(args.ModelBackend)
+	}
+
+	err = sg.Generate(pkgInfo)
+	if err != nil {
+		t.Fatalf("generate package failed: %v", err)
+	}
+	files, err := sg.GetFormatAndExcludedFiles()
+	if err != nil {
+		t.Fatalf("format files failed: %v", err)
+	}
+
+	res, err := plu.GetResponse(files, sg.OutputDir)
+	if err != nil {
+		t.Fatalf("get response failed: %v", err)
+	}
+	generated := make(map[string]bool, len(res.Contents))
+	for _, c := range res.Contents {
+		if c.InsertionPoint == nil {
+			generated[filepath.ToSlash(strings.TrimPrefix(c.GetName(), out))] = true
+		}
+	}
+	for _, want := range []string{"/biz/router/register.go", "/biz/router/toutiao/middleware/hertz/psm.go"} {
+		if !generated[want] {
+			t.Errorf("want the file '%s' in the response, got: %v", want, generated)
+		}
+	}
+}
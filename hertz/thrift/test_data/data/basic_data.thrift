@@ -0,0 +1,5 @@
+namespace go toutiao.middleware.hertz_data
+
+struct BasicDataType {
+    1: optional string IsBasicDataString;
+}
\ No newline at end of file
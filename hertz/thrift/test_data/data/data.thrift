@@ -0,0 +1,7 @@
+include "basic_data.thrift"
+
+namespace go toutiao.middleware.hertz_data
+
+struct DataType {
+    1: optional basic_data.BasicDataType IsDataString;
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+namespace go user
+
+struct UserReq {
+    1: string id
+}
+
+service UserService {
+    UserReq GetUser(1: UserReq req) (api.get="/user", api.middleware="auth rate")
+}
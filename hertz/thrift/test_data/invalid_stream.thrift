@@ -0,0 +1,7 @@
+namespace go invalid
+
+struct Req {}
+
+service InvalidService {
+    Req Echo(1: Req req) (api.get="/echo", streaming.mode="duplex")
+}
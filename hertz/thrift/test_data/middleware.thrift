@@ -0,0 +1,14 @@
+namespace go user
+
+struct UserReq {
+    1: string id (api.path="id")
+}
+
+struct UserResp {
+    1: string name
+}
+
+service UserService {
+    UserResp GetUser(1: UserReq req) (api.get="/user/:id", api.middleware="auth, ratelimit,trace", api.timeout="500ms", api.retry="2")
+    UserResp ListUser(1: UserReq req) (api.get="/user/list")
+}(api.service_middleware="trace")
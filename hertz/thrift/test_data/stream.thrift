@@ -0,0 +1,17 @@
+namespace go echo
+
+struct EchoReq {
+    1: string msg
+}
+
+struct EchoResp {
+    1: string msg
+}
+
+service EchoService {
+    EchoResp Echo(1: EchoReq req) (api.get="/echo")
+    EchoResp Unary(1: EchoReq req) (api.post="/unary", streaming.mode="unary")
+    EchoResp Watch(1: EchoReq req) (api.get="/watch", streaming.mode="server")
+    EchoResp Upload(1: EchoReq req) (api.get="/upload", streaming.mode="client")
+    EchoResp Chat(1: EchoReq req) (api.get="/chat", streaming.mode="bidirectional")
+}
@@ -41,6 +41,13 @@ func check(ca *config.ClientArgument) error {
 		return errors.New("unsupported registry")
 	}
 
+	if ca.Lang != "" && ca.Lang != consts.Go && ca.Lang != consts.TS {
+		return errors.New("client language not supported")
+	}
+	if ca.Lang == consts.TS && ca.Type != consts.HTTP {
+		return errors.New("the typescript client can only be generated for HTTP")
+	}
+
 	if ca.ServerName == "" {
 		return errors.New("must specify server name")
 	}
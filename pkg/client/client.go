@@ -29,11 +29,10 @@ import (
 	"github.com/cloudwego/kitex/tool/internal_pkg/pluginmode/thriftgo"
 	"github.com/hu-1996/cwgo/pkg/common/utils"
 
-	"github.com/cloudwego/hertz/cmd/hz/app"
+	hz "github.com/hu-1996/cwgo/hertz"
 
 	"github.com/hu-1996/cwgo/config"
 
-	hzConfig "github.com/cloudwego/hertz/cmd/hz/config"
 	"github.com/cloudwego/hertz/cmd/hz/meta"
 	"github.com/urfave/cli/v2"
 )
@@ -72,14 +71,14 @@ func Client(c *config.ClientArgument) error {
 		utils.UpgradeGolangProtobuf()
 		utils.Hessian2PostProcessing(args)
 	case consts.HTTP:
-		args := hzConfig.NewArgument()
+		args := config.NewHzArgument()
 		utils.SetHzVerboseLog(c.Verbose)
 		err = convertHzArgument(c, args)
 		if err != nil {
 			return err
 		}
 		args.CmdType = meta.CmdClient
-		err = app.TriggerPlugin(args)
+		err = hz.TriggerPlugin(args)
 		if err != nil {
 			return cli.Exit(err, meta.PluginError)
 		}
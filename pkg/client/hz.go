@@ -24,14 +24,13 @@ import (
 	"path/filepath"
 	"strings"
 
-	hzConfig "github.com/cloudwego/hertz/cmd/hz/config"
 	"github.com/hu-1996/cwgo/config"
 	"github.com/hu-1996/cwgo/pkg/common/utils"
 	"github.com/hu-1996/cwgo/pkg/consts"
 	"github.com/hu-1996/cwgo/tpl"
 )
 
-func convertHzArgument(ca *config.ClientArgument, hzArgument *hzConfig.Argument) (err error) {
+func convertHzArgument(ca *config.ClientArgument, hzArgument *config.HzArgument) (err error) {
 	// Common commands
 	abPath, err := filepath.Abs(ca.IdlPath)
 	if err != nil {
@@ -85,6 +84,10 @@ func convertHzArgument(ca *config.ClientArgument, hzArgument *hzConfig.Argument)
 	if err != nil {
 		return
 	}
+	hzArgument.ClientLang = ca.Lang
+	if ca.Lang == consts.TS && hzArgument.IdlType != consts.Proto {
+		return fmt.Errorf("the typescript client can only be generated from the protobuf idl")
+	}
 
 	// specific commands from -pass param
 	f := flag.NewFlagSet("", flag.ContinueOnError)
@@ -105,6 +108,7 @@ func convertHzArgument(ca *config.ClientArgument, hzArgument *hzConfig.Argument)
 	f.BoolVar(&hzArgument.ProtobufCamelJSONTag, "pb_camel_json_tag", false, "")
 	f.BoolVar(&hzArgument.SnakeName, "snake_tag", false, "")
 	f.BoolVar(&hzArgument.HandlerByMethod, "handler_by_method", false, "")
+	f.StringVar(&hzArgument.ModelBackend, "model_backend", "", "")
 
 	err = f.Parse(utils.StringSliceSpilt(ca.SliceParam.Pass))
 	if err != nil {
@@ -0,0 +1,245 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/hu-1996/cwgo/meta"
+)
+
+type clientInfo struct {
+	Version     string
+	PackageName string
+	ServiceName string
+	Addr        string
+	Configs     []*clientConfig
+}
+
+type clientConfig struct {
+	Version     string
+	PackageName string
+	Namespace   string
+	Key         string
+	HolderName  string
+	TypeName    string
+	IsText      bool
+	Decode      string
+	StdImports  []string
+	Imports     []string
+	Structs     string
+
+	configMeta ConfigGenerateMeta
+}
+
+// decoders of the config formats, the config is decoded to the variable "value"
+var clientDecoders = map[configFormat]struct {
+	imports []string
+	code    string
+}{
+	formatYaml: {[]string{"gopkg.in/yaml.v3"}, "if err := yaml.Unmarshal(data, value); err != nil {\n\t\treturn err\n\t}"},
+	formatJSON: {[]string{"encoding/json"}, "if err := json.Unmarshal(data, value); err != nil {\n\t\treturn err\n\t}"},
+	formatXML:  {[]string{"encoding/xml"}, "if err := xml.Unmarshal(data, value); err != nil {\n\t\treturn err\n\t}"},
+	formatTOML: {[]string{"github.com/BurntSushi/toml"}, "if err := toml.Unmarshal(data, value); err != nil {\n\t\treturn err\n\t}"},
+	formatProperties: {
+		[]string{"github.com/magiconair/properties"},
+		"p, err := properties.Load(data, properties.UTF8)\n\tif err != nil {\n\t\treturn err\n\t}\n\tif err = p.Decode(value); err != nil {\n\t\treturn err\n\t}",
+	},
+}
+
+// GenerateConfigClient generates the typed config client of the result, which loads the configs
+// of all the namespaces from the config centre, reloads them on change and notifies the callbacks
+// registered by OnChange. A file-backed Source is generated as the local stand-in of the config centre.
+// The generated files are returned by file name.
+func GenerateConfigClient(result *Result, pkgName string) (map[string]string, error) {
+	info := &clientInfo{
+		Version:     meta.Version,
+		PackageName: pkgName,
+		ServiceName: result.ServiceName,
+		Addr:        result.Addr,
+	}
+
+	// the kinds of a config, eg: dev and online, are merged by processSubConfig already,
+	// the same key of different value types is generated only once
+	seen := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, subConfig := range result.SubConfigMetadataList {
+		for _, configMeta := range subConfig.ConfigMetadata {
+			id := subConfig.Namespace + "/" + configMeta.Key
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			name := goIdentifier(subConfig.Namespace) + goKeyFormat(configMeta.Key)
+			for i := 2; names[name]; i++ {
+				name = fmt.Sprintf("%s%s%d", goIdentifier(subConfig.Namespace), goKeyFormat(configMeta.Key), i)
+			}
+			names[name] = true
+
+			info.Configs = append(info.Configs, &clientConfig{
+				Version:     meta.Version,
+				PackageName: pkgName,
+				Namespace:   subConfig.Namespace,
+				Key:         configMeta.Key,
+				HolderName:  name,
+				TypeName:    name,
+				configMeta:  renameConfigStructs(configMeta, name),
+			})
+		}
+	}
+
+	files := make(map[string]string)
+	if err := renderClientFile(files, "source.go", sourceTemplate, info); err != nil {
+		return nil, err
+	}
+	if err := renderClientFile(files, "client.go", clientTemplate, info); err != nil {
+		return nil, err
+	}
+	for _, c := range info.Configs {
+		fileName := strings.ToLower(c.HolderName)
+		if err := c.prepare(); err != nil {
+			return nil, err
+		}
+		if err := renderClientFile(files, fileName+".go", holderTemplate, c); err != nil {
+			return nil, err
+		}
+		if c.IsText {
+			continue
+		}
+		validator, err := GenerateValidator(&c.configMeta, pkgName)
+		if err != nil {
+			return nil, err
+		}
+		files[fileName+"_validator.go"] = validator
+	}
+	return files, nil
+}
+
+// prepare renders the structs and determines the decoder of the config
+func (c *clientConfig) prepare() error {
+	imports := map[string]bool{"sync": true}
+	decoder, ok := clientDecoders[configFormatOf(c.Key, c.configMeta.ConfigValueType)]
+	if !ok || len(c.configMeta.ConfigStruct.Fields) == 0 {
+		// the config which can't be converted is held as text
+		c.IsText = true
+		c.Structs = fmt.Sprintf("// %s is the content of %s in namespace %s\ntype %s string", c.TypeName, c.Key, c.Namespace, c.TypeName)
+	} else {
+		for _, imp := range decoder.imports {
+			imports[imp] = true
+		}
+		c.Decode = decoder.code
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("// %s is generated from %s in namespace %s\n", c.TypeName, c.Key, c.Namespace))
+		renderConfigStruct(&sb, c.TypeName, c.configMeta.ConfigStruct.Fields, make(map[string]bool))
+		c.Structs = sb.String()
+		if strings.Contains(c.Structs, "time.") {
+			imports["time"] = true
+		}
+	}
+
+	for imp := range imports {
+		if strings.Contains(strings.Split(imp, "/")[0], ".") {
+			c.Imports = append(c.Imports, imp)
+		} else {
+			c.StdImports = append(c.StdImports, imp)
+		}
+	}
+	sort.Strings(c.StdImports)
+	sort.Strings(c.Imports)
+	return nil
+}
+
+func renderClientFile(files map[string]string, name, tpl string, data interface{}) error {
+	t, err := template.New(name).Parse(tpl)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, data); err != nil {
+		return fmt.Errorf("render %s failed: %w", name, err)
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format %s failed: %w", name, err)
+	}
+	files[name] = string(code)
+	return nil
+}
+
+// renameConfigStructs prefixes the struct names of the config to avoid conflicts between configs
+func renameConfigStructs(configMeta ConfigGenerateMeta, prefix string) ConfigGenerateMeta {
+	configMeta.ConfigStruct = Struct{
+		StructName: prefix,
+		Fields:     renameFields(configMeta.ConfigStruct.Fields, prefix),
+	}
+	return configMeta
+}
+
+func renameFields(fields []Field, prefix string) []Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	result := make([]Field, len(fields))
+	for i, f := range fields {
+		f.Children = renameFields(f.Children, prefix)
+		if f.IsStruct {
+			t := elemType(f)
+			f.FieldType = strings.Replace(f.FieldType, t, prefix+t, 1)
+		}
+		result[i] = f
+	}
+	return result
+}
+
+func renderConfigStruct(sb *strings.Builder, name string, fields []Field, seen map[string]bool) {
+	if seen[name] {
+		return
+	}
+	seen[name] = true
+
+	sb.WriteString(fmt.Sprintf("type %s struct {\n", name))
+	for _, f := range fields {
+		tags := make([]string, 0, len(f.Tags))
+		for _, tag := range f.Tags {
+			tags = append(tags, fmt.Sprintf("%s:\"%s\"", tag.TagKey, tag.TagValue))
+		}
+		sb.WriteString(fmt.Sprintf("\t%s %s `%s`\n", f.FieldName, f.FieldType, strings.Join(tags, " ")))
+	}
+	sb.WriteString("}\n\n")
+
+	for _, f := range fields {
+		if f.IsStruct {
+			renderConfigStruct(sb, elemType(f), f.Children, seen)
+		}
+	}
+}
+
+// goIdentifier converts the namespace to go identifier, eg: "public" -> "Public"
+func goIdentifier(s string) string {
+	id := goKeyFormat(s)
+	if len(id) == 0 || id[0] < 'A' || id[0] > 'Z' {
+		id = "N" + id
+	}
+	return id
+}
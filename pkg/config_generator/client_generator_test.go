@@ -0,0 +1,83 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_generator
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestGenerateConfigClient(t *testing.T) {
+	addr, pkgName := "127.0.0.1:8848", "conf"
+	result, err := HandleRequest(&Config{
+		ServiceName:   "nacos_config_server",
+		Addr:          &addr,
+		ClientPackage: &pkgName,
+		SubConfigList: []*SubConfig{
+			{
+				NameSpace: "public",
+				ConfigKvPairList: []*ConfigKvPair{
+					{Key: "conf.yaml", ValueType: ConfigValueType_YamlType, Kind: "dev", Value: value},
+					{Key: "conf.yaml", ValueType: ConfigValueType_YamlType, Kind: "online", Value: value},
+					{Key: "readme", ValueType: ConfigValueType_TextType, Value: "hello"},
+				},
+			},
+			{
+				NameSpace: "db",
+				ConfigKvPairList: []*ConfigKvPair{
+					{Key: "mysql.json", ValueType: ConfigValueType_JsonType, Value: `{"kitex": {"dsn": "dsn", "timeout": 1.5}}`},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := result.ClientFiles
+	for _, name := range []string{"source.go", "client.go", "publicconfyaml.go", "publicconfyaml_validator.go", "publicreadme.go", "dbmysqljson.go"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("%s is not generated", name)
+		}
+	}
+	if strings.Count(files["client.go"], "c.load(") != 3 {
+		t.Errorf("the config of different kinds should be loaded once:\n%s", files["client.go"])
+	}
+	if !strings.Contains(files["publicconfyaml.go"], "Kitex PublicConfYamlKitex") || !strings.Contains(files["dbmysqljson.go"], "Kitex DbMysqlJsonKitex") {
+		t.Errorf("nested structs should be prefixed to avoid conflicts")
+	}
+
+	// the generated client must compile
+	fset := token.NewFileSet()
+	var astFiles []*ast.File
+	for name, src := range files {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		astFiles = append(astFiles, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err = conf.Check("conf", fset, astFiles, nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_generator
+
+const sourceTemplate = `// Code generated by cwgo ({{.Version}}). DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Source is the config centre which the configs are loaded from, eg: nacos, etcd and apollo.
+type Source interface {
+	// Get returns the content of the config
+	Get(namespace, key string) ([]byte, error)
+	// Watch calls onChange with the new content after the config is changed,
+	// the returned function stops watching.
+	Watch(namespace, key string, onChange func(data []byte)) (cancel func(), err error)
+}
+
+// FileSource is the local stand-in of the config centre, which reads the config from
+// <Dir>/<namespace>/<key> and polls the file to watch the changes.
+type FileSource struct {
+	Dir      string
+	Interval time.Duration
+}
+
+// NewFileSource creates a FileSource which polls the files every second
+func NewFileSource(dir string) *FileSource {
+	return &FileSource{Dir: dir, Interval: time.Second}
+}
+
+func (s *FileSource) path(namespace, key string) string {
+	return filepath.Join(s.Dir, namespace, key)
+}
+
+// Get reads the content of the config file
+func (s *FileSource) Get(namespace, key string) ([]byte, error) {
+	return os.ReadFile(s.path(namespace, key))
+}
+
+// Watch polls the config file and calls onChange when the content is changed
+func (s *FileSource) Watch(namespace, key string, onChange func(data []byte)) (func(), error) {
+	path := s.path(namespace, key)
+	last, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				data, err := os.ReadFile(path)
+				if err != nil || bytes.Equal(data, last) {
+					continue
+				}
+				last = data
+				onChange(data)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}, nil
+}
+`
+
+const clientTemplate = `// Code generated by cwgo ({{.Version}}). DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// ServiceName is the name of the service which the configs belong to
+const ServiceName = "{{.ServiceName}}"
+
+// Addr is the address of the config centre
+const Addr = "{{.Addr}}"
+
+// Client loads the configs from the config centre and reloads them on change
+type Client struct {
+	source Source
+
+	mu      sync.Mutex
+	cancels []func()
+{{- range .Configs}}
+
+	// {{.HolderName}} holds {{.Key}} in namespace {{.Namespace}}
+	{{.HolderName}} *{{.HolderName}}Holder
+{{- end}}
+}
+
+// NewClient creates the config client, call Load to load the configs
+func NewClient(source Source) *Client {
+	return &Client{
+		source: source,
+{{- range .Configs}}
+		{{.HolderName}}: &{{.HolderName}}Holder{},
+{{- end}}
+	}
+}
+
+// Load loads all the configs and watches their changes
+func (c *Client) Load() error {
+{{- range .Configs}}
+	if err := c.load("{{.Namespace}}", "{{.Key}}", c.{{.HolderName}}.update); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+
+func (c *Client) load(namespace, key string, update func(data []byte) error) error {
+	data, err := c.source.Get(namespace, key)
+	if err != nil {
+		return fmt.Errorf("get config '%s/%s' failed: %w", namespace, key, err)
+	}
+	if err = update(data); err != nil {
+		return fmt.Errorf("load config '%s/%s' failed: %w", namespace, key, err)
+	}
+
+	cancel, err := c.source.Watch(namespace, key, func(data []byte) {
+		// the old value is kept if the new one is invalid
+		if err := update(data); err != nil {
+			log.Printf("reload config '%s/%s' failed: %v", namespace, key, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("watch config '%s/%s' failed: %w", namespace, key, err)
+	}
+	c.mu.Lock()
+	c.cancels = append(c.cancels, cancel)
+	c.mu.Unlock()
+	return nil
+}
+
+// Close stops watching the configs
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cancel := range c.cancels {
+		cancel()
+	}
+	c.cancels = nil
+}
+`
+
+const holderTemplate = `// Code generated by cwgo ({{.Version}}). DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+{{- range .StdImports}}
+	"{{.}}"
+{{- end}}
+{{- if .Imports}}
+{{range .Imports}}
+	"{{.}}"
+{{- end}}
+{{- end}}
+)
+
+{{.Structs}}
+
+// {{.HolderName}}Holder holds the latest value of {{.Key}} in namespace {{.Namespace}}
+type {{.HolderName}}Holder struct {
+	mu        sync.RWMutex
+	value     *{{.TypeName}}
+	callbacks []func(old, new *{{.TypeName}})
+}
+
+// Get returns the latest value of the config
+func (h *{{.HolderName}}Holder) Get() *{{.TypeName}} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.value
+}
+
+// OnChange registers the callback which is called with the old and new value after the config is reloaded
+func (h *{{.HolderName}}Holder) OnChange(callback func(old, new *{{.TypeName}})) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.callbacks = append(h.callbacks, callback)
+}
+
+func (h *{{.HolderName}}Holder) update(data []byte) error {
+{{- if .IsText}}
+	value := {{.TypeName}}(data)
+{{- else}}
+	value := new({{.TypeName}})
+	{{.Decode}}
+	value.ApplyDefaults()
+	if err := value.Validate(); err != nil {
+		return err
+	}
+{{- end}}
+
+	current := {{if .IsText}}&{{end}}value
+	h.mu.Lock()
+	old := h.value
+	h.value = current
+	callbacks := make([]func(old, new *{{.TypeName}}), len(h.callbacks))
+	copy(callbacks, h.callbacks)
+	h.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(old, current)
+	}
+	return nil
+}
+`
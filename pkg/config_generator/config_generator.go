@@ -81,6 +81,7 @@ type Config struct {
 	ServiceName   string       `thrift:"ServiceName,1" json:"ServiceName"`
 	SubConfigList []*SubConfig `thrift:"SubConfigList,2" json:"SubConfigList"`
 	Addr          *string      `thrift:"addr,3,optional" json:"addr,omitempty"`
+	ClientPackage *string      `thrift:"client_package,4,optional" json:"client_package,omitempty"`
 }
 
 func NewConfig() *Config {
@@ -107,16 +108,30 @@ func (p *Config) GetAddr() (v string) {
 	return *p.Addr
 }
 
+var Config_ClientPackage_DEFAULT string
+
+func (p *Config) GetClientPackage() (v string) {
+	if !p.IsSetClientPackage() {
+		return Config_ClientPackage_DEFAULT
+	}
+	return *p.ClientPackage
+}
+
 var fieldIDToName_Config = map[int16]string{
 	1: "ServiceName",
 	2: "SubConfigList",
 	3: "addr",
+	4: "client_package",
 }
 
 func (p *Config) IsSetAddr() bool {
 	return p.Addr != nil
 }
 
+func (p *Config) IsSetClientPackage() bool {
+	return p.ClientPackage != nil
+}
+
 func (p *Config) Read(iprot thrift.TProtocol) (err error) {
 
 	var fieldTypeId thrift.TType
@@ -160,6 +175,14 @@ func (p *Config) Read(iprot thrift.TProtocol) (err error) {
 			} else if err = iprot.Skip(fieldTypeId); err != nil {
 				goto SkipFieldError
 			}
+		case 4:
+			if fieldTypeId == thrift.STRING {
+				if err = p.ReadField4(iprot); err != nil {
+					goto ReadFieldError
+				}
+			} else if err = iprot.Skip(fieldTypeId); err != nil {
+				goto SkipFieldError
+			}
 		default:
 			if err = iprot.Skip(fieldTypeId); err != nil {
 				goto SkipFieldError
@@ -234,6 +257,17 @@ func (p *Config) ReadField3(iprot thrift.TProtocol) error {
 	p.Addr = _field
 	return nil
 }
+func (p *Config) ReadField4(iprot thrift.TProtocol) error {
+
+	var _field *string
+	if v, err := iprot.ReadString(); err != nil {
+		return err
+	} else {
+		_field = &v
+	}
+	p.ClientPackage = _field
+	return nil
+}
 
 func (p *Config) Write(oprot thrift.TProtocol) (err error) {
 	var fieldId int16
@@ -253,6 +287,10 @@ func (p *Config) Write(oprot thrift.TProtocol) (err error) {
 			fieldId = 3
 			goto WriteFieldError
 		}
+		if err = p.writeField4(oprot); err != nil {
+			fieldId = 4
+			goto WriteFieldError
+		}
 	}
 	if err = oprot.WriteFieldStop(); err != nil {
 		goto WriteFieldStopError
@@ -332,6 +370,25 @@ WriteFieldEndError:
 	return thrift.PrependError(fmt.Sprintf("%T write field 3 end error: ", p), err)
 }
 
+func (p *Config) writeField4(oprot thrift.TProtocol) (err error) {
+	if p.IsSetClientPackage() {
+		if err = oprot.WriteFieldBegin("client_package", thrift.STRING, 4); err != nil {
+			goto WriteFieldBeginError
+		}
+		if err := oprot.WriteString(*p.ClientPackage); err != nil {
+			return err
+		}
+		if err = oprot.WriteFieldEnd(); err != nil {
+			goto WriteFieldEndError
+		}
+	}
+	return nil
+WriteFieldBeginError:
+	return thrift.PrependError(fmt.Sprintf("%T write field 4 begin error: ", p), err)
+WriteFieldEndError:
+	return thrift.PrependError(fmt.Sprintf("%T write field 4 end error: ", p), err)
+}
+
 func (p *Config) String() string {
 	if p == nil {
 		return "<nil>"
@@ -0,0 +1,350 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type configFormat int
+
+const (
+	formatYaml configFormat = iota
+	formatJSON
+	formatText
+	formatXML
+	formatTOML
+	formatProperties
+)
+
+const (
+	// xmlAttrPrefix marks the keys decoded from xml attributes
+	xmlAttrPrefix = "@"
+	// xmlTextKey is the key of the char data of an xml element which also has attributes or children
+	xmlTextKey = "#text"
+)
+
+// configFormatOf determines the format of the config content. Json is decoded by the yaml decoder,
+// TextType is distinguished by the extension of the key, eg: conf.toml, conf.properties.
+func configFormatOf(key string, valueType ConfigValueType) configFormat {
+	switch valueType {
+	case ConfigValueType_YamlType:
+		return formatYaml
+	case ConfigValueType_JsonType:
+		return formatJSON
+	case ConfigValueType_XmlType:
+		return formatXML
+	case ConfigValueType_TextType:
+		switch strings.ToLower(filepath.Ext(key)) {
+		case ".toml":
+			return formatTOML
+		case ".properties":
+			return formatProperties
+		}
+	}
+	return formatText
+}
+
+// decode unmarshal the config content to map[string]interface{}
+func (f configFormat) decode(data []byte) (map[string]interface{}, error) {
+	switch f {
+	case formatYaml, formatJSON:
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case formatXML:
+		return decodeXML(data)
+	case formatTOML:
+		return decodeTOML(data)
+	case formatProperties:
+		return decodeProperties(data)
+	default:
+		return nil, errors.New("text config can not be converted to struct")
+	}
+}
+
+// decodeXML decodes the root element of the xml document. Attributes are stored with
+// the xmlAttrPrefix, repeated elements are stored as slice.
+func decodeXML(data []byte) (map[string]interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("xml root element not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		root, err := decodeXMLElement(decoder, start)
+		if err != nil {
+			return nil, err
+		}
+		if obj, ok := root.(map[string]interface{}); ok {
+			return obj, nil
+		}
+		return map[string]interface{}{xmlTextKey: root}, nil
+	}
+}
+
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	node := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		// namespace declarations are not part of the config
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		node[xmlAttrPrefix+attr.Name.Local] = inferScalar(attr.Value)
+	}
+
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			existing, ok := node[name]
+			switch {
+			case !ok:
+				node[name] = child
+			case isSlice(existing):
+				node[name] = append(existing.([]interface{}), child)
+			default:
+				node[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			// leaf element
+			if len(node) == 0 {
+				return inferScalar(s), nil
+			}
+			if len(s) > 0 {
+				node[xmlTextKey] = inferScalar(s)
+			}
+			return node, nil
+		}
+	}
+}
+
+func isSlice(v interface{}) bool {
+	_, ok := v.([]interface{})
+	return ok
+}
+
+// decodeTOML decodes the toml document, array of tables and integers are normalized
+// to the types produced by the yaml decoder.
+func decodeTOML(data []byte) (map[string]interface{}, error) {
+	var obj map[string]interface{}
+	if err := toml.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	return normalizeTOMLValue(obj).(map[string]interface{}), nil
+}
+
+func normalizeTOMLValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, v1 := range val {
+			val[k] = normalizeTOMLValue(v1)
+		}
+		return val
+	case []map[string]interface{}:
+		list := make([]interface{}, 0, len(val))
+		for _, v1 := range val {
+			list = append(list, normalizeTOMLValue(v1))
+		}
+		return list
+	case []interface{}:
+		for i, v1 := range val {
+			val[i] = normalizeTOMLValue(v1)
+		}
+		return val
+	case int64:
+		return int(val)
+	}
+	return v
+}
+
+// decodeProperties decodes java style properties, dotted keys are decoded as nested objects,
+// eg: "server.port=8080" is decoded as {"server": {"port": 8080}}.
+func decodeProperties(data []byte) (map[string]interface{}, error) {
+	obj := make(map[string]interface{})
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var logical string
+	for scanner.Scan() {
+		l := strings.TrimLeft(scanner.Text(), " \t\f")
+		if len(logical) == 0 && (len(l) == 0 || l[0] == '#' || l[0] == '!') {
+			continue
+		}
+		// a line ends with an odd number of backslashes continues on the next line
+		if trailingBackslashes(l)%2 == 1 {
+			logical += l[:len(l)-1]
+			continue
+		}
+		logical += l
+
+		key, value := splitProperty(logical)
+		logical = ""
+		if err := setProperty(obj, key, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(logical) > 0 {
+		key, value := splitProperty(logical)
+		if err := setProperty(obj, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+func trailingBackslashes(s string) int {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+	return n
+}
+
+// splitProperty splits the logical line into key and value, the key is terminated
+// by the first unescaped '=', ':' or white space.
+func splitProperty(l string) (string, string) {
+	end := len(l)
+	for i := 0; i < len(l); i++ {
+		if l[i] == '\\' {
+			i++
+			continue
+		}
+		if l[i] == '=' || l[i] == ':' || l[i] == ' ' || l[i] == '\t' || l[i] == '\f' {
+			end = i
+			break
+		}
+	}
+	key, rest := l[:end], l[end:]
+	rest = strings.TrimLeft(rest, " \t\f")
+	if len(rest) > 0 && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return unescapeProperty(key), unescapeProperty(rest)
+}
+
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+4 < len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					sb.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			sb.WriteByte('u')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+func setProperty(obj map[string]interface{}, key, value string) error {
+	segments := strings.Split(key, ".")
+	node := obj
+	for i, segment := range segments[:len(segments)-1] {
+		child, ok := node[segment]
+		if !ok {
+			child = make(map[string]interface{})
+			node[segment] = child
+		}
+		m, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("properties key '%s' conflicts with key '%s'", key, strings.Join(segments[:i+1], "."))
+		}
+		node = m
+	}
+	last := segments[len(segments)-1]
+	if _, ok := node[last].(map[string]interface{}); ok {
+		return fmt.Errorf("properties key '%s' conflicts with keys prefixed by '%s.'", key, key)
+	}
+	node[last] = inferScalar(value)
+	return nil
+}
+
+// inferScalar converts the text value of xml and properties to int, float64 or bool if possible
+func inferScalar(s string) interface{} {
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	if strings.ContainsAny(s, ".eE") && !strings.ContainsAny(s, "nNiI") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
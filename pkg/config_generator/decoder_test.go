@@ -0,0 +1,119 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func findField(fields []Field, name string) *Field {
+	for i := range fields {
+		if fields[i].FieldName == name {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+func TestXml2Go(t *testing.T) {
+	metas, err := processConfigKvPairs([]*ConfigKvPair{{
+		Key:       "conf.xml",
+		ValueType: ConfigValueType_XmlType,
+		Value:     xmlValue,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := metas[0].ConfigStruct.Fields
+
+	version := findField(fields, "Version")
+	if version == nil || version.Tags[0].TagValue != "version,attr" {
+		t.Fatalf("attribute field is not generated: %+v", version)
+	}
+	service := findField(fields, "Service")
+	if service == nil || service.FieldType != "string" || service.Tags[0].TagValue != "service" {
+		t.Fatalf("element field is not generated: %+v", service)
+	}
+	port := findField(fields, "Port")
+	if port == nil || port.FieldType != "[]int" {
+		t.Fatalf("repeated element is not generated as slice: %+v", port)
+	}
+}
+
+func TestToml2Go(t *testing.T) {
+	content := `
+title = "kitex"
+
+[server]
+port = 8888
+timeout = 1.5
+
+[[backends]]
+addr = "127.0.0.1"
+`
+	yaml2Go := New("conf.toml", "", "", ConfigValueType_TextType)
+	s, err := yaml2Go.Convert("conf.toml", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"`toml:\"title\"`", "Port    int     `toml:\"port\"`", "Backends []Backends `toml:\"backends\"`"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("generated struct does not contain %s:\n%s", want, s)
+		}
+	}
+}
+
+func TestProperties2Go(t *testing.T) {
+	content := `
+# comment
+server.port=8080
+server.host : localhost
+app.name = hello \
+    world
+app.enabled true
+`
+	obj, err := decodeProperties([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := obj["server"].(map[string]interface{})
+	if server["port"] != 8080 || server["host"] != "localhost" {
+		t.Errorf("unexpected server: %v", server)
+	}
+	app := obj["app"].(map[string]interface{})
+	if app["name"] != "hello world" || app["enabled"] != true {
+		t.Errorf("unexpected app: %v", app)
+	}
+
+	if _, err = decodeProperties([]byte("a=1\na.b=2")); err == nil {
+		t.Error("conflict keys should return error")
+	}
+
+	metas, err := processConfigKvPairs([]*ConfigKvPair{{
+		Key:       "application.properties",
+		ValueType: ConfigValueType_TextType,
+		Value:     content,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverField := findField(metas[0].ConfigStruct.Fields, "Server")
+	if serverField == nil || serverField.Tags[0].TagKey != "properties" || findField(serverField.Children, "Port") == nil {
+		t.Fatalf("nested properties struct is not generated: %+v", serverField)
+	}
+}
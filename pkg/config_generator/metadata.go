@@ -16,12 +16,15 @@
 
 package config_generator
 
+import "encoding/json"
+
 type ConfigGenerateMeta struct {
 	Desc            string          `json:"desc,omitempty"`
 	Kind            string          `json:"kind,omitempty"`
 	ConfigStruct    Struct          `json:"config_struct,omitempty"`
 	ConfigValueType ConfigValueType `json:"config_value_type,omitempty"`
 	Key             string          `json:"key,omitempty"`
+	JSONSchema      json.RawMessage `json:"json_schema,omitempty"` // the json schema of ConfigStruct
 
 	structTree map[string]Struct `json:"-"`
 }
@@ -43,7 +46,29 @@ type Field struct {
 	FieldType   string     `json:"field_type,omitempty"` // FieldType of the field
 	IsStruct    bool       `json:"is_struct"`
 	IsSlice     bool       `json:"is_slice"`
+	IsPointer   bool       `json:"is_pointer"` // the key is absent or null in some samples
 	IsBasicType bool       `json:"is_basic"`
 	Tags        []FieldTag `json:"tags,omitempty"`
 	Children    []Field    `json:"children,omitempty"`
+	Rule        *FieldRule `json:"rule,omitempty"` // declared by the companion schema of the config
+}
+
+// FieldRule declares the validation rules and the default value of a config key.
+// Min and Max limit the value of numbers and the length of strings and slices.
+type FieldRule struct {
+	Required bool     `json:"required,omitempty" yaml:"required"`
+	Min      *float64 `json:"min,omitempty" yaml:"min"`
+	Max      *float64 `json:"max,omitempty" yaml:"max"`
+	Enum     []string `json:"enum,omitempty" yaml:"enum"`
+	Default  string   `json:"default,omitempty" yaml:"default"`
+	Desc     string   `json:"desc,omitempty" yaml:"desc"`
 }
+
+// ConfigSchema maps the dotted path of the config keys to their rules, eg:
+//
+//	mysql.dsn:
+//	  required: true
+//	kitex.log_level:
+//	  enum: [debug, info, warn, error]
+//	  default: info
+type ConfigSchema map[string]*FieldRule
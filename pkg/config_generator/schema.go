@@ -0,0 +1,282 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// optional marks the value of a key which is absent or null in some samples
+type optional struct {
+	value interface{}
+}
+
+// rawString marks a string which must not be inferred as time.Duration or time.Time,
+// because the samples of the key don't agree on the type
+type rawString string
+
+const (
+	typeString   = "string"
+	typeFloat    = "float64"
+	typeDuration = "time.Duration"
+	typeTime     = "time.Time"
+)
+
+// mergeValues merges the values of the same key from different samples into one value,
+// which has the shape of all the samples. samples are the indexes of the samples of the values,
+// they are named in the error if the values conflict.
+func mergeValues(key string, values []interface{}, samples []int) (interface{}, error) {
+	present := make([]interface{}, 0, len(values))
+	from := make([]int, 0, len(values))
+	for i, v := range values {
+		if v != nil {
+			present = append(present, v)
+			from = append(from, samples[i])
+		}
+	}
+	if len(present) == 0 {
+		return nil, nil
+	}
+
+	switch present[0].(type) {
+	case map[string]interface{}:
+		return mergeMaps(key, present, from)
+	case []interface{}:
+		return mergeSlices(key, present, from)
+	}
+	merged, err := mergeScalars(key, present, from)
+	if err != nil {
+		return nil, err
+	}
+	return merged[0], nil
+}
+
+func mergeMaps(key string, values []interface{}, samples []int) (interface{}, error) {
+	maps := make([]map[string]interface{}, 0, len(values))
+	for _, v := range values {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, conflictError(key, values, samples)
+		}
+		maps = append(maps, m)
+	}
+
+	result := make(map[string]interface{})
+	for _, m := range maps {
+		for k := range m {
+			if _, ok := result[k]; ok {
+				continue
+			}
+			isOptional := false
+			fieldValues := make([]interface{}, 0, len(maps))
+			for _, m1 := range maps {
+				v, ok := m1[k]
+				if !ok || v == nil {
+					isOptional = true
+				}
+				fieldValues = append(fieldValues, v)
+			}
+			fieldKey := k
+			if key != "" {
+				fieldKey = key + "." + k
+			}
+			merged, err := mergeValues(fieldKey, fieldValues, samples)
+			if err != nil {
+				return nil, err
+			}
+			if isOptional {
+				merged = optional{value: merged}
+			}
+			result[k] = merged
+		}
+	}
+	return result, nil
+}
+
+// mergeSlices unifies the elements of the slices. Objects are merged into one element,
+// scalars are converted to the same type and the elements of the first sample are kept.
+func mergeSlices(key string, values []interface{}, samples []int) (interface{}, error) {
+	var elems []interface{}
+	var from []int
+	firstLen := -1
+	for i, v := range values {
+		s, ok := v.([]interface{})
+		if !ok {
+			return nil, conflictError(key, values, samples)
+		}
+		for _, elem := range s {
+			if elem != nil {
+				elems = append(elems, elem)
+				from = append(from, samples[i])
+			}
+		}
+		if firstLen == -1 && len(elems) > 0 {
+			firstLen = len(elems)
+		}
+	}
+	if len(elems) == 0 {
+		return nil, nil
+	}
+
+	key += "[]"
+	switch elems[0].(type) {
+	case map[string]interface{}:
+		merged, err := mergeMaps(key, elems, from)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{merged}, nil
+	case []interface{}:
+		// nested arrays are not supported
+		return nil, nil
+	}
+	merged, err := mergeScalars(key, elems, from)
+	if err != nil {
+		return nil, err
+	}
+	return merged[:firstLen], nil
+}
+
+// mergeScalars converts the scalars to the same type, int and float64 are unified to float64,
+// strings inferred as different types are unified to rawString.
+func mergeScalars(key string, values []interface{}, samples []int) ([]interface{}, error) {
+	kind := scalarKind(values[0])
+	for _, v := range values[1:] {
+		k := scalarKind(v)
+		switch {
+		case k == kind:
+		case isNumberKind(k) && isNumberKind(kind):
+			kind = typeFloat
+		case isStringKind(k) && isStringKind(kind):
+			kind = typeString
+		default:
+			return nil, conflictError(key, values, samples)
+		}
+	}
+
+	result := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		switch val := v.(type) {
+		case int:
+			if kind == typeFloat {
+				result = append(result, float64(val))
+				continue
+			}
+		case string:
+			if kind == typeString {
+				result = append(result, rawString(val))
+				continue
+			}
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// conflictError names the samples of each kind of the values which can not be merged
+func conflictError(key string, values []interface{}, samples []int) error {
+	var kinds []string
+	indexes := make(map[string][]int)
+	for i, v := range values {
+		kind := valueKind(v)
+		idx, ok := indexes[kind]
+		if !ok {
+			kinds = append(kinds, kind)
+		}
+		// the elements of the slices in the same sample are adjacent
+		if len(idx) == 0 || idx[len(idx)-1] != samples[i] {
+			indexes[kind] = append(idx, samples[i])
+		}
+	}
+	descs := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		descs = append(descs, fmt.Sprintf("%s in the samples %v", kind, indexes[kind]))
+	}
+	return fmt.Errorf("the values of '%s' conflict: %s", key, strings.Join(descs, ", "))
+}
+
+func valueKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return scalarKind(v)
+}
+
+func scalarKind(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return inferStringType(val)
+	case rawString:
+		return typeString
+	}
+	return reflect.TypeOf(v).String()
+}
+
+func isNumberKind(kind string) bool {
+	return kind == "int" || kind == typeFloat
+}
+
+func isStringKind(kind string) bool {
+	return kind == typeString || kind == typeDuration || kind == typeTime
+}
+
+// inferStringType infers time.Duration and time.Time from strings, eg: "1.5s", "2006-01-02T15:04:05Z"
+func inferStringType(s string) string {
+	if strings.IndexFunc(s, func(r rune) bool { return r >= 'a' && r <= 'z' }) != -1 {
+		if _, err := time.ParseDuration(s); err == nil {
+			return typeDuration
+		}
+	}
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return typeTime
+	}
+	return typeString
+}
+
+// typeOf returns the go type of the scalar value, time.Duration is only inferred
+// when the decoder of the config format supports it.
+func (yg *Yaml2Go) typeOf(v interface{}) string {
+	switch val := v.(type) {
+	case rawString:
+		return typeString
+	case string:
+		t := inferStringType(val)
+		if t == typeDuration && (yg.format == formatJSON || yg.format == formatXML) {
+			return typeString
+		}
+		return t
+	}
+	return reflect.TypeOf(v).String()
+}
+
+// formatValue renders the value of the field
+func formatValue(v interface{}) string {
+	switch v.(type) {
+	case string, rawString:
+		return fmt.Sprintf("\"%s\"", v)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
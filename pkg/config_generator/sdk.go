@@ -16,12 +16,16 @@
 
 package config_generator
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Result struct {
 	ServiceName           string              `json:"service_name,omitempty"`
 	Addr                  string              `json:"addr,omitempty"`
 	SubConfigMetadataList []SubConfigMetadata `json:"sub_config_metadata_list,omitempty"`
+	ClientFiles           map[string]string   `json:"client_files,omitempty"` // the typed config client by file name
 }
 
 type SubConfigMetadata struct {
@@ -55,6 +59,15 @@ func HandleRequest(req *Config) (*Result, error) {
 		result.SubConfigMetadataList = append(result.SubConfigMetadataList, subConfigMetadata)
 	}
 
+	// Generate the typed config client into the package when it's requested
+	if req.IsSetClientPackage() {
+		files, err := GenerateConfigClient(result, req.GetClientPackage())
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate config client: %w", err)
+		}
+		result.ClientFiles = files
+	}
+
 	return result, nil
 }
 
@@ -64,12 +77,58 @@ func processSubConfig(subConfig *SubConfig) (SubConfigMetadata, error) {
 		Namespace: subConfig.NameSpace,
 	}
 
-	// Iterate over each key-value pair and process it
+	// Collect the companion schemas, eg: conf.yaml.schema declares the rules of conf.yaml
+	schemas := make(map[string]ConfigSchema)
 	for _, configKvPair := range subConfig.ConfigKvPairList {
-		fileMetas, err := processConfigKvPair(configKvPair)
+		if !strings.HasSuffix(configKvPair.Key, schemaSuffix) {
+			continue
+		}
+		schema, err := ParseConfigSchema([]byte(configKvPair.Value))
+		if err != nil {
+			return metadata, fmt.Errorf("failed to parse schema '%s': %w", configKvPair.Key, err)
+		}
+		schemas[strings.TrimSuffix(configKvPair.Key, schemaSuffix)] = schema
+	}
+
+	// Group the samples of the same config of different kinds, eg: dev, test and online,
+	// they are merged into one struct
+	var groups [][]*ConfigKvPair
+	index := make(map[string]int)
+	for _, configKvPair := range subConfig.ConfigKvPairList {
+		if strings.HasSuffix(configKvPair.Key, schemaSuffix) {
+			continue
+		}
+		id := fmt.Sprintf("%s/%d", configKvPair.Key, configKvPair.ValueType)
+		i, ok := index[id]
+		if !ok {
+			i = len(groups)
+			index[id] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], configKvPair)
+	}
+
+	// Iterate over each group of key-value pairs and process it
+	for _, configKvPairs := range groups {
+		fileMetas, err := processConfigKvPairs(configKvPairs)
 		if err != nil {
 			return metadata, err
 		}
+		if schema, ok := schemas[configKvPairs[0].Key]; ok {
+			for i := range fileMetas {
+				if err = ApplySchema(&fileMetas[i], schema); err != nil {
+					return metadata, err
+				}
+			}
+		}
+		for i := range fileMetas {
+			if len(fileMetas[i].ConfigStruct.StructName) == 0 {
+				continue
+			}
+			if fileMetas[i].JSONSchema, err = GenerateJSONSchema(&fileMetas[i]); err != nil {
+				return metadata, fmt.Errorf("failed to generate json schema for key '%s': %w", fileMetas[i].Key, err)
+			}
+		}
 
 		// Add the processed file metadata to the sub-config metadata
 		metadata.ConfigMetadata = append(metadata.ConfigMetadata, fileMetas...)
@@ -78,22 +137,33 @@ func processSubConfig(subConfig *SubConfig) (SubConfigMetadata, error) {
 	return metadata, nil
 }
 
-// processConfigKvPair processes a single key-value pair and returns the file metadata.
-func processConfigKvPair(configKvPair *ConfigKvPair) ([]ConfigGenerateMeta, error) {
+// processConfigKvPairs processes the samples of the same key and returns the file metadata,
+// the kinds of the samples are joined by comma.
+func processConfigKvPairs(configKvPairs []*ConfigKvPair) ([]ConfigGenerateMeta, error) {
 	var result []ConfigGenerateMeta
 
-	key := configKvPair.Key
-	content := configKvPair.Value
-	desc := configKvPair.Desc
-	group := configKvPair.Kind
-	valueType := configKvPair.ValueType
+	key := configKvPairs[0].Key
+	valueType := configKvPairs[0].ValueType
+	var desc string
+	var groups []string
+	samples := make([][]byte, 0, len(configKvPairs))
+	for _, configKvPair := range configKvPairs {
+		if desc == "" {
+			desc = configKvPair.Desc
+		}
+		if configKvPair.Kind != "" && !containsString(groups, configKvPair.Kind) {
+			groups = append(groups, configKvPair.Kind)
+		}
+		samples = append(samples, []byte(configKvPair.Value))
+	}
+	group := strings.Join(groups, ",")
 
-	// Check if the value type is either JsonType or YamlType
-	switch valueType {
-	case ConfigValueType_YamlType, ConfigValueType_JsonType:
+	// Check if the content can be converted, text is converted only when it is toml or properties
+	switch configFormatOf(key, valueType) {
+	case formatYaml, formatJSON, formatXML, formatTOML, formatProperties:
 		// Convert configuration content into Go structs
 		yaml2Go := New(key, desc, group, valueType)
-		if _, err := yaml2Go.Convert(convertToGoStructName(key), []byte(content)); err != nil {
+		if _, err := yaml2Go.ConvertSamples(convertToGoStructName(key), samples...); err != nil {
 			return nil, fmt.Errorf("failed to convert content for key '%s': %w", key, err)
 		}
 
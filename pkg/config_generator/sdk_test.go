@@ -19,9 +19,18 @@ package config_generator
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 )
 
+var xmlValue = `
+<kitex version="1.0.0">
+  <service>p.s.m</service>
+  <port>8888</port>
+  <port>8889</port>
+</kitex>
+`
+
 func Test_HandleRequest(t *testing.T) {
 	c := &Config{
 		ServiceName: "nacos_config_server",
@@ -40,7 +49,7 @@ func Test_HandleRequest(t *testing.T) {
 						Key:       "conf.yaml",
 						ValueType: ConfigValueType_XmlType,
 						Kind:      "dev",
-						Value:     value,
+						Value:     xmlValue,
 					},
 				},
 			},
@@ -56,3 +65,48 @@ func Test_HandleRequest(t *testing.T) {
 	}
 	os.WriteFile("test.json", out, 0o644)
 }
+
+func TestProcessSubConfigMergeKinds(t *testing.T) {
+	subConfig := &SubConfig{
+		NameSpace: "public",
+		ConfigKvPairList: []*ConfigKvPair{
+			{Key: "conf.yaml", ValueType: ConfigValueType_YamlType, Kind: "dev", Value: "timeout: 1s\nmysql:\n  dsn: dev\n"},
+			{Key: "conf.yaml", ValueType: ConfigValueType_YamlType, Kind: "test", Value: "timeout: 2s\nmysql:\n  dsn: test\n"},
+			{Key: "conf.yaml", ValueType: ConfigValueType_YamlType, Kind: "online", Value: "timeout: 3s\ndebug: true\n"},
+			{Key: "other.json", ValueType: ConfigValueType_JsonType, Kind: "dev", Value: `{"name": "a"}`},
+		},
+	}
+	metadata, err := processSubConfig(subConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata.ConfigMetadata) != 2 {
+		t.Fatalf("want 2 merged configs, got %d", len(metadata.ConfigMetadata))
+	}
+	conf := metadata.ConfigMetadata[0]
+	if conf.Key != "conf.yaml" || conf.Kind != "dev,test,online" {
+		t.Errorf("unexpected merged config %s of kinds %s", conf.Key, conf.Kind)
+	}
+	fields := make(map[string]Field)
+	for _, f := range conf.ConfigStruct.Fields {
+		fields[f.FieldName] = f
+	}
+	for name, pointer := range map[string]bool{"Timeout": false, "Mysql": true, "Debug": true} {
+		f, ok := fields[name]
+		if !ok {
+			t.Errorf("field %s of the samples is missing", name)
+			continue
+		}
+		if f.IsPointer != pointer {
+			t.Errorf("field %s: want pointer %v, got %v", name, pointer, f.IsPointer)
+		}
+	}
+
+	files, err := GenerateConfigClient(&Result{SubConfigMetadataList: []SubConfigMetadata{metadata}}, "conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if holder := files["publicconfyaml.go"]; !strings.Contains(holder, "Debug") || !strings.Contains(holder, "Mysql") {
+		t.Errorf("config client does not contain the keys of all the kinds:\n%s", holder)
+	}
+}
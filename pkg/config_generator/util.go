@@ -66,3 +66,13 @@ func isBasicType(typeName string) bool {
 
 	return basicTypes[typeName]
 }
+
+// containsString checks if the slice contains the given string
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,454 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hu-1996/cwgo/meta"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaSuffix is the key suffix of the companion schema of a config, eg: conf.yaml.schema
+const schemaSuffix = ".schema"
+
+// ParseConfigSchema parses the companion schema of a config, which is written in yaml or json
+func ParseConfigSchema(data []byte) (ConfigSchema, error) {
+	schema := make(ConfigSchema)
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// ApplySchema attaches the rules of the schema to the fields of the config struct
+func ApplySchema(configMeta *ConfigGenerateMeta, schema ConfigSchema) error {
+	for path, rule := range schema {
+		field := findFieldByPath(configMeta.ConfigStruct.Fields, strings.Split(path, "."))
+		if field == nil {
+			return fmt.Errorf("schema key '%s' is not found in config '%s'", path, configMeta.Key)
+		}
+		field.Rule = rule
+	}
+	return nil
+}
+
+func findFieldByPath(fields []Field, path []string) *Field {
+	for i := range fields {
+		if fieldKey(fields[i]) != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return &fields[i]
+		}
+		return findFieldByPath(fields[i].Children, path[1:])
+	}
+	return nil
+}
+
+// fieldKey returns the config key of the field, eg: `yaml:"name,omitempty"` -> name
+func fieldKey(f Field) string {
+	if len(f.Tags) == 0 {
+		return f.FieldName
+	}
+	return strings.Split(f.Tags[0].TagValue, ",")[0]
+}
+
+// elemType returns the type of the field without slice and pointer, eg: []*Backend -> Backend
+func elemType(f Field) string {
+	return strings.TrimPrefix(strings.TrimPrefix(f.FieldType, "[]"), "*")
+}
+
+func isSliceField(f Field) bool {
+	return strings.HasPrefix(f.FieldType, "[]")
+}
+
+func isPointerField(f Field) bool {
+	return strings.HasPrefix(f.FieldType, "*")
+}
+
+func joinConditions(conditions ...string) string {
+	var result []string
+	for _, c := range conditions {
+		if len(c) > 0 {
+			result = append(result, c)
+		}
+	}
+	return strings.Join(result, " && ")
+}
+
+func isNumberType(t string) bool {
+	return strings.HasPrefix(t, "int") || strings.HasPrefix(t, "uint") || strings.HasPrefix(t, "float")
+}
+
+type validatorGenerator struct {
+	seen    map[string]bool
+	body    strings.Builder
+	useTime bool
+}
+
+// GenerateValidator generates the Validate and ApplyDefaults methods of the config struct
+// and its nested structs according to the rules of the fields.
+func GenerateValidator(configMeta *ConfigGenerateMeta, pkgName string) (string, error) {
+	g := &validatorGenerator{seen: make(map[string]bool)}
+	if err := g.genStruct(configMeta.ConfigStruct.StructName, configMeta.ConfigStruct.Fields); err != nil {
+		return "", fmt.Errorf("generate validator for '%s' failed: %w", configMeta.Key, err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// Code generated by cwgo (%s). DO NOT EDIT.\n\n", meta.Version))
+	sb.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
+	body := g.body.String()
+	var imports []string
+	if strings.Contains(body, "fmt.Errorf") {
+		imports = append(imports, "\t\"fmt\"\n")
+	}
+	if g.useTime {
+		imports = append(imports, "\t\"time\"\n")
+	}
+	if len(imports) > 0 {
+		sb.WriteString(fmt.Sprintf("import (\n%s)\n\n", strings.Join(imports, "")))
+	}
+	sb.WriteString(body)
+
+	goFormat, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(goFormat), nil
+}
+
+func (g *validatorGenerator) genStruct(name string, fields []Field) error {
+	if g.seen[name] {
+		return nil
+	}
+	g.seen[name] = true
+
+	var validate, defaults strings.Builder
+	for _, f := range fields {
+		if err := g.genValidate(&validate, f); err != nil {
+			return fmt.Errorf("field '%s': %w", fieldKey(f), err)
+		}
+		if err := g.genDefault(&defaults, f); err != nil {
+			return fmt.Errorf("field '%s': %w", fieldKey(f), err)
+		}
+	}
+
+	g.body.WriteString(fmt.Sprintf("// Validate checks the rules of %s\n", name))
+	g.body.WriteString(fmt.Sprintf("func (c *%s) Validate() error {\n%s\treturn nil\n}\n\n", name, validate.String()))
+	g.body.WriteString(fmt.Sprintf("// ApplyDefaults sets the default values of the empty fields of %s\n", name))
+	g.body.WriteString(fmt.Sprintf("func (c *%s) ApplyDefaults() {\n%s}\n\n", name, defaults.String()))
+
+	for _, f := range fields {
+		if f.IsStruct {
+			if err := g.genStruct(elemType(f), f.Children); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *validatorGenerator) genValidate(sb *strings.Builder, f Field) error {
+	key := fieldKey(f)
+	expr := "c." + f.FieldName
+	typ := elemType(f)
+	rule := f.Rule
+	if rule == nil {
+		rule = &FieldRule{}
+	}
+
+	if rule.Required {
+		switch {
+		case isSliceField(f):
+			sb.WriteString(fmt.Sprintf("\tif len(%s) == 0 {\n", expr))
+		case isPointerField(f) || typ == "interface{}":
+			sb.WriteString(fmt.Sprintf("\tif %s == nil {\n", expr))
+		case typ == "string":
+			sb.WriteString(fmt.Sprintf("\tif %s == \"\" {\n", expr))
+		case isNumberType(typ) || typ == typeDuration:
+			sb.WriteString(fmt.Sprintf("\tif %s == 0 {\n", expr))
+		case typ == typeTime:
+			sb.WriteString(fmt.Sprintf("\tif %s.IsZero() {\n", expr))
+		default:
+			return fmt.Errorf("required is not supported for type %s", f.FieldType)
+		}
+		sb.WriteString(fmt.Sprintf("\t\treturn fmt.Errorf(\"%s is required\")\n\t}\n", key))
+	}
+
+	// dereference the optional scalar
+	value := expr
+	var guard string
+	if isPointerField(f) && !f.IsStruct {
+		value = "*" + expr
+		guard = expr + " != nil"
+	}
+
+	if rule.Min != nil || rule.Max != nil {
+		measure := value
+		switch {
+		case isSliceField(f) || typ == "string":
+			measure = fmt.Sprintf("len(%s)", value)
+		case isNumberType(typ):
+		default:
+			return fmt.Errorf("min and max are not supported for type %s", f.FieldType)
+		}
+		for _, bound := range []struct {
+			limit *float64
+			op    string
+			desc  string
+		}{{rule.Min, "<", "less than"}, {rule.Max, ">", "greater than"}} {
+			if bound.limit == nil {
+				continue
+			}
+			limit := strconv.FormatFloat(*bound.limit, 'f', -1, 64)
+			if (isSliceField(f) || !strings.HasPrefix(typ, "float")) && *bound.limit != float64(int64(*bound.limit)) {
+				return fmt.Errorf("limit %s of integer or length must be integral", limit)
+			}
+			sb.WriteString(fmt.Sprintf("\tif %s {\n", joinConditions(guard, measure+" "+bound.op+" "+limit)))
+			sb.WriteString(fmt.Sprintf("\t\treturn fmt.Errorf(\"%s must not be %s %s\")\n\t}\n", key, bound.desc, limit))
+		}
+	}
+
+	if len(rule.Enum) > 0 {
+		if isSliceField(f) || f.IsStruct {
+			return fmt.Errorf("enum is not supported for type %s", f.FieldType)
+		}
+		literals := make([]string, 0, len(rule.Enum))
+		for _, e := range rule.Enum {
+			lit, err := g.literal(typ, e)
+			if err != nil {
+				return err
+			}
+			literals = append(literals, lit)
+		}
+		if guard != "" {
+			sb.WriteString(fmt.Sprintf("\tif %s {\n", guard))
+		}
+		sb.WriteString(fmt.Sprintf("\tswitch %s {\n\tcase %s:\n\tdefault:\n", value, strings.Join(literals, ", ")))
+		sb.WriteString(fmt.Sprintf("\t\treturn fmt.Errorf(\"%s must be one of %s, got %%v\", %s)\n\t}\n", key, strings.Join(rule.Enum, ", "), value))
+		if guard != "" {
+			sb.WriteString("\t}\n")
+		}
+	}
+
+	if f.IsStruct {
+		switch {
+		case isSliceField(f):
+			sb.WriteString(fmt.Sprintf("\tfor i := range %s {\n", expr))
+			sb.WriteString(fmt.Sprintf("\t\tif err := %s[i].Validate(); err != nil {\n", expr))
+			sb.WriteString(fmt.Sprintf("\t\t\treturn fmt.Errorf(\"%s[%%d]: %%w\", i, err)\n\t\t}\n\t}\n", key))
+		case isPointerField(f):
+			sb.WriteString(fmt.Sprintf("\tif %s != nil {\n", expr))
+			sb.WriteString(fmt.Sprintf("\t\tif err := %s.Validate(); err != nil {\n", expr))
+			sb.WriteString(fmt.Sprintf("\t\t\treturn fmt.Errorf(\"%s: %%w\", err)\n\t\t}\n\t}\n", key))
+		default:
+			sb.WriteString(fmt.Sprintf("\tif err := %s.Validate(); err != nil {\n", expr))
+			sb.WriteString(fmt.Sprintf("\t\treturn fmt.Errorf(\"%s: %%w\", err)\n\t}\n", key))
+		}
+	}
+	return nil
+}
+
+func (g *validatorGenerator) genDefault(sb *strings.Builder, f Field) error {
+	expr := "c." + f.FieldName
+	typ := elemType(f)
+
+	if f.IsStruct {
+		switch {
+		case isSliceField(f):
+			sb.WriteString(fmt.Sprintf("\tfor i := range %s {\n\t\t%s[i].ApplyDefaults()\n\t}\n", expr, expr))
+		case isPointerField(f):
+			sb.WriteString(fmt.Sprintf("\tif %s != nil {\n\t\t%s.ApplyDefaults()\n\t}\n", expr, expr))
+		default:
+			sb.WriteString(fmt.Sprintf("\t%s.ApplyDefaults()\n", expr))
+		}
+		return nil
+	}
+
+	if f.Rule == nil || f.Rule.Default == "" {
+		return nil
+	}
+	if isSliceField(f) {
+		return fmt.Errorf("default is not supported for type %s", f.FieldType)
+	}
+	lit, err := g.literal(typ, f.Rule.Default)
+	if err != nil {
+		return err
+	}
+	switch {
+	case isPointerField(f):
+		sb.WriteString(fmt.Sprintf("\tif %s == nil {\n\t\tv := %s(%s)\n\t\t%s = &v\n\t}\n", expr, typ, lit, expr))
+	case typ == "string":
+		sb.WriteString(fmt.Sprintf("\tif %s == \"\" {\n\t\t%s = %s\n\t}\n", expr, expr, lit))
+	case isNumberType(typ) || typ == typeDuration:
+		sb.WriteString(fmt.Sprintf("\tif %s == 0 {\n\t\t%s = %s\n\t}\n", expr, expr, lit))
+	default:
+		// the zero value of bool can't be distinguished from an absent key
+		return fmt.Errorf("default is only supported for optional field of type %s", f.FieldType)
+	}
+	return nil
+}
+
+// literal renders the value declared in the schema as go literal of the type
+func (g *validatorGenerator) literal(typ, value string) (string, error) {
+	switch {
+	case typ == "string":
+		return strconv.Quote(value), nil
+	case typ == "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "", fmt.Errorf("invalid bool value '%s'", value)
+		}
+		return value, nil
+	case typ == typeDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid duration value '%s'", value)
+		}
+		g.useTime = true
+		return fmt.Sprintf("time.Duration(%d)", int64(d)), nil
+	case strings.HasPrefix(typ, "float"):
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("invalid number value '%s'", value)
+		}
+		return value, nil
+	case isNumberType(typ):
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid integer value '%s'", value)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("value is not supported for type %s", typ)
+}
+
+// GenerateJSONSchema generates the json schema (draft-07) of the config struct
+func GenerateJSONSchema(configMeta *ConfigGenerateMeta) ([]byte, error) {
+	schema := structSchema(configMeta.ConfigStruct.Fields)
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = configMeta.ConfigStruct.StructName
+	if len(configMeta.Desc) > 0 {
+		schema["description"] = configMeta.Desc
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func structSchema(fields []Field) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	var required []string
+	for _, f := range fields {
+		key := fieldKey(f)
+		if len(key) == 0 {
+			continue
+		}
+		properties[key] = fieldSchema(f)
+		if f.Rule != nil && f.Rule.Required {
+			required = append(required, key)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func fieldSchema(f Field) map[string]interface{} {
+	typ := elemType(f)
+	var schema map[string]interface{}
+	switch {
+	case f.IsStruct:
+		schema = structSchema(f.Children)
+	case typ == "string" || typ == typeDuration:
+		schema = map[string]interface{}{"type": "string"}
+	case typ == typeTime:
+		schema = map[string]interface{}{"type": "string", "format": "date-time"}
+	case typ == "bool":
+		schema = map[string]interface{}{"type": "boolean"}
+	case strings.HasPrefix(typ, "float"):
+		schema = map[string]interface{}{"type": "number"}
+	case isNumberType(typ):
+		schema = map[string]interface{}{"type": "integer"}
+	default:
+		schema = map[string]interface{}{}
+	}
+
+	rule := f.Rule
+	if rule != nil && !isSliceField(f) {
+		addRuleSchema(schema, typ, rule)
+	}
+	if isSliceField(f) {
+		schema = map[string]interface{}{"type": "array", "items": schema}
+		if rule != nil {
+			if rule.Min != nil {
+				schema["minItems"] = int64(*rule.Min)
+			}
+			if rule.Max != nil {
+				schema["maxItems"] = int64(*rule.Max)
+			}
+		}
+	}
+	if rule != nil && len(rule.Desc) > 0 {
+		schema["description"] = rule.Desc
+	}
+	return schema
+}
+
+func addRuleSchema(schema map[string]interface{}, typ string, rule *FieldRule) {
+	minKey, maxKey := "minimum", "maximum"
+	if typ == "string" {
+		minKey, maxKey = "minLength", "maxLength"
+	}
+	if rule.Min != nil {
+		schema[minKey] = *rule.Min
+	}
+	if rule.Max != nil {
+		schema[maxKey] = *rule.Max
+	}
+	if len(rule.Enum) > 0 {
+		enum := make([]interface{}, 0, len(rule.Enum))
+		for _, e := range rule.Enum {
+			enum = append(enum, schemaValue(typ, e))
+		}
+		schema["enum"] = enum
+	}
+	if len(rule.Default) > 0 {
+		schema["default"] = schemaValue(typ, rule.Default)
+	}
+}
+
+// schemaValue converts the value declared in the schema to the json value of the type
+func schemaValue(typ, value string) interface{} {
+	switch {
+	case typ == "bool":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case isNumberType(typ):
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_generator
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+var confSchemaValue = `
+kitex.service:
+  required: true
+kitex.version:
+  enum: ["1.0.0", "2.0.0"]
+kitex.ports:
+  min: 1
+  max: 3
+kitex.timeout:
+  default: 1s
+  desc: rpc timeout
+`
+
+var schemaConfigValue = `
+kitex:
+  service: p.s.m
+  version: 1.0.0
+  timeout: 3s
+  ports:
+    - 8888
+`
+
+func TestGenerateValidator(t *testing.T) {
+	result, err := HandleRequest(&Config{
+		ServiceName: "nacos_config_server",
+		SubConfigList: []*SubConfig{
+			{
+				NameSpace: "public",
+				ConfigKvPairList: []*ConfigKvPair{
+					{Key: "conf.yaml", ValueType: ConfigValueType_YamlType, Value: schemaConfigValue},
+					{Key: "conf.yaml.schema", ValueType: ConfigValueType_YamlType, Value: confSchemaValue},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metas := result.SubConfigMetadataList[0].ConfigMetadata
+	if len(metas) != 1 {
+		t.Fatalf("schema should not be generated as config, got %d configs", len(metas))
+	}
+	configMeta := &metas[0]
+
+	code, err := GenerateValidator(configMeta, "conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`return fmt.Errorf("service is required")`,
+		`case "1.0.0", "2.0.0":`,
+		`if len(c.Ports) > 3 {`,
+		`c.Timeout = time.Duration(1000000000)`,
+		`if err := c.Kitex.Validate(); err != nil {`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated validator does not contain %s:\n%s", want, code)
+		}
+	}
+
+	// the validator must compile with the generated structs
+	yaml2Go := New("conf.yaml", "", "", ConfigValueType_YamlType)
+	structs, err := yaml2Go.Convert("conf.yaml", []byte(schemaConfigValue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{"conf.go": "package conf\n\nimport \"time\"\n\nvar _ time.Duration\n\n" + structs, "validator.go": code} {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err = conf.Check("conf", fset, files, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the json schema is generated alongside the structs
+	var obj map[string]interface{}
+	if err = json.Unmarshal(configMeta.JSONSchema, &obj); err != nil {
+		t.Fatal(err)
+	}
+	kitex := obj["properties"].(map[string]interface{})["kitex"].(map[string]interface{})
+	if required := kitex["required"].([]interface{}); len(required) != 1 || required[0] != "service" {
+		t.Errorf("unexpected required keys: %v", required)
+	}
+	ports := kitex["properties"].(map[string]interface{})["ports"].(map[string]interface{})
+	if ports["type"] != "array" || ports["maxItems"] != float64(3) {
+		t.Errorf("unexpected ports schema: %v", ports)
+	}
+}
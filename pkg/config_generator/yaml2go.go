@@ -21,8 +21,6 @@ import (
 	"go/format"
 	"reflect"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 // New creates Yaml2Go object
@@ -35,6 +33,7 @@ func New(key, desc, kind string, configValueType ConfigValueType) Yaml2Go {
 			ConfigValueType: configValueType,
 			structTree:      make(map[string]Struct),
 		},
+		format: configFormatOf(key, configValueType),
 	}
 }
 
@@ -48,6 +47,8 @@ type Yaml2Go struct {
 	visited     map[line]bool
 	structMap   map[string]string
 	StructsMeta *ConfigGenerateMeta
+
+	format configFormat
 }
 
 // NewStruct creates new entry in structMap result
@@ -90,7 +91,7 @@ func (yg *Yaml2Go) AppendResult(structName, l string, s ...Struct) {
 func goKeyFormat(key string) string {
 	var st string
 	strList := strings.FieldsFunc(key, func(r rune) bool {
-		return r == ' ' || r == '_' || r == '-'
+		return r == ' ' || r == '_' || r == '-' || r == '.' || r == ':'
 	})
 	for _, str := range strList {
 		st += strings.Title(str)
@@ -101,18 +102,93 @@ func goKeyFormat(key string) string {
 	return st
 }
 
+// fieldName returns the go field name of the given key
+func (yg *Yaml2Go) fieldName(k string) string {
+	switch {
+	case yg.format == formatXML && k == xmlTextKey:
+		return "Text"
+	case yg.format == formatXML && strings.HasPrefix(k, xmlAttrPrefix):
+		return goKeyFormat(strings.TrimPrefix(k, xmlAttrPrefix))
+	}
+	return goKeyFormat(k)
+}
+
+// fieldTags returns the struct tags of the given key, the first one is the
+// tag used by the decoder of the config format
+func (yg *Yaml2Go) fieldTags(k string) []FieldTag {
+	switch yg.format {
+	case formatXML:
+		switch {
+		case k == xmlTextKey:
+			return []FieldTag{{TagKey: "xml", TagValue: ",chardata"}}
+		case strings.HasPrefix(k, xmlAttrPrefix):
+			name := strings.TrimPrefix(k, xmlAttrPrefix)
+			return []FieldTag{{TagKey: "xml", TagValue: name + ",attr"}, {TagKey: "json", TagValue: name}}
+		}
+		return []FieldTag{{TagKey: "xml", TagValue: k}, {TagKey: "json", TagValue: k}}
+	case formatTOML:
+		return []FieldTag{{TagKey: "toml", TagValue: k}, {TagKey: "json", TagValue: k}}
+	case formatProperties:
+		return []FieldTag{{TagKey: "properties", TagValue: k}, {TagKey: "json", TagValue: k}}
+	default:
+		return []FieldTag{{TagKey: "yaml", TagValue: k}, {TagKey: "json", TagValue: k}}
+	}
+}
+
+// tagString renders the decoder tag, eg: `yaml:"name"`
+func tagString(tags []FieldTag) string {
+	return fmt.Sprintf("`%s:\"%s\"`", tags[0].TagKey, tags[0].TagValue)
+}
+
+// optionalTags appends omitempty to the tags of an optional field
+func optionalTags(tags []FieldTag) []FieldTag {
+	result := make([]FieldTag, 0, len(tags))
+	for _, tag := range tags {
+		// properties decoder doesn't support omitempty, and char data can't be omitted
+		if tag.TagKey != "properties" && !strings.HasPrefix(tag.TagValue, ",") {
+			tag.TagValue += ",omitempty"
+		}
+		result = append(result, tag)
+	}
+	return result
+}
+
 // Convert transforms map[string]interface{} to go struct
 func (yg *Yaml2Go) Convert(structName string, data []byte) (string, error) {
+	return yg.ConvertSamples(structName, data)
+}
+
+// ConvertSamples transforms several samples of the same config, eg: the confs of dev, test and
+// online, to one go struct. Keys absent or null in some samples are generated as optional fields.
+func (yg *Yaml2Go) ConvertSamples(structName string, samples ...[]byte) (string, error) {
+	if len(samples) == 0 {
+		return "", fmt.Errorf("no config sample to convert")
+	}
 	structName = convertToGoStructName(structName)
 	yg.visited = make(map[line]bool)
 	yg.structMap = make(map[string]string)
 
 	// Unmarshal to map[string]interface{}
-	var obj map[string]interface{}
-	err := yaml.Unmarshal(data, &obj)
+	objs := make([]interface{}, 0, len(samples))
+	for i, data := range samples {
+		obj, err := yg.format.decode(data)
+		if err != nil {
+			return "", fmt.Errorf("decode sample %d failed: %w", i, err)
+		}
+		objs = append(objs, obj)
+	}
+	indexes := make([]int, len(objs))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	merged, err := mergeValues("", objs, indexes)
 	if err != nil {
 		return "", err
 	}
+	obj := make(map[string]interface{})
+	if merged != nil {
+		obj = merged.(map[string]interface{})
+	}
 
 	yg.NewStruct(structName, "")
 	for k, v := range obj {
@@ -136,26 +212,25 @@ func (yg *Yaml2Go) Convert(structName string, data []byte) (string, error) {
 // structName : parent struct name
 // k, v       : fields in the struct
 func (yg *Yaml2Go) Structify(structName, k string, v interface{}, arrayElem bool) {
+	tags := yg.fieldTags(k)
+	isOptional := false
+	if o, ok := v.(optional); ok {
+		v = o.value
+		isOptional = true
+		tags = optionalTags(tags)
+	}
+
 	if reflect.TypeOf(v) == nil || len(k) == 0 {
 		field := Struct{
 			Fields: []Field{
 				{
-					FieldName: goKeyFormat(k),
+					FieldName: yg.fieldName(k),
 					FieldType: "interface{}",
-					Tags: []FieldTag{
-						{
-							TagKey:   "yaml",
-							TagValue: k,
-						},
-						{
-							TagKey:   "json",
-							TagValue: k,
-						},
-					},
+					Tags:      tags,
 				},
 			},
 		}
-		yg.AppendResult(structName, fmt.Sprintf("%s interface{} `yaml:\"%s\"`\n", goKeyFormat(k), k), field)
+		yg.AppendResult(structName, fmt.Sprintf("%s interface{} %s\n", yg.fieldName(k), tagString(tags)), field)
 		return
 	}
 
@@ -164,32 +239,28 @@ func (yg *Yaml2Go) Structify(structName, k string, v interface{}, arrayElem bool
 	case reflect.Map:
 		switch val := v.(type) {
 		case map[string]interface{}:
-			key := goKeyFormat(k)
-			newKey := key
+			key := yg.fieldName(k)
+			newKey := structName
 			if !arrayElem {
 				// Create new structure
 				newKey = yg.NewStruct(key, structName)
+				fieldType := newKey
+				if isOptional {
+					fieldType = "*" + newKey
+				}
 				field := Struct{
 					StructName: newKey,
 					Fields: []Field{
 						{
 							FieldName: key,
-							FieldType: newKey,
+							FieldType: fieldType,
 							IsStruct:  true,
-							Tags: []FieldTag{
-								{
-									TagKey:   "yaml",
-									TagValue: k,
-								},
-								{
-									TagKey:   "json",
-									TagValue: k,
-								},
-							},
+							IsPointer: isOptional,
+							Tags:      tags,
 						},
 					},
 				}
-				yg.AppendResult(structName, fmt.Sprintf("%s %s `yaml:\"%s\"`\n", key, newKey, k), field)
+				yg.AppendResult(structName, fmt.Sprintf("%s %s %s\n", key, fieldType, tagString(tags)), field)
 			}
 			// If array of yaml objects
 			for k1, v1 := range val {
@@ -206,51 +277,11 @@ func (yg *Yaml2Go) Structify(structName, k string, v interface{}, arrayElem bool
 		if len(val) == 0 {
 			return
 		}
-		keyFormat := goKeyFormat(k)
+		keyFormat := yg.fieldName(k)
 		switch val[0].(type) {
-		case string, int, bool, float64:
-			structOr := Struct{
-				StructName: keyFormat,
-				Fields: []Field{
-					{
-						FieldName: goKeyFormat(k),
-						FieldType: fmt.Sprintf("[]%s", reflect.TypeOf(val[0])),
-						IsStruct:  false,
-						IsSlice:   true,
-						Tags: []FieldTag{
-							{
-								TagKey:   "yaml",
-								TagValue: k,
-							},
-							{
-								TagKey:   "json",
-								TagValue: k,
-							},
-						},
-					},
-				},
-			}
-			for _, v := range val {
-				var v1 string
-				switch v.(type) {
-				case string:
-					v1 = fmt.Sprintf("\"%s\"", v)
-				case bool:
-					v1 = fmt.Sprintf("%t", v)
-				default:
-					v1 = fmt.Sprintf("%v", v)
-				}
-				structOr.Fields[0].Children = append(structOr.Fields[0].Children, Field{
-					Value:       v1,
-					FieldType:   reflect.TypeOf(v).String(),
-					IsBasicType: true,
-				})
-			}
-			yg.AppendResult(structName, fmt.Sprintf("%s []%s `yaml:\"%s\"`\n", goKeyFormat(k), reflect.TypeOf(val[0]), k), structOr)
-
 		// if nested object
 		case map[string]interface{}:
-			key := goKeyFormat(k)
+			key := yg.fieldName(k)
 			// Create new structure
 			newKey := yg.NewStruct(key, structName)
 			field := Struct{
@@ -260,55 +291,56 @@ func (yg *Yaml2Go) Structify(structName, k string, v interface{}, arrayElem bool
 						FieldName: key,
 						FieldType: fmt.Sprintf("[]%s", newKey),
 						IsSlice:   true,
-						Tags: []FieldTag{
-							{
-								TagKey:   "yaml",
-								TagValue: k,
-							},
-							{
-								TagKey:   "json",
-								TagValue: k,
-							},
-						},
+						Tags:      tags,
 					},
 				},
 			}
-			yg.AppendResult(structName, fmt.Sprintf("%s []%s `yaml:\"%s\"`\n", key, newKey, k), field)
-			for _, v1 := range val {
-				yg.Structify(newKey, key, v1, true)
-			}
+			yg.AppendResult(structName, fmt.Sprintf("%s []%s %s\n", key, newKey, tagString(tags)), field)
+			// elements of the array have been merged into one
+			yg.Structify(newKey, key, val[0], true)
 			yg.AppendResult(newKey, "}\n")
+		default:
+			elemType := yg.typeOf(val[0])
+			structOr := Struct{
+				StructName: keyFormat,
+				Fields: []Field{
+					{
+						FieldName: yg.fieldName(k),
+						FieldType: fmt.Sprintf("[]%s", elemType),
+						IsStruct:  false,
+						IsSlice:   true,
+						Tags:      tags,
+					},
+				},
+			}
+			for _, v := range val {
+				structOr.Fields[0].Children = append(structOr.Fields[0].Children, Field{
+					Value:       formatValue(v),
+					FieldType:   elemType,
+					IsBasicType: isBasicType(elemType),
+				})
+			}
+			yg.AppendResult(structName, fmt.Sprintf("%s []%s %s\n", yg.fieldName(k), elemType, tagString(tags)), structOr)
 		}
 
 	default:
+		fieldType := yg.typeOf(v)
+		if isOptional {
+			fieldType = "*" + fieldType
+		}
 		field := Struct{
 			Fields: []Field{
 				{
-					FieldName:   goKeyFormat(k),
-					FieldType:   reflect.TypeOf(v).String(),
-					IsBasicType: isBasicType(reflect.TypeOf(v).String()),
-					Tags: []FieldTag{
-						{
-							TagKey:   "yaml",
-							TagValue: k,
-						},
-						{
-							TagKey:   "json",
-							TagValue: k,
-						},
-					},
+					FieldName:   yg.fieldName(k),
+					FieldType:   fieldType,
+					IsBasicType: isBasicType(yg.typeOf(v)),
+					IsPointer:   isOptional,
+					Value:       formatValue(v),
+					Tags:        tags,
 				},
 			},
 		}
-		switch v.(type) {
-		case string:
-			field.Fields[0].Value = fmt.Sprintf("\"%s\"", v)
-		case bool:
-			field.Fields[0].Value = fmt.Sprintf("%t", v)
-		default:
-			field.Fields[0].Value = fmt.Sprintf("%v", v)
-		}
-		yg.AppendResult(structName, fmt.Sprintf("%s %s `yaml:\"%s\"`\n", goKeyFormat(k), reflect.TypeOf(v).String(), k), field)
+		yg.AppendResult(structName, fmt.Sprintf("%s %s %s\n", yg.fieldName(k), fieldType, tagString(tags)), field)
 	}
 }
 
@@ -351,6 +383,7 @@ func organizeStructHelper(currentStruct Struct, structs map[string]Struct, visit
 			fieldType = strings.TrimPrefix(fieldType, "[]")
 			isSlice = true
 		}
+		fieldType = strings.TrimPrefix(fieldType, "*")
 
 		// Determine if the field type is a basic type or a struct
 		currentStruct.Fields[i].IsBasicType = isBasicType(fieldType)
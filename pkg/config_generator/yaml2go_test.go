@@ -19,6 +19,7 @@ package config_generator
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -46,3 +47,61 @@ func TestYaml2Go(t *testing.T) {
 	}
 	os.WriteFile("metadata.json", marshal, 0o644)
 }
+
+func TestYaml2GoSamples(t *testing.T) {
+	dev := `
+timeout: 1s
+started: 2024-01-02T15:04:05Z
+ratio: 1
+mysql:
+  dsn: dev
+backends:
+  - addr: 127.0.0.1
+  - addr: 127.0.0.2
+    weight: 10
+`
+	online := `
+timeout: 500ms
+started: 2024-01-02T15:04:05Z
+ratio: 0.5
+debug: ~
+backends:
+  - addr: 10.0.0.1
+`
+	yaml2Go := New("key", "desc", "group", ConfigValueType_YamlType)
+	s, err := yaml2Go.ConvertSamples("config", []byte(dev), []byte(online))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Timeout time.Duration `yaml:\"timeout\"`",
+		"Started time.Time `yaml:\"started\"`",
+		"Ratio float64 `yaml:\"ratio\"`",
+		"Mysql *Mysql `yaml:\"mysql,omitempty\"`",
+		"Debug interface{} `yaml:\"debug,omitempty\"`",
+		"Weight *int `yaml:\"weight,omitempty\"`",
+	} {
+		if !strings.Contains(strings.Join(strings.Fields(s), " "), want) {
+			t.Errorf("generated struct does not contain %s:\n%s", want, s)
+		}
+	}
+
+	yaml2Go = New("key", "desc", "group", ConfigValueType_YamlType)
+	_, err = yaml2Go.ConvertSamples("config", []byte(dev), []byte("mysql: dev"))
+	if err == nil || !strings.Contains(err.Error(), "'mysql' conflict: object in the samples [0], string in the samples [1]") {
+		t.Errorf("want the error of the conflicting samples, got: %v", err)
+	}
+	_, err = yaml2Go.ConvertSamples("config", []byte(dev), []byte(online), []byte("backends: [1]"))
+	if err == nil || !strings.Contains(err.Error(), "'backends[]' conflict: object in the samples [0 1], int in the samples [2]") {
+		t.Errorf("want the error of the conflicting elements, got: %v", err)
+	}
+
+	yaml2Go = New("key", "desc", "group", ConfigValueType_JsonType)
+	s, err = yaml2Go.Convert("config", []byte(`{"timeout": "1s"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(s, "Timeout string") {
+		t.Errorf("duration should not be inferred for json:\n%s", s)
+	}
+}
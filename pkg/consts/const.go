@@ -70,6 +70,9 @@ const (
 	DefaultHZClientDir    = "biz/http"
 	DefaultKitexModelDir  = "kitex_gen"
 	DefaultDbOutDir       = "biz/dal/query"
+	DefaultDbTplOutDir    = "biz/dal"
+	DefaultIDLOutDir      = "idl"
+	DefaultMigrationDir   = "migrations"
 	DefaultDocModelOutDir = "biz/doc/model"
 	DefaultDocDaoOutDir   = "biz/doc/dao"
 	Standard              = "standard"
@@ -117,6 +120,7 @@ const (
 
 const (
 	Go     = "go"
+	TS     = "ts"
 	GOPATH = "GOPATH"
 	Env    = "env"
 	Mod    = "mod"
@@ -139,26 +143,35 @@ const (
 	Registry        = "registry"
 	Pass            = "pass"
 	ProtoSearchPath = "proto_search_path"
+	Lang            = "lang"
 	ThriftGo        = "thriftgo"
 	Protoc          = "protoc"
 	GenBase         = "gen_base"
 
-	ProjectPath   = "project_path"
-	HertzRepoUrl  = "hertz_repo_url"
-	DSN           = "dsn"
-	DBType        = "db_type"
-	Tables        = "tables"
-	ExcludeTables = "exclude_tables"
-	OnlyModel     = "only_model"
-	OutFile       = "out_file"
-	UnitTest      = "unittest"
-	ModelPkgName  = "model_pkg"
-	Nullable      = "nullable"
-	Signable      = "signable"
-	IndexTag      = "index_tag"
-	TypeTag       = "type_tag"
-	HexTag        = "hex"
-	SQLDir        = "sql_dir"
+	ProjectPath    = "project_path"
+	HertzRepoUrl   = "hertz_repo_url"
+	DSN            = "dsn"
+	DBType         = "db_type"
+	Tables         = "tables"
+	ExcludeTables  = "exclude_tables"
+	OnlyModel      = "only_model"
+	OutFile        = "out_file"
+	UnitTest       = "unittest"
+	ModelPkgName   = "model_pkg"
+	Nullable       = "nullable"
+	Signable       = "signable"
+	IndexTag       = "index_tag"
+	TypeTag        = "type_tag"
+	HexTag         = "hex"
+	SQLDir         = "sql_dir"
+	TemplateOutDir = "template_out_dir"
+	ToIDL          = "to_idl"
+	IDLOutFile     = "idl_out"
+	ModelConfig    = "model_config"
+	QueryDir       = "query_dir"
+	Against        = "against"
+	Rule           = "rule"
+	SkipRule       = "skip_rule"
 )
 
 const (
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/hertz/generator"
+)
+
+// Breaking reports the wire, json and route breaking changes of the idl against its previous version,
+// an error is returned if there is any breaking change, so that it can be used in the CI
+func Breaking(c *config.BreakingArgument) error {
+	current, err := loadIdl(c.IdlPath, c.ProtoSearchPath)
+	if err != nil {
+		return err
+	}
+	previous, err := loadIdl(c.Against, c.ProtoSearchPath)
+	if err != nil {
+		return err
+	}
+	return report(current.Path, breakingChanges(previous, current), "breaking changes")
+}
+
+// breakingChanges compares the types declared in the previous idl or used by its services, and the services
+func breakingChanges(previous, current *idlFile) []string {
+	var changes []string
+	for _, name := range previous.checkedTypes() {
+		if s, ok := previous.structs[name]; ok {
+			cs, ok := current.structs[name]
+			if !ok {
+				changes = append(changes, fmt.Sprintf("struct '%s' is removed", name))
+				continue
+			}
+			changes = append(changes, structChanges(s, cs)...)
+		}
+		if e, ok := previous.enums[name]; ok {
+			ce, ok := current.enums[name]
+			if !ok {
+				changes = append(changes, fmt.Sprintf("enum '%s' is removed", name))
+				continue
+			}
+			changes = append(changes, enumChanges(e, ce)...)
+		}
+	}
+
+	services := make(map[string]*idlService, len(current.Services))
+	for _, s := range current.Services {
+		services[s.Name] = s
+	}
+	for _, s := range previous.Services {
+		cs, ok := services[s.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("service '%s' is removed", s.Name))
+			continue
+		}
+		changes = append(changes, serviceChanges(s, cs)...)
+	}
+	return changes
+}
+
+// structChanges compares the fields by the ids, which are the identities of the fields on the wire
+func structChanges(previous, current *idlStruct) []string {
+	var changes []string
+	byID := make(map[int32]*idlField, len(current.Fields))
+	byName := make(map[string]*idlField, len(current.Fields))
+	for _, f := range current.Fields {
+		byID[f.ID] = f
+		byName[f.Name] = f
+	}
+	fields := make(map[int32]bool, len(previous.Fields))
+	for _, f := range previous.Fields {
+		fields[f.ID] = true
+		name := previous.FullName + "." + f.Name
+		cf, ok := byID[f.ID]
+		if !ok {
+			if cf, ok = byName[f.Name]; ok {
+				changes = append(changes, fmt.Sprintf("the id of field '%s' is changed from %d to %d", name, f.ID, cf.ID))
+			} else {
+				changes = append(changes, fmt.Sprintf("field '%s' (%d) is removed", name, f.ID))
+			}
+			continue
+		}
+		if cf.Type != f.Type {
+			changes = append(changes, fmt.Sprintf("the type of field '%s' is changed from '%s' to '%s'", name, f.Type, cf.Type))
+		}
+		if cf.JSONName != f.JSONName {
+			changes = append(changes, fmt.Sprintf("the json name of field '%s' is changed from '%s' to '%s'", name, f.JSONName, cf.JSONName))
+		}
+		if cf.Required && !f.Required {
+			changes = append(changes, fmt.Sprintf("field '%s' becomes required", name))
+		}
+		bindings := make(map[idlBinding]bool, len(cf.Bindings))
+		for _, b := range cf.Bindings {
+			bindings[b] = true
+		}
+		for _, b := range f.Bindings {
+			if !bindings[b] {
+				changes = append(changes, fmt.Sprintf("the binding of %s of field '%s' is removed", b, name))
+			}
+		}
+	}
+	for _, cf := range current.Fields {
+		if cf.Required && !fields[cf.ID] {
+			changes = append(changes, fmt.Sprintf("required field '%s.%s' (%d) is added", current.FullName, cf.Name, cf.ID))
+		}
+	}
+	return changes
+}
+
+func enumChanges(previous, current *idlEnum) []string {
+	var changes []string
+	values := make(map[string]int64, len(current.Values))
+	for _, v := range current.Values {
+		values[v.Name] = v.Value
+	}
+	for _, v := range previous.Values {
+		name := previous.FullName + "." + v.Name
+		cv, ok := values[v.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("enum value '%s' is removed", name))
+		} else if cv != v.Value {
+			changes = append(changes, fmt.Sprintf("the value of enum value '%s' is changed from %d to %d", name, v.Value, cv))
+		}
+	}
+	return changes
+}
+
+func serviceChanges(previous, current *idlService) []string {
+	var changes []string
+	methods := make(map[string]*idlMethod, len(current.Methods))
+	for _, m := range current.Methods {
+		methods[m.Name] = m
+	}
+	for _, m := range previous.Methods {
+		name := previous.Name + "." + m.Name
+		cm, ok := methods[m.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("method '%s' is removed", name))
+			continue
+		}
+		if cm.Request != m.Request {
+			changes = append(changes, fmt.Sprintf("the request of method '%s' is changed from '%s' to '%s'", name, m.Request, cm.Request))
+		}
+		if cm.Response != m.Response {
+			changes = append(changes, fmt.Sprintf("the response of method '%s' is changed from '%s' to '%s'", name, m.Response, cm.Response))
+		}
+		routes := make(map[string]bool, len(cm.Routes))
+		for _, r := range cm.Routes {
+			routes[r.Method+" "+generator.RoutePattern(r.Path)] = true
+		}
+		for _, r := range m.Routes {
+			if !routes[r.Method+" "+generator.RoutePattern(r.Path)] {
+				changes = append(changes, fmt.Sprintf("route '%s' of method '%s' is removed", r, name))
+			}
+		}
+	}
+	return changes
+}
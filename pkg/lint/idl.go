@@ -0,0 +1,171 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hu-1996/cwgo/pkg/common/utils"
+	"github.com/hu-1996/cwgo/pkg/consts"
+)
+
+// idlFile is the thrift or proto idl for checking, the structs, enums and services are the ones declared in the file,
+// the types are the structs and enums of the file and its includes by the full names
+type idlFile struct {
+	Path     string
+	Structs  []*idlStruct
+	Enums    []*idlEnum
+	Services []*idlService
+
+	structs map[string]*idlStruct
+	enums   map[string]*idlEnum
+}
+
+type idlStruct struct {
+	FullName string // "{file}.{name}" for thrift, "{package}.{name}" for proto
+	Name     string
+	Fields   []*idlField
+}
+
+type idlField struct {
+	ID       int32
+	Name     string
+	Type     string // the named types are in the full names, such as "list<base.Item>" or "repeated hello.Item"
+	JSONName string
+	Required bool
+	Bindings []idlBinding // the bindings of the api annotations except the json
+
+	refs []string // the full names of the named types in the type
+}
+
+type idlBinding struct {
+	In   string // query, path, header, cookie or form
+	Name string
+}
+
+func (b idlBinding) String() string {
+	return b.In + " '" + b.Name + "'"
+}
+
+type idlEnum struct {
+	FullName string
+	Name     string
+	Values   []*idlEnumValue
+}
+
+type idlEnumValue struct {
+	Name  string
+	Value int64
+}
+
+type idlService struct {
+	Name    string
+	Methods []*idlMethod
+}
+
+type idlMethod struct {
+	Name     string
+	Request  string // full name of the request, it is empty if the method has no request
+	Response string // full name of the response, it is empty if the method is void
+	Throws   []string
+	Routes   []idlRoute
+}
+
+type idlRoute struct {
+	Method string // upper case, "ANY" matches all the methods
+	Path   string
+}
+
+func (r idlRoute) String() string {
+	return r.Method + " " + r.Path
+}
+
+func newIdlFile(path string) *idlFile {
+	return &idlFile{
+		Path:    path,
+		structs: make(map[string]*idlStruct),
+		enums:   make(map[string]*idlEnum),
+	}
+}
+
+// loadIdl parses the idl and its includes into idlFile
+func loadIdl(path string, includes []string) (*idlFile, error) {
+	idlType, err := utils.GetIdlType(path)
+	if err != nil {
+		return nil, err
+	}
+	switch idlType {
+	case consts.Thrift:
+		return loadThrift(path, includes)
+	case consts.Proto:
+		return loadProto(path, includes)
+	default:
+		return nil, fmt.Errorf("IDL type %s is not supported", idlType)
+	}
+}
+
+// usedTypes returns the full names of the types used by the services directly or indirectly
+func (f *idlFile) usedTypes() map[string]bool {
+	used := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if name == "" || used[name] {
+			return
+		}
+		used[name] = true
+		if s, ok := f.structs[name]; ok {
+			for _, field := range s.Fields {
+				for _, ref := range field.refs {
+					visit(ref)
+				}
+			}
+		}
+	}
+	for _, s := range f.Services {
+		for _, m := range s.Methods {
+			visit(m.Request)
+			visit(m.Response)
+			for _, t := range m.Throws {
+				visit(t)
+			}
+		}
+	}
+	return used
+}
+
+// checkedTypes returns the full names of the types declared in the file, followed by the used types of the includes in order
+func (f *idlFile) checkedTypes() []string {
+	var out []string
+	declared := make(map[string]bool)
+	for _, s := range f.Structs {
+		out = append(out, s.FullName)
+		declared[s.FullName] = true
+	}
+	for _, e := range f.Enums {
+		out = append(out, e.FullName)
+		declared[e.FullName] = true
+	}
+	var included []string
+	for name := range f.usedTypes() {
+		if !declared[name] {
+			included = append(included, name)
+		}
+	}
+	sort.Strings(included)
+	return append(out, included...)
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/hertz/generator"
+)
+
+var (
+	pascalCaseRegexp     = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	snakeCaseRegexp      = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	upperSnakeCaseRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+)
+
+type lintRule struct {
+	Name  string
+	Check func(f *idlFile) []string
+}
+
+// lintRules are the rules of lint in the order of checking
+var lintRules = []lintRule{
+	{Name: "naming", Check: lintNaming},
+	{Name: "http_annotation", Check: lintHTTPAnnotations},
+	{Name: "route_conflict", Check: lintRouteConflicts},
+	{Name: "binding", Check: lintBindings},
+	{Name: "unused", Check: lintUnused},
+}
+
+// Lint checks the naming, http annotations, routes, field bindings and unused structs of the idl,
+// the problems are printed and an error is returned if there is any problem, so that it can be used in the CI
+func Lint(c *config.LintArgument) error {
+	rules, err := selectRules(c.Rules, c.SkipRules)
+	if err != nil {
+		return err
+	}
+	f, err := loadIdl(c.IdlPath, c.ProtoSearchPath)
+	if err != nil {
+		return err
+	}
+	return report(f.Path, lintIdl(f, rules), "problems")
+}
+
+// selectRules returns the selected rules except the skipped ones, all the rules are selected if none is specified
+func selectRules(selected, skipped []string) ([]lintRule, error) {
+	known := make(map[string]bool, len(lintRules))
+	var names []string
+	for _, r := range lintRules {
+		known[r.Name] = true
+		names = append(names, r.Name)
+	}
+	check := func(rules []string) (map[string]bool, error) {
+		set := make(map[string]bool, len(rules))
+		for _, r := range rules {
+			if !known[r] {
+				return nil, fmt.Errorf("unknown lint rule '%s', it should be one of %s", r, strings.Join(names, ", "))
+			}
+			set[r] = true
+		}
+		return set, nil
+	}
+	include, err := check(selected)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := check(skipped)
+	if err != nil {
+		return nil, err
+	}
+	var rules []lintRule
+	for _, r := range lintRules {
+		if (len(include) == 0 || include[r.Name]) && !exclude[r.Name] {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+func lintIdl(f *idlFile, rules []lintRule) []string {
+	var problems []string
+	for _, r := range rules {
+		problems = append(problems, r.Check(f)...)
+	}
+	return problems
+}
+
+// report prints the problems of the idl and returns an error if there is any
+func report(path string, problems []string, kind string) error {
+	for _, p := range problems {
+		fmt.Printf("%s: %s\n", path, p)
+	}
+	if len(problems) != 0 {
+		return fmt.Errorf("%d %s are found in '%s'", len(problems), kind, path)
+	}
+	return nil
+}
+
+func lintNaming(f *idlFile) []string {
+	var problems []string
+	for _, s := range f.Structs {
+		if !pascalCaseRegexp.MatchString(s.Name) {
+			problems = append(problems, fmt.Sprintf("struct '%s' should be in PascalCase", s.Name))
+		}
+		for _, field := range s.Fields {
+			if !snakeCaseRegexp.MatchString(field.Name) {
+				problems = append(problems, fmt.Sprintf("field '%s.%s' should be in snake_case", s.Name, field.Name))
+			}
+		}
+	}
+	for _, e := range f.Enums {
+		if !pascalCaseRegexp.MatchString(e.Name) {
+			problems = append(problems, fmt.Sprintf("enum '%s' should be in PascalCase", e.Name))
+		}
+		for _, v := range e.Values {
+			if !upperSnakeCaseRegexp.MatchString(v.Name) {
+				problems = append(problems, fmt.Sprintf("enum value '%s.%s' should be in UPPER_SNAKE_CASE", e.Name, v.Name))
+			}
+		}
+	}
+	for _, s := range f.Services {
+		if !pascalCaseRegexp.MatchString(s.Name) {
+			problems = append(problems, fmt.Sprintf("service '%s' should be in PascalCase", s.Name))
+		}
+		for _, m := range s.Methods {
+			if !pascalCaseRegexp.MatchString(m.Name) {
+				problems = append(problems, fmt.Sprintf("method '%s.%s' should be in PascalCase", s.Name, m.Name))
+			}
+		}
+	}
+	return problems
+}
+
+// lintHTTPAnnotations checks the methods without the http annotations, it should be skipped for the rpc only idl
+func lintHTTPAnnotations(f *idlFile) []string {
+	var problems []string
+	for _, s := range f.Services {
+		for _, m := range s.Methods {
+			if len(m.Routes) == 0 {
+				problems = append(problems, fmt.Sprintf("method '%s.%s' has no http annotation", s.Name, m.Name))
+			}
+		}
+	}
+	return problems
+}
+
+// lintRouteConflicts checks the routes matching the same requests like the generator of hertz
+func lintRouteConflicts(f *idlFile) []string {
+	var problems []string
+	type owner struct {
+		route  generator.Route
+		method string
+	}
+	var owners []owner
+	for _, s := range f.Services {
+		for _, m := range s.Methods {
+			name := s.Name + "." + m.Name
+			for _, r := range m.Routes {
+				route := generator.Route{HttpMethod: r.Method, Path: r.Path}
+				for _, o := range owners {
+					if route.Conflicts(o.route) {
+						problems = append(problems, fmt.Sprintf("route '%s' of method '%s' conflicts with route '%s %s' of method '%s'",
+							r, name, o.route.HttpMethod, o.route.Path, o.method))
+					}
+				}
+				owners = append(owners, owner{route: route, method: name})
+			}
+		}
+	}
+	return problems
+}
+
+// lintBindings checks the fields of a struct bound to the same parameter or json name
+func lintBindings(f *idlFile) []string {
+	var problems []string
+	for _, s := range f.Structs {
+		bound := make(map[string]string)
+		check := func(field *idlField, b idlBinding) {
+			key := b.In + ":" + b.Name
+			if b.In == "header" {
+				// the headers are case-insensitive
+				key = strings.ToLower(key)
+			}
+			if other, ok := bound[key]; ok {
+				problems = append(problems, fmt.Sprintf("field '%s.%s' binds %s which is bound by field '%s.%s'", s.Name, field.Name, b, s.Name, other))
+				return
+			}
+			bound[key] = field.Name
+		}
+		for _, field := range s.Fields {
+			check(field, idlBinding{In: "json", Name: field.JSONName})
+			for _, b := range field.Bindings {
+				check(field, b)
+			}
+		}
+	}
+	return problems
+}
+
+// lintUnused checks the structs declared in the idl but not used by the services, it is skipped if there is no service
+func lintUnused(f *idlFile) []string {
+	if len(f.Services) == 0 {
+		return nil
+	}
+	var problems []string
+	used := f.usedTypes()
+	for _, s := range f.Structs {
+		if !used[s.FullName] {
+			problems = append(problems, fmt.Sprintf("struct '%s' is not used by any service", s.Name))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const lintThrift = `
+namespace go hello
+
+include "base.thrift"
+
+struct helloReq {
+    1: string Name (api.query="name");
+    2: string nick_name (api.query="name");
+    3: base.Base base;
+}
+
+struct HelloResp {
+    1: string message;
+}
+
+struct Unused {
+    1: string name;
+}
+
+enum Status {
+    ok = 0;
+}
+
+service HelloService {
+    HelloResp Hello(1: helloReq req) (api.get="/hello/:id");
+    HelloResp Hello2(1: helloReq req) (api.any="/hello/:name");
+    HelloResp Hello3(1: helloReq req);
+}
+`
+
+const baseThrift = `
+namespace go base
+
+struct Base {
+    1: string log_id;
+}
+`
+
+func writeIdl(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLintThrift(t *testing.T) {
+	dir := t.TempDir()
+	writeIdl(t, dir, "base.thrift", baseThrift)
+	path := writeIdl(t, dir, "hello.thrift", lintThrift)
+
+	f, err := loadIdl(path, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"struct 'helloReq' should be in PascalCase",
+		"field 'helloReq.Name' should be in snake_case",
+		"enum value 'Status.ok' should be in UPPER_SNAKE_CASE",
+		"method 'HelloService.Hello3' has no http annotation",
+		"route 'ANY /hello/:name' of method 'HelloService.Hello2' conflicts with route 'GET /hello/:id' of method 'HelloService.Hello'",
+		"field 'helloReq.nick_name' binds query 'name' which is bound by field 'helloReq.Name'",
+		"struct 'Unused' is not used by any service",
+	}, lintIdl(f, lintRules))
+	assert.True(t, f.usedTypes()["base.Base"])
+
+	assert.Error(t, Lint(&config.LintArgument{IdlPath: path}))
+}
+
+func TestSelectRules(t *testing.T) {
+	dir := t.TempDir()
+	writeIdl(t, dir, "base.thrift", baseThrift)
+	path := writeIdl(t, dir, "hello.thrift", lintThrift)
+	f, err := loadIdl(path, nil)
+	assert.NoError(t, err)
+
+	rules, err := selectRules([]string{"http_annotation", "route_conflict"}, []string{"route_conflict"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"method 'HelloService.Hello3' has no http annotation"}, lintIdl(f, rules))
+
+	// the rpc only idl passes without the rule of the http annotations
+	rules, err = selectRules(nil, []string{"http_annotation"})
+	assert.NoError(t, err)
+	assert.NotContains(t, lintIdl(f, rules), "method 'HelloService.Hello3' has no http annotation")
+	assert.Len(t, rules, len(lintRules)-1)
+
+	_, err = selectRules([]string{"unknown"}, nil)
+	assert.Error(t, err)
+	assert.Error(t, Lint(&config.LintArgument{IdlPath: path, SkipRules: []string{"http"}}))
+}
+
+const breakingProtoBefore = `
+syntax = "proto3";
+
+package hello;
+
+import "api.proto";
+
+message HelloReq {
+  string name = 1 [(api.query) = "name"];
+  int32 age = 2;
+  string nick = 3;
+  Status status = 4;
+}
+
+message HelloResp {
+  string message = 1 [(api.body) = "message"];
+}
+
+enum Status {
+  STATUS_UNKNOWN = 0;
+  STATUS_OK = 1;
+}
+
+service HelloService {
+  rpc Hello(HelloReq) returns (HelloResp) {
+    option (api.get) = "/hello/:id";
+  }
+  rpc Bye(HelloReq) returns (HelloResp) {
+    option (api.post) = "/bye";
+  }
+}
+`
+
+const breakingProtoAfter = `
+syntax = "proto3";
+
+package hello;
+
+import "api.proto";
+
+message HelloReq {
+  string name = 1 [(api.query) = "user_name"];
+  int64 age = 2;
+  string nick = 5;
+  Status status = 4;
+}
+
+message HelloResp {
+  string message = 1 [(api.body) = "msg"];
+}
+
+enum Status {
+  STATUS_UNKNOWN = 0;
+  STATUS_OK = 2;
+}
+
+service HelloService {
+  rpc Hello(HelloReq) returns (HelloResp) {
+    option (api.get) = "/hello/:user_id";
+  }
+}
+`
+
+func TestBreakingProto(t *testing.T) {
+	before := writeIdl(t, t.TempDir(), "hello.proto", breakingProtoBefore)
+	after := writeIdl(t, t.TempDir(), "hello.proto", breakingProtoAfter)
+
+	previous, err := loadIdl(before, nil)
+	assert.NoError(t, err)
+	current, err := loadIdl(after, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"the binding of query 'name' of field 'hello.HelloReq.name' is removed",
+		"the type of field 'hello.HelloReq.age' is changed from 'int32' to 'int64'",
+		"the id of field 'hello.HelloReq.nick' is changed from 3 to 5",
+		"the json name of field 'hello.HelloResp.message' is changed from 'message' to 'msg'",
+		"the value of enum value 'hello.Status.STATUS_OK' is changed from 1 to 2",
+		"method 'HelloService.Bye' is removed",
+	}, breakingChanges(previous, current))
+	assert.Empty(t, breakingChanges(current, current))
+
+	assert.Error(t, Breaking(&config.BreakingArgument{IdlPath: after, Against: before}))
+	assert.NoError(t, Breaking(&config.BreakingArgument{IdlPath: after, Against: after}))
+}
+
+const breakingThrift = `
+include "base.thrift"
+
+struct HelloReq {
+    1: string name (api.query="name");
+    2: base.Base base;
+}
+
+struct HelloResp {
+    1: string message;
+}
+
+service HelloService {
+    HelloResp Hello(1: HelloReq req) (api.get="/hello");
+}
+`
+
+func TestBreakingThrift(t *testing.T) {
+	// the against file is named differently, the types are keyed by the namespace or the path relative to it
+	dir, againstDir := t.TempDir(), t.TempDir()
+	writeIdl(t, dir, "base.thrift", baseThrift)
+	path := writeIdl(t, dir, "hello.thrift", breakingThrift)
+	writeIdl(t, againstDir, "base.thrift", baseThrift)
+	against := writeIdl(t, againstDir, "hello_old.thrift", breakingThrift)
+	assert.NoError(t, Breaking(&config.BreakingArgument{IdlPath: path, Against: against}))
+
+	namespaced := writeIdl(t, dir, "hello_ns.thrift", "namespace go hello\n"+breakingThrift)
+	againstNamespaced := writeIdl(t, againstDir, "hello_ns_old.thrift", "namespace go hello\n"+strings.Replace(breakingThrift, "2: base.Base", "3: base.Base", 1))
+	previous, err := loadIdl(againstNamespaced, nil)
+	assert.NoError(t, err)
+	current, err := loadIdl(namespaced, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"the id of field 'hello.HelloReq.base' is changed from 3 to 2"}, breakingChanges(previous, current))
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/hertz/cmd/hz/protobuf/api"
+	"github.com/hu-1996/cwgo/hertz/protobuf"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/runtime/protoimpl"
+)
+
+// protoBindingOptions are the options of the field bindings of hz, the "api.body" is the json name
+var protoBindingOptions = []struct {
+	in  string
+	ext *protoimpl.ExtensionInfo
+}{
+	{"query", api.E_Query},
+	{"path", api.E_Path},
+	{"header", api.E_Header},
+	{"cookie", api.E_Cookie},
+	{"form", api.E_Form},
+}
+
+func loadProto(path string, includes []string) (*idlFile, error) {
+	p := protoparse.Parser{
+		ImportPaths: append([]string{filepath.Dir(path)}, includes...),
+		// the api.proto of hz and the google/api/annotations.proto are resolved from the registered descriptors
+		LookupImport: desc.LoadFileDescriptor,
+	}
+	fds, err := p.ParseFiles(filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("parse proto idl '%s' failed, err: %v", path, err)
+	}
+	main := fds[0]
+	f := newIdlFile(path)
+
+	seen := make(map[string]bool)
+	var addFile func(fd *desc.FileDescriptor)
+	addFile = func(fd *desc.FileDescriptor) {
+		if seen[fd.GetName()] {
+			return
+		}
+		seen[fd.GetName()] = true
+		for _, md := range fd.GetMessageTypes() {
+			addProtoMessage(f, md, fd == main)
+		}
+		for _, ed := range fd.GetEnumTypes() {
+			addProtoEnum(f, ed, fd == main)
+		}
+		for _, dep := range fd.GetDependencies() {
+			addFile(dep)
+		}
+	}
+	addFile(main)
+
+	for _, sd := range main.GetServices() {
+		s := &idlService{Name: sd.GetName()}
+		for _, md := range sd.GetMethods() {
+			m := &idlMethod{
+				Name:     md.GetName(),
+				Request:  md.GetInputType().GetFullyQualifiedName(),
+				Response: md.GetOutputType().GetFullyQualifiedName(),
+			}
+			routes, err := protobuf.GetHttpRoutes(md.GetMethodOptions(), md.GetName())
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range routes {
+				m.Routes = append(m.Routes, idlRoute{Method: strings.ToUpper(r.Method), Path: r.Path})
+			}
+			s.Methods = append(s.Methods, m)
+		}
+		f.Services = append(f.Services, s)
+	}
+	return f, nil
+}
+
+func addProtoMessage(f *idlFile, md *desc.MessageDescriptor, declared bool) {
+	if md.IsMapEntry() {
+		return
+	}
+	s := &idlStruct{FullName: md.GetFullyQualifiedName(), Name: md.GetName()}
+	for _, fd := range md.GetFields() {
+		field := &idlField{
+			ID:       fd.GetNumber(),
+			Name:     fd.GetName(),
+			JSONName: fd.GetName(),
+			Required: fd.IsRequired(),
+		}
+		field.Type = protoType(fd, &field.refs)
+		opts := fd.GetFieldOptions()
+		if opts != nil && proto.HasExtension(opts, api.E_Body) {
+			field.JSONName = proto.GetExtension(opts, api.E_Body).(string)
+		}
+		for _, b := range protoBindingOptions {
+			if opts != nil && proto.HasExtension(opts, b.ext) {
+				field.Bindings = append(field.Bindings, idlBinding{In: b.in, Name: proto.GetExtension(opts, b.ext).(string)})
+			}
+		}
+		s.Fields = append(s.Fields, field)
+	}
+	f.structs[s.FullName] = s
+	if declared {
+		f.Structs = append(f.Structs, s)
+	}
+	for _, nested := range md.GetNestedMessageTypes() {
+		addProtoMessage(f, nested, declared)
+	}
+	for _, ed := range md.GetNestedEnumTypes() {
+		addProtoEnum(f, ed, declared)
+	}
+}
+
+func addProtoEnum(f *idlFile, ed *desc.EnumDescriptor, declared bool) {
+	e := &idlEnum{FullName: ed.GetFullyQualifiedName(), Name: ed.GetName()}
+	for _, v := range ed.GetValues() {
+		e.Values = append(e.Values, &idlEnumValue{Name: v.GetName(), Value: int64(v.GetNumber())})
+	}
+	f.enums[e.FullName] = e
+	if declared {
+		f.Enums = append(f.Enums, e)
+	}
+}
+
+// protoType returns the type of the field with the full names of the messages and enums
+func protoType(fd *desc.FieldDescriptor, refs *[]string) string {
+	if fd.IsMap() {
+		return "map<" + protoType(fd.GetMapKeyType(), refs) + "," + protoType(fd.GetMapValueType(), refs) + ">"
+	}
+	var typ string
+	switch {
+	case fd.GetMessageType() != nil:
+		typ = fd.GetMessageType().GetFullyQualifiedName()
+		*refs = append(*refs, typ)
+	case fd.GetEnumType() != nil:
+		typ = fd.GetEnumType().GetFullyQualifiedName()
+		*refs = append(*refs, typ)
+	default:
+		typ = strings.ToLower(strings.TrimPrefix(fd.GetType().String(), "TYPE_"))
+	}
+	if fd.IsRepeated() {
+		return "repeated " + typ
+	}
+	return typ
+}
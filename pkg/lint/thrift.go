@@ -0,0 +1,162 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/thriftgo/parser"
+)
+
+// thriftRouteAnnotations are the annotations of the routes of hz
+var thriftRouteAnnotations = []string{
+	"api.get", "api.post", "api.put", "api.patch", "api.delete", "api.options", "api.head", "api.any",
+}
+
+// thriftBindingAnnotations are the annotations of the field bindings of hz, the "api.body" is the json name
+var thriftBindingAnnotations = []string{
+	"api.query", "api.path", "api.header", "api.cookie", "api.form",
+}
+
+var thriftBaseTypes = map[string]bool{
+	"bool": true, "byte": true, "i8": true, "i16": true, "i32": true, "i64": true,
+	"double": true, "string": true, "binary": true, "void": true,
+}
+
+func loadThrift(path string, includes []string) (*idlFile, error) {
+	ast, err := parser.ParseFile(path, includes, true)
+	if err != nil {
+		return nil, fmt.Errorf("parse thrift idl '%s' failed, err: %v", path, err)
+	}
+	f := newIdlFile(path)
+	for t := range ast.DepthFirstSearch() {
+		scope := thriftScope(ast, t)
+		for _, st := range t.GetStructLikes() {
+			s := &idlStruct{FullName: thriftFullName(scope, st.Name), Name: st.Name}
+			for _, fd := range st.Fields {
+				field := &idlField{
+					ID:       fd.ID,
+					Name:     fd.Name,
+					JSONName: fd.Name,
+					Required: fd.Requiredness == parser.FieldType_Required,
+				}
+				field.Type = thriftType(ast, t, fd.Type, &field.refs)
+				if v := fd.Annotations.Get("api.body"); len(v) != 0 {
+					field.JSONName = v[0]
+				}
+				for _, anno := range thriftBindingAnnotations {
+					if v := fd.Annotations.Get(anno); len(v) != 0 {
+						field.Bindings = append(field.Bindings, idlBinding{In: strings.TrimPrefix(anno, "api."), Name: v[0]})
+					}
+				}
+				s.Fields = append(s.Fields, field)
+			}
+			f.structs[s.FullName] = s
+			if t == ast {
+				f.Structs = append(f.Structs, s)
+			}
+		}
+		for _, en := range t.Enums {
+			e := &idlEnum{FullName: thriftFullName(scope, en.Name), Name: en.Name}
+			for _, v := range en.Values {
+				e.Values = append(e.Values, &idlEnumValue{Name: v.Name, Value: v.Value})
+			}
+			f.enums[e.FullName] = e
+			if t == ast {
+				f.Enums = append(f.Enums, e)
+			}
+		}
+	}
+
+	for _, svc := range ast.Services {
+		s := &idlService{Name: svc.Name}
+		for _, fn := range svc.Functions {
+			m := &idlMethod{Name: fn.Name}
+			// hz only supports the method with one argument
+			if len(fn.Arguments) == 1 {
+				m.Request = thriftType(ast, ast, fn.Arguments[0].Type, nil)
+			}
+			if !fn.Void && fn.FunctionType != nil {
+				m.Response = thriftType(ast, ast, fn.FunctionType, nil)
+			}
+			for _, th := range fn.Throws {
+				m.Throws = append(m.Throws, thriftType(ast, ast, th.Type, nil))
+			}
+			for _, anno := range thriftRouteAnnotations {
+				for _, path := range fn.Annotations.Get(anno) {
+					m.Routes = append(m.Routes, idlRoute{Method: strings.ToUpper(strings.TrimPrefix(anno, "api.")), Path: path})
+				}
+			}
+			s.Methods = append(s.Methods, m)
+		}
+		f.Services = append(f.Services, s)
+	}
+	return f, nil
+}
+
+// thriftType returns the type with the full names of the named types, the typedefs are resolved
+func thriftType(root, t *parser.Thrift, typ *parser.Type, refs *[]string) string {
+	switch typ.Name {
+	case "list", "set":
+		return typ.Name + "<" + thriftType(root, t, typ.ValueType, refs) + ">"
+	case "map":
+		return "map<" + thriftType(root, t, typ.KeyType, refs) + "," + thriftType(root, t, typ.ValueType, refs) + ">"
+	}
+	if thriftBaseTypes[typ.Name] {
+		return typ.Name
+	}
+
+	owner, name := t, typ.Name
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		if ref, ok := t.GetReference(name[:idx]); ok {
+			owner, name = ref, name[idx+1:]
+		}
+	}
+	if td, ok := owner.GetTypedef(name); ok {
+		return thriftType(root, owner, td.Type, refs)
+	}
+	fullName := thriftFullName(thriftScope(root, owner), name)
+	if refs != nil {
+		*refs = append(*refs, fullName)
+	}
+	return fullName
+}
+
+// thriftScope returns the scope of the types of the thrift file, it is the go namespace of the file, or the path
+// relative to the root file if there is no namespace, so that the types keep their names if the root file is renamed
+func thriftScope(root, t *parser.Thrift) string {
+	if ns, ok := t.GetNamespace("go"); ok {
+		return ns
+	}
+	if t == root {
+		return ""
+	}
+	rel, err := filepath.Rel(filepath.Dir(root.Filename), t.Filename)
+	if err != nil {
+		rel = filepath.Base(t.Filename)
+	}
+	return filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+}
+
+func thriftFullName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
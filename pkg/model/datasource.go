@@ -0,0 +1,164 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/meta"
+	"github.com/hu-1996/cwgo/pkg/common/utils"
+	"github.com/hu-1996/cwgo/pkg/consts"
+)
+
+const dataSourceInitFile = "init.go"
+
+var dataSourceNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// dataSourceConfig is the data sources part of the model config, the query and model code of each
+// data source is generated in its own package, eg:
+//
+//	data_sources:
+//	  - name: user
+//	    db_type: mysql
+//	    dsn: gorm:gorm@tcp(localhost:3306)/user
+//	    tables:
+//	      - users
+type dataSourceConfig struct {
+	// Name is the package name of the data source
+	Name   string `yaml:"name"`
+	DBType string `yaml:"db_type"`
+	DSN    string `yaml:"dsn"`
+	// Tables is the tables of the data source, empty means all the tables
+	Tables []string `yaml:"tables"`
+}
+
+type dataSourceInit struct {
+	Version      string
+	Name         string
+	Driver       string
+	EnvPrefix    string // the prefix of the environment variables of the DSNs
+	QueryImport  string
+	QueryPkgName string
+}
+
+func checkDataSources(sources []*dataSourceConfig) error {
+	names := make(map[string]bool, len(sources))
+	for _, ds := range sources {
+		if !dataSourceNameRegexp.MatchString(ds.Name) {
+			return fmt.Errorf("invalid data source name '%s', it should be a lowercase package name", ds.Name)
+		}
+		if names[ds.Name] {
+			return fmt.Errorf("duplicate data source name '%s'", ds.Name)
+		}
+		names[ds.Name] = true
+		if ds.DSN == "" {
+			return fmt.Errorf("the dsn of data source '%s' is empty", ds.Name)
+		}
+		if ds.DBType != "" {
+			if _, ok := config.OpenTypeFuncMap[consts.DataBaseType(strings.ToLower(ds.DBType))]; !ok {
+				return fmt.Errorf("unknown db type '%s' of data source '%s' (support mysql || postgres || sqlite || sqlserver for now)", ds.DBType, ds.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// genDataSources generates the query and model code of each data source in <dal>/<name>/ and
+// the init code which opens the data source with the read replicas registered by dbresolver.
+// The DSNs are only used for the generation, the init code reads them from the environment.
+func genDataSources(c *config.ModelArgument, mc *modelConfig) error {
+	if c.ToIDL != "" {
+		return fmt.Errorf("--%s is not supported with the data sources, please generate the idl of each database separately", consts.ToIDL)
+	}
+	for _, ds := range mc.DataSources {
+		dc := *c
+		dc.DSN, dc.SQLDir, dc.Tables = ds.DSN, "", ds.Tables
+		if ds.DBType != "" {
+			dc.Type = strings.ToLower(ds.DBType)
+		}
+		dc.OutPath = filepath.Join(filepath.Dir(c.OutPath), ds.Name, filepath.Base(c.OutPath))
+		if err := genModel(&dc, mc); err != nil {
+			return fmt.Errorf("generate data source '%s' failed: %w", ds.Name, err)
+		}
+		if dc.OnlyModel {
+			continue
+		}
+
+		queryImport, err := importPath(dc.OutPath)
+		if err != nil {
+			return err
+		}
+		code, err := renderDataSourceInit(&dataSourceInit{
+			Version:      meta.Version,
+			Name:         ds.Name,
+			Driver:       dc.Type,
+			EnvPrefix:    strings.ToUpper(ds.Name),
+			QueryImport:  queryImport,
+			QueryPkgName: filepath.Base(dc.OutPath),
+		})
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(filepath.Join(filepath.Dir(dc.OutPath), dataSourceInitFile), code, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderDataSourceInit renders the init code of the data source
+func renderDataSourceInit(init *dataSourceInit) ([]byte, error) {
+	tmpl, err := template.New("data_source").Parse(dataSourceTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, init); err != nil {
+		return nil, fmt.Errorf("render init of data source '%s' failed: %w", init.Name, err)
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format init of data source '%s' failed: %w", init.Name, err)
+	}
+	return code, nil
+}
+
+// importPath returns the import path of the directory by the go.mod above it
+func importPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	module, modPath, ok := utils.SearchGoMod(abs, true)
+	if !ok {
+		return "", fmt.Errorf("go.mod of '%s' not found, the code should be generated in a go module", dir)
+	}
+	rel, err := filepath.Rel(modPath, abs)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(module, filepath.ToSlash(rel)), nil
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+const dataSourceTemplate = `// Code generated by cwgo ({{.Version}}). DO NOT EDIT.
+
+package {{.Name}}
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gorm.io/driver/{{.Driver}}"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+
+	"{{.QueryImport}}"
+)
+
+// Config is the DSNs of the {{.Name}} data source, the writes go to the primary and the reads go to the replicas
+type Config struct {
+	DSN      string   ` + "`yaml:\"dsn\" json:\"dsn\"`" + `
+	Replicas []string ` + "`yaml:\"replicas\" json:\"replicas\"`" + `
+}
+
+// ConfigFromEnv reads the config from the environment variables {{.EnvPrefix}}_DSN and {{.EnvPrefix}}_REPLICAS,
+// the DSNs of the replicas are separated by comma
+func ConfigFromEnv() (Config, error) {
+	c := Config{DSN: os.Getenv("{{.EnvPrefix}}_DSN")}
+	if c.DSN == "" {
+		return c, fmt.Errorf("the environment variable {{.EnvPrefix}}_DSN of the {{.Name}} data source is empty")
+	}
+	if replicas := os.Getenv("{{.EnvPrefix}}_REPLICAS"); replicas != "" {
+		c.Replicas = strings.Split(replicas, ",")
+	}
+	return c, nil
+}
+
+var (
+	DB *gorm.DB
+	Q  *{{.QueryPkgName}}.Query
+)
+
+// Init opens the {{.Name}} data source and creates the query of it
+func Init(c Config, opts ...gorm.Option) error {
+	db, err := gorm.Open({{.Driver}}.Open(c.DSN), opts...)
+	if err != nil {
+		return err
+	}
+	if len(c.Replicas) > 0 {
+		replicas := make([]gorm.Dialector, 0, len(c.Replicas))
+		for _, dsn := range c.Replicas {
+			replicas = append(replicas, {{.Driver}}.Open(dsn))
+		}
+		if err = db.Use(dbresolver.Register(dbresolver.Config{
+			Replicas: replicas,
+			Policy:   dbresolver.RandomPolicy{},
+		})); err != nil {
+			return err
+		}
+	}
+	DB = db
+	Q = {{.QueryPkgName}}.Use(db)
+	return nil
+}
+`
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCheckDataSources(t *testing.T) {
+	type testCase struct {
+		name    string
+		sources []*dataSourceConfig
+		err     string
+	}
+	cases := []testCase{
+		{
+			name: "valid",
+			sources: []*dataSourceConfig{
+				{Name: "user", DSN: "user.db", DBType: "SQLite"},
+				{Name: "order_v2", DSN: "order.db"},
+			},
+		},
+		{name: "invalid name", sources: []*dataSourceConfig{{Name: "User", DSN: "user.db"}}, err: "invalid data source name 'User'"},
+		{name: "invalid name start", sources: []*dataSourceConfig{{Name: "1user", DSN: "user.db"}}, err: "invalid data source name '1user'"},
+		{name: "duplicate name", sources: []*dataSourceConfig{{Name: "user", DSN: "a.db"}, {Name: "user", DSN: "b.db"}}, err: "duplicate data source name 'user'"},
+		{name: "empty dsn", sources: []*dataSourceConfig{{Name: "user"}}, err: "the dsn of data source 'user' is empty"},
+		{name: "unknown db type", sources: []*dataSourceConfig{{Name: "user", DSN: "user.db", DBType: "oracle"}}, err: "unknown db type 'oracle'"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkDataSources(c.sources)
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestImportPath(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, consts.GoMod), []byte("module example.com/app\n\ngo 1.18\n"), 0o644))
+
+	type testCase struct {
+		dir    string
+		expect string
+	}
+	cases := []testCase{
+		{dir: dir, expect: "example.com/app"},
+		{dir: filepath.Join(dir, "biz", "dal", "query"), expect: "example.com/app/biz/dal/query"},
+	}
+	for _, c := range cases {
+		p, err := importPath(c.dir)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expect, p)
+	}
+}
+
+func TestRenderDataSourceInit(t *testing.T) {
+	type testCase struct {
+		name     string
+		init     *dataSourceInit
+		contains []string
+		excludes []string
+	}
+	cases := []testCase{
+		{
+			name: "user",
+			init: &dataSourceInit{
+				Version:      "test",
+				Name:         "user",
+				Driver:       "mysql",
+				EnvPrefix:    "USER",
+				QueryImport:  "example.com/app/biz/dal/user/query",
+				QueryPkgName: "query",
+			},
+			contains: []string{
+				"package user",
+				`"gorm.io/driver/mysql"`,
+				`"example.com/app/biz/dal/user/query"`,
+				`c := Config{DSN: os.Getenv("USER_DSN")}`,
+				`if replicas := os.Getenv("USER_REPLICAS"); replicas != "" {`,
+				"replicas = append(replicas, mysql.Open(dsn))",
+				"Q = query.Use(db)",
+			},
+		},
+		{
+			name: "sqlite",
+			init: &dataSourceInit{
+				Version:      "test",
+				Name:         "order",
+				Driver:       "sqlite",
+				EnvPrefix:    "ORDER",
+				QueryImport:  "example.com/app/biz/dal/order/dal",
+				QueryPkgName: "dal",
+			},
+			contains: []string{
+				"package order",
+				`os.Getenv("ORDER_DSN")`,
+				"db, err := gorm.Open(sqlite.Open(c.DSN), opts...)",
+				"Q  *dal.Query",
+			},
+			excludes: []string{"DefaultConfig"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			code, err := renderDataSourceInit(c.init)
+			assert.NoError(t, err)
+			_, err = parser.ParseFile(token.NewFileSet(), dataSourceInitFile, code, 0)
+			assert.NoError(t, err)
+			for _, s := range c.contains {
+				assert.Contains(t, string(code), s)
+			}
+			for _, s := range c.excludes {
+				assert.NotContains(t, string(code), s)
+			}
+		})
+	}
+}
+
+func sqliteDSN(db *gorm.DB) string {
+	return db.Dialector.(*sqlite.Dialector).DSN
+}
+
+// TestGenDataSources generates the data sources in the package dir, the import path of the query is resolved by the go.mod
+func TestGenDataSources(t *testing.T) {
+	dir, err := os.MkdirTemp(".", "_datasource_test_")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	userDSN := sqliteDSN(openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"))
+	orderDSN := sqliteDSN(openTestDB(t, "CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER)", "CREATE TABLE items (id INTEGER PRIMARY KEY)"))
+
+	c := config.NewModelArgument()
+	c.Type = string(consts.MySQL)
+	c.OutPath = filepath.Join(dir, "dal", "query")
+	mc := &modelConfig{DataSources: []*dataSourceConfig{
+		{Name: "user", DBType: "sqlite", DSN: userDSN},
+		{Name: "order", DBType: "sqlite", DSN: orderDSN, Tables: []string{"orders"}},
+	}}
+	assert.NoError(t, genDataSources(c, mc))
+
+	module, err := importPath(".")
+	assert.NoError(t, err)
+	for _, ds := range mc.DataSources {
+		code, err := os.ReadFile(filepath.Join(dir, "dal", ds.Name, dataSourceInitFile))
+		assert.NoError(t, err)
+		assert.Contains(t, string(code), "package "+ds.Name)
+		assert.Contains(t, string(code), `"`+module+"/"+filepath.ToSlash(filepath.Join(dir, "dal", ds.Name, "query"))+`"`)
+		assert.Contains(t, string(code), `"gorm.io/driver/sqlite"`)
+		assert.NotContains(t, string(code), ds.DSN)
+	}
+	assert.FileExists(t, filepath.Join(dir, "dal", "user", "query", "users.gen.go"))
+	assert.FileExists(t, filepath.Join(dir, "dal", "order", "query", "orders.gen.go"))
+	assert.NoFileExists(t, filepath.Join(dir, "dal", "order", "query", "items.gen.go"))
+	assert.NoDirExists(t, filepath.Join(dir, "dal", "query"))
+
+	// the data sources can not be converted to one idl
+	c.ToIDL = consts.Thrift
+	assert.ErrorContains(t, genDataSources(c, mc), "is not supported with the data sources")
+}
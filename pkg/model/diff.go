@@ -0,0 +1,367 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/kitex/tool/internal_pkg/log"
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"gorm.io/gorm"
+	"gorm.io/rawsql"
+)
+
+const migrationVersionLayout = "20060102150405"
+
+type schemaTable struct {
+	Name    string
+	Columns []gorm.ColumnType
+	Indexes []gorm.Index
+}
+
+func (t *schemaTable) column(name string) gorm.ColumnType {
+	for _, c := range t.Columns {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// primaryKeys returns the columns of the primary key in the column order
+func (t *schemaTable) primaryKeys() []string {
+	var keys []string
+	for _, c := range t.Columns {
+		if pk, _ := c.PrimaryKey(); pk {
+			keys = append(keys, c.Name())
+		}
+	}
+	return keys
+}
+
+func (t *schemaTable) index(name string) gorm.Index {
+	for _, idx := range t.Indexes {
+		if idx.Name() == name {
+			return idx
+		}
+	}
+	return nil
+}
+
+// Diff compares the schema of the database with the schema in the sql files,
+// and writes the versioned up/down migrations which turn the database into the sql schema
+func Diff(c *config.ModelDiffArgument) error {
+	// the sql files are parsed by rawsql as mysql ddl, so the types of the other databases never match them
+	if consts.DataBaseType(c.Type) != consts.MySQL {
+		return fmt.Errorf("db type %s is not supported by model diff, the sql schema is parsed as mysql ddl (support mysql for now)", c.Type)
+	}
+	dialector := config.OpenTypeFuncMap[consts.MySQL]
+
+	liveDB, err := gorm.Open(dialector(c.DSN))
+	if err != nil {
+		return err
+	}
+	sqlDB, err := gorm.Open(rawsql.New(rawsql.Config{
+		FilePath: []string{c.SQLDir},
+	}))
+	if err != nil {
+		return err
+	}
+
+	live, err := loadSchema(liveDB, c)
+	if err != nil {
+		return err
+	}
+	target, err := loadSchema(sqlDB, c)
+	if err != nil {
+		return err
+	}
+
+	up := diffSchemas(live, target)
+	if len(up) == 0 {
+		log.Warn("the database is up to date with the sql schema, no migration is generated")
+		return nil
+	}
+	down := diffSchemas(target, live)
+
+	version := time.Now().Format(migrationVersionLayout)
+	name := c.Name
+	if name == "" {
+		name = "migration"
+	}
+	if err = os.MkdirAll(c.OutDir, 0o755); err != nil {
+		return err
+	}
+	for suffix, statements := range map[string][]string{".up.sql": up, ".down.sql": down} {
+		file := filepath.Join(c.OutDir, version+"_"+name+suffix)
+		if err = os.WriteFile(file, []byte(strings.Join(statements, consts.LineBreak)+consts.LineBreak), 0o644); err != nil {
+			return err
+		}
+		log.Infof("migration %s is generated", file)
+	}
+	return nil
+}
+
+// loadSchema reads the tables, columns and indexes through the migrator of the db
+func loadSchema(db *gorm.DB, c *config.ModelDiffArgument) (map[string]*schemaTable, error) {
+	tables := c.Tables
+	if len(tables) == 0 {
+		var err error
+		tables, err = db.Migrator().GetTables()
+		if err != nil {
+			return nil, fmt.Errorf("migrator get all tables fail: %w", err)
+		}
+	}
+
+	schema := make(map[string]*schemaTable, len(tables))
+	for _, name := range tables {
+		if isExcluded(c, name) {
+			continue
+		}
+		columns, err := db.Migrator().ColumnTypes(name)
+		if err != nil {
+			return nil, fmt.Errorf("migrator get columns of table '%s' fail: %w", name, err)
+		}
+		if len(columns) == 0 {
+			// the specified table doesn't exist
+			continue
+		}
+		indexes, err := db.Migrator().GetIndexes(name)
+		if err != nil {
+			return nil, fmt.Errorf("migrator get indexes of table '%s' fail: %w", name, err)
+		}
+		t := &schemaTable{Name: name, Columns: columns}
+		for _, idx := range indexes {
+			if pk, _ := idx.PrimaryKey(); !pk {
+				t.Indexes = append(t.Indexes, idx)
+			}
+		}
+		schema[name] = t
+	}
+	return schema, nil
+}
+
+func isExcluded(c *config.ModelDiffArgument, table string) bool {
+	for _, t := range c.ExcludeTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// diffSchemas returns the statements which turn the from schema into the to schema,
+// so the down migration is the diff in the opposite direction
+func diffSchemas(from, to map[string]*schemaTable) (statements []string) {
+	for _, name := range sortedTables(to) {
+		toTable := to[name]
+		fromTable, ok := from[name]
+		if !ok {
+			statements = append(statements, createTable(toTable))
+			for _, idx := range toTable.Indexes {
+				statements = append(statements, createIndex(name, idx))
+			}
+			continue
+		}
+
+		for _, idx := range fromTable.Indexes {
+			if toIdx := toTable.index(idx.Name()); toIdx == nil || !sameIndex(idx, toIdx) {
+				statements = append(statements, dropIndex(name, idx))
+			}
+		}
+		fromKeys, toKeys := fromTable.primaryKeys(), toTable.primaryKeys()
+		samePrimaryKey := strings.Join(fromKeys, ",") == strings.Join(toKeys, ",")
+		if !samePrimaryKey && len(fromKeys) > 0 {
+			statements = append(statements, dropPrimaryKey(name))
+		}
+		for _, col := range toTable.Columns {
+			fromCol := fromTable.column(col.Name())
+			if fromCol == nil {
+				statements = append(statements, addColumn(name, col))
+			} else if !sameColumn(fromCol, col) {
+				statements = append(statements, alterColumn(name, col))
+			}
+		}
+		for _, col := range fromTable.Columns {
+			if toTable.column(col.Name()) == nil {
+				statements = append(statements, dropColumn(name, col))
+			}
+		}
+		if !samePrimaryKey && len(toKeys) > 0 {
+			statements = append(statements, addPrimaryKey(name, toKeys))
+		}
+		for _, idx := range toTable.Indexes {
+			if fromIdx := fromTable.index(idx.Name()); fromIdx == nil || !sameIndex(fromIdx, idx) {
+				statements = append(statements, createIndex(name, idx))
+			}
+		}
+	}
+
+	for _, name := range sortedTables(from) {
+		if _, ok := to[name]; !ok {
+			statements = append(statements, dropTable(name))
+		}
+	}
+	return statements
+}
+
+func sortedTables(schema map[string]*schemaTable) []string {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// integerWidth matches the display width of the integer types, eg: int(11)
+var integerWidth = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|integer|bigint)\(\d+\)`)
+
+// columnType normalizes the column type to compare, the display width except tinyint(1) is ignored
+func columnType(c gorm.ColumnType) string {
+	t, ok := c.ColumnType()
+	if !ok || t == "" {
+		t = c.DatabaseTypeName()
+	}
+	t = strings.ToLower(strings.Join(strings.Fields(t), ""))
+	if !strings.HasPrefix(t, "tinyint(1)") {
+		t = integerWidth.ReplaceAllString(t, "$1")
+	}
+	return strings.Replace(t, "integer", "int", 1)
+}
+
+func sameColumn(a, b gorm.ColumnType) bool {
+	if columnType(a) != columnType(b) {
+		return false
+	}
+	if an, ok := a.Nullable(); ok {
+		if bn, ok := b.Nullable(); ok && an != bn {
+			return false
+		}
+	}
+	ad, aok := a.DefaultValue()
+	bd, bok := b.DefaultValue()
+	return aok == bok && ad == bd
+}
+
+func sameIndex(a, b gorm.Index) bool {
+	au, _ := a.Unique()
+	bu, _ := b.Unique()
+	return au == bu && strings.Join(a.Columns(), ",") == strings.Join(b.Columns(), ",")
+}
+
+// quote quotes the identifier of mysql
+func quote(name string) string {
+	return "`" + name + "`"
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func columnTypeOf(col gorm.ColumnType) string {
+	if t, ok := col.ColumnType(); ok && t != "" {
+		return t
+	}
+	return col.DatabaseTypeName()
+}
+
+func columnDefinition(col gorm.ColumnType) string {
+	def := quote(col.Name()) + " " + columnTypeOf(col)
+	if nullable, ok := col.Nullable(); ok && !nullable {
+		def += " NOT NULL"
+	}
+	if value, ok := col.DefaultValue(); ok {
+		def += " DEFAULT " + defaultLiteral(value)
+	}
+	if autoIncrement, _ := col.AutoIncrement(); autoIncrement {
+		def += " AUTO_INCREMENT"
+	}
+	return def
+}
+
+// defaultLiteral quotes the default value unless it's a number, NULL or an expression
+func defaultLiteral(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	upper := strings.ToUpper(value)
+	if upper == "NULL" || upper == "TRUE" || upper == "FALSE" || strings.HasPrefix(upper, "CURRENT_") ||
+		strings.Contains(value, "(") || strings.HasPrefix(value, "'") {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func createTable(t *schemaTable) string {
+	lines := make([]string, 0, len(t.Columns)+1)
+	for _, col := range t.Columns {
+		lines = append(lines, "  "+columnDefinition(col))
+	}
+	if keys := t.primaryKeys(); len(keys) > 0 {
+		lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", quoteAll(keys)))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n);", quote(t.Name), strings.Join(lines, ",\n"))
+}
+
+func dropTable(table string) string {
+	return fmt.Sprintf("DROP TABLE %s;", quote(table))
+}
+
+func addColumn(table string, col gorm.ColumnType) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", quote(table), columnDefinition(col))
+}
+
+func alterColumn(table string, col gorm.ColumnType) string {
+	return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", quote(table), columnDefinition(col))
+}
+
+func dropColumn(table string, col gorm.ColumnType) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", quote(table), quote(col.Name()))
+}
+
+func dropPrimaryKey(table string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", quote(table))
+}
+
+func addPrimaryKey(table string, keys []string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", quote(table), quoteAll(keys))
+}
+
+func createIndex(table string, idx gorm.Index) string {
+	unique := ""
+	if u, _ := idx.Unique(); u {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, quote(idx.Name()), quote(table), quoteAll(idx.Columns()))
+}
+
+func dropIndex(table string, idx gorm.Index) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s;", quote(idx.Name()), quote(table))
+}
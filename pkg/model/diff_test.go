@@ -0,0 +1,141 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/rawsql"
+)
+
+func testColumn(name, typ string, nullable bool, pk bool) gorm.ColumnType {
+	return migrator.ColumnType{
+		NameValue:       sql.NullString{String: name, Valid: true},
+		ColumnTypeValue: sql.NullString{String: typ, Valid: true},
+		NullableValue:   sql.NullBool{Bool: nullable, Valid: true},
+		PrimaryKeyValue: sql.NullBool{Bool: pk, Valid: true},
+	}
+}
+
+func TestColumnType(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		same bool
+	}{
+		{"int(11)", "int", true},
+		{"INT(11) UNSIGNED", "int unsigned", true},
+		{"integer", "int", true},
+		{"bigint(20)", "bigint", true},
+		{"tinyint(1)", "tinyint", false},
+		{"tinyint(4)", "tinyint", true},
+		{"varchar(64)", "varchar(128)", false},
+		{"decimal(10,2)", "decimal(10, 2)", true},
+	} {
+		a, b := testColumn("c", tc.a, false, false), testColumn("c", tc.b, false, false)
+		assert.Equal(t, tc.same, columnType(a) == columnType(b), "%s vs %s", tc.a, tc.b)
+	}
+}
+
+func TestDiffSchemas(t *testing.T) {
+	live := map[string]*schemaTable{
+		"users": {
+			Name: "users",
+			Columns: []gorm.ColumnType{
+				testColumn("id", "int(11)", false, true),
+				testColumn("name", "varchar(32)", true, false),
+				testColumn("legacy", "text", true, false),
+			},
+			Indexes: []gorm.Index{
+				migrator.Index{NameValue: "idx_name", ColumnList: []string{"name"}},
+			},
+		},
+		"logs": {Name: "logs", Columns: []gorm.ColumnType{testColumn("id", "bigint", false, true)}},
+		"tags": {Name: "tags", Columns: []gorm.ColumnType{testColumn("id", "bigint", false, true), testColumn("name", "varchar(32)", false, false)}},
+	}
+	target := map[string]*schemaTable{
+		"users": {
+			Name: "users",
+			Columns: []gorm.ColumnType{
+				testColumn("id", "int", false, true),
+				testColumn("name", "varchar(64)", false, false),
+				testColumn("email", "varchar(128)", true, false),
+			},
+			Indexes: []gorm.Index{
+				migrator.Index{NameValue: "idx_name", ColumnList: []string{"name"}, UniqueValue: sql.NullBool{Bool: true, Valid: true}},
+			},
+		},
+		"orders": {Name: "orders", Columns: []gorm.ColumnType{testColumn("id", "bigint", false, true)}},
+		"tags":   {Name: "tags", Columns: []gorm.ColumnType{testColumn("id", "bigint", false, true), testColumn("name", "varchar(32)", false, true)}},
+	}
+
+	assert.Equal(t, []string{
+		"CREATE TABLE `orders` (\n  `id` bigint NOT NULL,\n  PRIMARY KEY (`id`)\n);",
+		"ALTER TABLE `tags` DROP PRIMARY KEY;",
+		"ALTER TABLE `tags` ADD PRIMARY KEY (`id`, `name`);",
+		"DROP INDEX `idx_name` ON `users`;",
+		"ALTER TABLE `users` MODIFY COLUMN `name` varchar(64) NOT NULL;",
+		"ALTER TABLE `users` ADD COLUMN `email` varchar(128);",
+		"ALTER TABLE `users` DROP COLUMN `legacy`;",
+		"CREATE UNIQUE INDEX `idx_name` ON `users` (`name`);",
+		"DROP TABLE `logs`;",
+	}, diffSchemas(live, target))
+
+	down := diffSchemas(target, live)
+	assert.Contains(t, down, "ALTER TABLE `tags` ADD PRIMARY KEY (`id`);")
+	assert.Contains(t, down, "DROP TABLE `orders`;")
+	assert.Empty(t, diffSchemas(live, live))
+}
+
+func TestLoadSchemaFromSQL(t *testing.T) {
+	dir := t.TempDir()
+	ddl := "CREATE TABLE `users` (\n" +
+		"  `id` int(11) NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` varchar(64) NOT NULL DEFAULT '',\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_name` (`name`)\n" +
+		");\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "schema.sql"), []byte(ddl), 0o644))
+	db, err := gorm.Open(rawsql.New(rawsql.Config{FilePath: []string{dir}}), &gorm.Config{Logger: logger.Discard})
+	assert.NoError(t, err)
+
+	schema, err := loadSchema(db, &config.ModelDiffArgument{Type: string(consts.MySQL)})
+	assert.NoError(t, err)
+	users := schema["users"]
+	assert.NotNil(t, users)
+	assert.Equal(t, []string{"id"}, users.primaryKeys())
+	assert.Len(t, users.Indexes, 1)
+
+	up := diffSchemas(nil, schema)
+	assert.Equal(t, []string{
+		"CREATE TABLE `users` (\n  `id` int(11) NOT NULL AUTO_INCREMENT,\n  `name` varchar(64) NOT NULL DEFAULT '',\n  PRIMARY KEY (`id`)\n);",
+		"CREATE INDEX `idx_name` ON `users` (`name`);",
+	}, up)
+}
+
+func TestDiffUnsupportedType(t *testing.T) {
+	err := Diff(&config.ModelDiffArgument{Type: string(consts.Postgres), DSN: "host=localhost", SQLDir: t.TempDir()})
+	assert.ErrorContains(t, err, "not supported")
+}
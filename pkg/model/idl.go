@@ -0,0 +1,182 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/meta"
+	"github.com/hu-1996/cwgo/pkg/consts"
+)
+
+type idlInfo struct {
+	Version   string
+	Namespace string
+	Service   string
+	Tables    []*idlTable
+}
+
+type idlTable struct {
+	Name      string
+	TableName string
+	Comment   string
+	Fields    []*idlField
+	Keys      []*idlField
+}
+
+type idlField struct {
+	Index    int
+	Name     string
+	Type     string
+	Optional bool
+	Tag      string
+	Comment  string
+}
+
+// thrift and proto types of the go types generated by gorm/gen, the others are mapped to string
+var (
+	thriftTypes = map[string]string{
+		"bool":    "bool",
+		"int8":    "byte",
+		"int16":   "i16",
+		"uint8":   "i16",
+		"int":     "i32",
+		"int32":   "i32",
+		"uint16":  "i32",
+		"int64":   "i64",
+		"uint":    "i64",
+		"uint32":  "i64",
+		"uint64":  "i64",
+		"float32": "double",
+		"float64": "double",
+		"[]byte":  "binary",
+		"[]uint8": "binary",
+	}
+	protoTypes = map[string]string{
+		"bool":    "bool",
+		"int8":    "int32",
+		"int16":   "int32",
+		"int":     "int32",
+		"int32":   "int32",
+		"uint8":   "uint32",
+		"uint16":  "uint32",
+		"uint32":  "uint32",
+		"int64":   "int64",
+		"uint":    "uint64",
+		"uint64":  "uint64",
+		"float32": "float",
+		"float64": "double",
+		"[]byte":  "bytes",
+		"[]uint8": "bytes",
+	}
+)
+
+// genIDL generates the thrift or proto file with one struct/message per table,
+// and the CRUD service of the tables if the service name is specified
+func genIDL(c *config.ModelArgument, tables []*TableMeta) error {
+	var (
+		tpl   string
+		types map[string]string
+	)
+	switch c.ToIDL {
+	case consts.Thrift:
+		tpl, types = thriftTemplate, thriftTypes
+	case consts.Proto, consts.Protobuf:
+		tpl, types = protoTemplate, protoTypes
+	default:
+		return fmt.Errorf("unsupported idl type '%s' (support thrift || proto for now)", c.ToIDL)
+	}
+
+	info := &idlInfo{
+		Version:   meta.Version,
+		Namespace: idlNamespace(c),
+		Service:   toCamel(c.Service),
+	}
+	for _, table := range tables {
+		t := &idlTable{
+			Name:      table.StructName,
+			TableName: table.TableName,
+			Comment:   singleLine(table.TableComment),
+		}
+		for i, column := range table.Columns {
+			goType := strings.TrimPrefix(column.GoType, "*")
+			idlType, ok := types[goType]
+			if !ok {
+				idlType = "string"
+			}
+			f := &idlField{
+				Index:    i + 1,
+				Name:     column.ColumnName,
+				Type:     idlType,
+				Optional: strings.HasPrefix(column.GoType, "*"),
+				Tag:      column.Tags,
+				Comment:  singleLine(column.Comment),
+			}
+			t.Fields = append(t.Fields, f)
+			if column.PrimaryKey {
+				t.Keys = append(t.Keys, &idlField{Index: len(t.Keys) + 1, Name: f.Name, Type: f.Type})
+			}
+		}
+		info.Tables = append(info.Tables, t)
+	}
+
+	tmpl, err := template.New(c.ToIDL).Parse(tpl)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, info); err != nil {
+		return fmt.Errorf("render %s idl failed: %w", c.ToIDL, err)
+	}
+
+	outFile := c.IDLOutFile
+	if outFile == "" {
+		ext := consts.Thrift
+		if c.ToIDL != consts.Thrift {
+			ext = consts.Proto
+		}
+		outFile = filepath.Join(consts.DefaultIDLOutDir, info.Namespace+"."+ext)
+	}
+	if err = os.MkdirAll(filepath.Dir(outFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(outFile, buf.Bytes(), 0o644)
+}
+
+// idlNamespace returns the namespace of the idl, which is the package name of the models
+func idlNamespace(c *config.ModelArgument) string {
+	if strings.TrimSpace(c.ModelPkgName) == "" {
+		return "model"
+	}
+	return filepath.Base(c.ModelPkgName)
+}
+
+func toCamel(name string) string {
+	name = strings.Replace(name, "_", " ", -1)
+	name = strings.Title(name)
+	return strings.Replace(name, " ", "", -1)
+}
+
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+const thriftTemplate = `// Code generated by cwgo ({{.Version}}). DO NOT EDIT.
+
+namespace go {{.Namespace}}
+{{range .Tables}}
+// {{.Name}} is the model of table {{.TableName}}{{if .Comment}}, {{.Comment}}{{end}}
+struct {{.Name}} {
+{{- range .Fields}}
+    {{.Index}}: {{if .Optional}}optional {{end}}{{.Type}} {{.Name}}{{if .Tag}} (go.tag = '{{.Tag}}'){{end}}{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+{{- if $.Service}}
+{{- if .Keys}}
+
+struct Get{{.Name}}Req {
+{{- range .Keys}}
+    {{.Index}}: {{.Type}} {{.Name}}
+{{- end}}
+}
+
+struct Delete{{.Name}}Req {
+{{- range .Keys}}
+    {{.Index}}: {{.Type}} {{.Name}}
+{{- end}}
+}
+{{- end}}
+
+struct List{{.Name}}Req {
+    1: i32 page
+    2: i32 page_size
+}
+
+struct List{{.Name}}Resp {
+    1: list<{{.Name}}> items
+    2: i64 total
+}
+{{- end}}
+{{end}}
+{{- if .Service}}
+service {{.Service}} {
+{{- range .Tables}}
+    {{.Name}} Create{{.Name}}(1: {{.Name}} req)
+{{- if .Keys}}
+    {{.Name}} Get{{.Name}}(1: Get{{.Name}}Req req)
+    {{.Name}} Update{{.Name}}(1: {{.Name}} req)
+    void Delete{{.Name}}(1: Delete{{.Name}}Req req)
+{{- end}}
+    List{{.Name}}Resp List{{.Name}}(1: List{{.Name}}Req req)
+{{- end}}
+}
+{{end}}`
+
+const protoTemplate = `// Code generated by cwgo ({{.Version}}). DO NOT EDIT.
+
+syntax = "proto3";
+
+package {{.Namespace}};
+
+option go_package = "{{.Namespace}}";
+
+import "api.proto";
+{{range .Tables}}
+// {{.Name}} is the model of table {{.TableName}}{{if .Comment}}, {{.Comment}}{{end}}
+message {{.Name}} {
+{{- range .Fields}}
+    {{if .Optional}}optional {{end}}{{.Type}} {{.Name}} = {{.Index}}{{if .Tag}} [(api.go_tag) = '{{.Tag}}']{{end}};{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+{{- if $.Service}}
+{{- if .Keys}}
+
+message Get{{.Name}}Req {
+{{- range .Keys}}
+    {{.Type}} {{.Name}} = {{.Index}};
+{{- end}}
+}
+
+message Delete{{.Name}}Req {
+{{- range .Keys}}
+    {{.Type}} {{.Name}} = {{.Index}};
+{{- end}}
+}
+
+message Delete{{.Name}}Resp {}
+{{- end}}
+
+message List{{.Name}}Req {
+    int32 page = 1;
+    int32 page_size = 2;
+}
+
+message List{{.Name}}Resp {
+    repeated {{.Name}} items = 1;
+    int64 total = 2;
+}
+{{- end}}
+{{end}}
+{{- if .Service}}
+service {{.Service}} {
+{{- range .Tables}}
+    rpc Create{{.Name}}({{.Name}}) returns ({{.Name}});
+{{- if .Keys}}
+    rpc Get{{.Name}}(Get{{.Name}}Req) returns ({{.Name}});
+    rpc Update{{.Name}}({{.Name}}) returns ({{.Name}});
+    rpc Delete{{.Name}}(Delete{{.Name}}Req) returns (Delete{{.Name}}Resp);
+{{- end}}
+    rpc List{{.Name}}(List{{.Name}}Req) returns (List{{.Name}}Resp);
+{{- end}}
+}
+{{end}}`
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/cloudwego/hertz/cmd/hz/protobuf/api"
+	"github.com/cloudwego/thriftgo/parser"
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestGenThriftIDL(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "user.thrift")
+	err := genIDL(&config.ModelArgument{ToIDL: consts.Thrift, ModelPkgName: "biz/user", Service: "user_service", IDLOutFile: out}, testTables)
+	assert.NoError(t, err)
+
+	ast, err := parser.ParseFile(out, nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", ast.Namespaces[0].Name)
+
+	user, ok := ast.GetStruct("User")
+	assert.True(t, ok)
+	assert.Len(t, user.Fields, 3)
+	assert.Equal(t, "i64", user.Fields[0].Type.Name)
+	assert.Equal(t, "user_name", user.Fields[1].Name)
+	assert.Equal(t, "string", user.Fields[1].Type.Name)
+	assert.Equal(t, "i32", user.Fields[2].Type.Name)
+	assert.True(t, user.Fields[2].Requiredness.IsOptional())
+	assert.Equal(t, `gorm:"column:id;primaryKey" json:"id"`, user.Fields[0].Annotations.Get("go.tag")[0])
+
+	_, ok = ast.GetStruct("GetUserReq")
+	assert.True(t, ok)
+
+	svc, ok := ast.GetService("UserService")
+	assert.True(t, ok)
+	var methods []string
+	for _, m := range svc.Functions {
+		methods = append(methods, m.Name)
+	}
+	assert.Equal(t, []string{"CreateUser", "GetUser", "UpdateUser", "DeleteUser", "ListUser"}, methods)
+}
+
+func TestGenProtoIDL(t *testing.T) {
+	dir := t.TempDir()
+	err := genIDL(&config.ModelArgument{ToIDL: consts.Proto, IDLOutFile: filepath.Join(dir, "model.proto")}, testTables)
+	assert.NoError(t, err)
+
+	p := protoparse.Parser{ImportPaths: []string{dir}, LookupImport: desc.LoadFileDescriptor}
+	fds, err := p.ParseFiles("model.proto")
+	assert.NoError(t, err)
+	fd := fds[0]
+	assert.Equal(t, "model", fd.GetPackage())
+	assert.Empty(t, fd.GetServices())
+
+	user := fd.FindMessage("model.User")
+	assert.NotNil(t, user)
+	assert.Len(t, user.GetFields(), 3)
+	assert.Equal(t, descriptorpb.FieldDescriptorProto_TYPE_INT64, user.FindFieldByName("id").GetType())
+	assert.True(t, user.FindFieldByName("age").IsProto3Optional())
+	assert.Nil(t, fd.FindMessage("model.GetUserReq"))
+}
+
+func TestGenIDLUnknownType(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "model.graphql")
+	err := genIDL(&config.ModelArgument{ToIDL: "graphql", IDLOutFile: out}, testTables)
+	assert.Error(t, err)
+	_, err = os.Stat(out)
+	assert.True(t, os.IsNotExist(err))
+}
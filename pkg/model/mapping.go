@@ -0,0 +1,306 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+// JSON tag naming strategies
+const (
+	jsonSnake      = "snake"
+	jsonCamel      = "camel"
+	jsonLowerCamel = "lower_camel"
+)
+
+// modelConfig is the mapping config of cwgo model, eg:
+//
+//	data_type_map:
+//	  decimal: github.com/shopspring/decimal.Decimal
+//	  json: gorm.io/datatypes.JSON
+//	  tinyint(1): bool
+//	json_tag_strategy: lower_camel
+//	tables:
+//	  users:
+//	    model_name: Account
+//	    columns:
+//	      email:
+//	        name: EmailAddress
+//	        tags:
+//	          validate: email
+//	relations:
+//	  enable: true
+//	  naming: true
+//	data_sources:
+//	  - name: user
+//	    dsn: gorm:gorm@tcp(localhost:3306)/user
+type modelConfig struct {
+	// DataTypeMap maps the db type to go type, the type with length like tinyint(1) takes precedence
+	DataTypeMap     map[string]string       `yaml:"data_type_map"`
+	JSONTagStrategy string                  `yaml:"json_tag_strategy"`
+	Tables          map[string]*tableConfig `yaml:"tables"`
+	Relations       *relationConfig         `yaml:"relations"`
+	DataSources     []*dataSourceConfig     `yaml:"data_sources"`
+}
+
+type tableConfig struct {
+	ModelName string                   `yaml:"model_name"`
+	Columns   map[string]*columnConfig `yaml:"columns"`
+}
+
+type columnConfig struct {
+	Type   string            `yaml:"type"`
+	Name   string            `yaml:"name"`
+	JSON   string            `yaml:"json"`
+	Tags   map[string]string `yaml:"tags"`
+	Ignore bool              `yaml:"ignore"`
+}
+
+func loadModelConfig(path string) (*modelConfig, error) {
+	mc := &modelConfig{}
+	if path == "" {
+		return mc, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(data, mc); err != nil {
+		return nil, fmt.Errorf("parse model config '%s' failed: %w", path, err)
+	}
+	switch mc.JSONTagStrategy {
+	case "", jsonSnake, jsonCamel, jsonLowerCamel:
+	default:
+		return nil, fmt.Errorf("unknown json tag strategy '%s' (support snake || camel || lower_camel for now)", mc.JSONTagStrategy)
+	}
+	if err = checkDataSources(mc.DataSources); err != nil {
+		return nil, err
+	}
+	return mc, nil
+}
+
+// goType splits the go type into the import path and the type used in code,
+// eg: "github.com/shopspring/decimal.Decimal" -> "github.com/shopspring/decimal", "decimal.Decimal"
+func goType(t string) (importPath, typ string) {
+	prefix := ""
+	for strings.HasPrefix(t, "*") || strings.HasPrefix(t, "[]") {
+		n := 1
+		if t[0] == '[' {
+			n = 2
+		}
+		prefix, t = prefix+t[:n], t[n:]
+	}
+	slash := strings.LastIndex(t, "/")
+	if slash < 0 {
+		return "", prefix + t
+	}
+	dot := strings.LastIndex(t, ".")
+	if dot < slash {
+		return "", prefix + t
+	}
+	return t[:dot], prefix + t[strings.LastIndex(t[:dot], "/")+1:]
+}
+
+// apply sets the data type mapping and json tag strategy to the gen config
+func (mc *modelConfig) apply(genConfig *gen.Config) {
+	var imports []string
+	addImport := func(t string) string {
+		path, typ := goType(t)
+		if path != "" {
+			imports = append(imports, path)
+		}
+		return typ
+	}
+
+	if len(mc.DataTypeMap) > 0 {
+		// group the types by the db type name which gorm/gen looks up the mapping with
+		types := make(map[string]map[string]string)
+		for dbType, t := range mc.DataTypeMap {
+			dbType = strings.ToLower(strings.TrimSpace(dbType))
+			base := dbType
+			if i := strings.Index(base, "("); i > 0 {
+				base = base[:i]
+			}
+			if types[base] == nil {
+				types[base] = make(map[string]string)
+			}
+			types[base][dbType] = addImport(t)
+		}
+
+		dataTypeMap := make(map[string]func(columnType gorm.ColumnType) string)
+		for base, mapping := range types {
+			f := dataTypeMapping(base, mapping)
+			dataTypeMap[base] = f
+			dataTypeMap[strings.ToUpper(base)] = f
+		}
+		genConfig.WithDataTypeMap(dataTypeMap)
+	}
+
+	// the config is left untouched, the types of the columns are resolved by modelOpts
+	for _, table := range mc.Tables {
+		for _, column := range table.Columns {
+			if column.Type != "" {
+				addImport(column.Type)
+			}
+		}
+	}
+	if len(imports) > 0 {
+		genConfig.WithImportPkgPath(imports...)
+	}
+
+	if ns := jsonTagNS(mc.JSONTagStrategy); ns != nil {
+		genConfig.WithJSONTagNameStrategy(ns)
+	}
+}
+
+func dataTypeMapping(base string, mapping map[string]string) func(columnType gorm.ColumnType) string {
+	detailTypes := make([]string, 0, len(mapping))
+	for dbType := range mapping {
+		if dbType != base {
+			detailTypes = append(detailTypes, dbType)
+		}
+	}
+	// the longer type is more specific
+	sort.Slice(detailTypes, func(i, j int) bool { return len(detailTypes[i]) > len(detailTypes[j]) })
+
+	return func(columnType gorm.ColumnType) string {
+		detail, _ := columnType.ColumnType()
+		detail = strings.ToLower(strings.TrimSpace(detail))
+		for _, dbType := range detailTypes {
+			if strings.HasPrefix(detail, dbType) {
+				return mapping[dbType]
+			}
+		}
+		if t, ok := mapping[base]; ok {
+			return t
+		}
+		return defaultDataType(base, detail)
+	}
+}
+
+// defaultDataType mirrors the default mapping of gorm/gen, which is used when only
+// the types with length are configured, eg: tinyint(1)
+func defaultDataType(base, detail string) string {
+	switch base {
+	case "numeric", "integer", "int", "smallint", "mediumint", "year":
+		return "int32"
+	case "bigint":
+		return "int64"
+	case "float":
+		return "float32"
+	case "real", "double", "decimal":
+		return "float64"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "[]byte"
+	case "time", "date", "datetime", "timestamp":
+		return "time.Time"
+	case "bit":
+		return "[]uint8"
+	case "boolean":
+		return "bool"
+	case "tinyint":
+		if strings.HasPrefix(detail, "tinyint(1)") {
+			return "bool"
+		}
+		return "int32"
+	}
+	return "string"
+}
+
+func jsonTagNS(strategy string) func(columnName string) string {
+	switch strategy {
+	case jsonCamel:
+		return toCamel
+	case jsonLowerCamel:
+		return func(columnName string) string {
+			name := toCamel(columnName)
+			if name == "" {
+				return name
+			}
+			return strings.ToLower(name[:1]) + name[1:]
+		}
+	}
+	return nil
+}
+
+// modelOpts returns the model name and the field options of the table
+func (mc *modelConfig) modelOpts(tableName string) (modelName string, opts []gen.ModelOpt) {
+	table, ok := mc.Tables[tableName]
+	if !ok {
+		return "", nil
+	}
+
+	columnNames := make([]string, 0, len(table.Columns))
+	for name := range table.Columns {
+		columnNames = append(columnNames, name)
+	}
+	sort.Strings(columnNames)
+
+	for _, name := range columnNames {
+		column := table.Columns[name]
+		if column.Ignore {
+			opts = append(opts, gen.FieldIgnore(name))
+			continue
+		}
+		if column.Type != "" {
+			_, typ := goType(column.Type)
+			opts = append(opts, gen.FieldType(name, typ))
+		}
+		if column.Name != "" {
+			opts = append(opts, gen.FieldRename(name, column.Name))
+		}
+		if column.JSON != "" {
+			opts = append(opts, gen.FieldJSONTag(name, column.JSON))
+		}
+		if len(column.Tags) > 0 {
+			tags := column.Tags
+			opts = append(opts, gen.FieldTag(name, func(tag field.Tag) field.Tag {
+				for k, v := range tags {
+					tag.Set(k, v)
+				}
+				return tag
+			}))
+		}
+	}
+	return table.ModelName, opts
+}
+
+// modelName returns the model struct name of the table
+func (mc *modelConfig) modelName(db *gorm.DB, tableName string) string {
+	if table, ok := mc.Tables[tableName]; ok && table.ModelName != "" {
+		return table.ModelName
+	}
+	return db.NamingStrategy.SchemaName(tableName)
+}
+
+// fieldName returns the field name of the column in the model struct
+func (mc *modelConfig) fieldName(db *gorm.DB, tableName, columnName string) string {
+	if table, ok := mc.Tables[tableName]; ok {
+		if column, ok := table.Columns[columnName]; ok && column.Name != "" {
+			return column.Name
+		}
+	}
+	return db.NamingStrategy.SchemaName(columnName)
+}
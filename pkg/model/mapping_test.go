@@ -0,0 +1,152 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gen"
+	"gorm.io/gorm/migrator"
+)
+
+func TestGoType(t *testing.T) {
+	for _, tc := range []struct {
+		in, importPath, typ string
+	}{
+		{"bool", "", "bool"},
+		{"time.Time", "", "time.Time"},
+		{"github.com/shopspring/decimal.Decimal", "github.com/shopspring/decimal", "decimal.Decimal"},
+		{"*gorm.io/datatypes.JSON", "gorm.io/datatypes", "*datatypes.JSON"},
+		{"[]*example.com/pkg/types.ID", "example.com/pkg/types", "[]*types.ID"},
+		{"example.com/pkg", "", "example.com/pkg"},
+	} {
+		importPath, typ := goType(tc.in)
+		assert.Equal(t, tc.importPath, importPath, tc.in)
+		assert.Equal(t, tc.typ, typ, tc.in)
+	}
+}
+
+func TestDataTypeMapping(t *testing.T) {
+	mapping := dataTypeMapping("tinyint", map[string]string{"tinyint(1)": "bool"})
+	column := func(detail string) migrator.ColumnType {
+		return migrator.ColumnType{ColumnTypeValue: sql.NullString{String: detail, Valid: true}}
+	}
+	assert.Equal(t, "bool", mapping(column("tinyint(1)")))
+	// the default mapping of gorm/gen is kept for the other lengths
+	assert.Equal(t, "int32", mapping(column("tinyint(4)")))
+
+	mapping = dataTypeMapping("decimal", map[string]string{"decimal": "decimal.Decimal", "decimal(10,2)": "float64"})
+	assert.Equal(t, "float64", mapping(column("DECIMAL(10,2)")))
+	assert.Equal(t, "decimal.Decimal", mapping(column("decimal(20,6)")))
+}
+
+func TestJSONTagNS(t *testing.T) {
+	assert.Nil(t, jsonTagNS(""))
+	assert.Nil(t, jsonTagNS(jsonSnake))
+	assert.Equal(t, "UserName", jsonTagNS(jsonCamel)("user_name"))
+	assert.Equal(t, "userName", jsonTagNS(jsonLowerCamel)("user_name"))
+}
+
+func TestLoadModelConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("json_tag_strategy: kebab\n"), 0o644))
+	_, err := loadModelConfig(path)
+	assert.ErrorContains(t, err, "kebab")
+
+	mc, err := loadModelConfig("")
+	assert.NoError(t, err)
+	assert.NotNil(t, mc)
+}
+
+func TestModelConfigApply(t *testing.T) {
+	mc := &modelConfig{
+		DataTypeMap: map[string]string{
+			"decimal":    "github.com/shopspring/decimal.Decimal",
+			"tinyint(1)": "bool",
+		},
+		Tables: map[string]*tableConfig{
+			"users": {
+				ModelName: "Account",
+				Columns: map[string]*columnConfig{
+					"extra":  {Type: "gorm.io/datatypes.JSON"},
+					"secret": {Ignore: true},
+				},
+			},
+		},
+	}
+
+	// the config is applied once per data source, it must be left untouched
+	for i := 0; i < 2; i++ {
+		mc.apply(&gen.Config{})
+		assert.Equal(t, "gorm.io/datatypes.JSON", mc.Tables["users"].Columns["extra"].Type)
+		assert.Equal(t, "github.com/shopspring/decimal.Decimal", mc.DataTypeMap["decimal"])
+	}
+
+	modelName, opts := mc.modelOpts("users")
+	assert.Equal(t, "Account", modelName)
+	assert.Len(t, opts, 2)
+	modelName, opts = mc.modelOpts("orders")
+	assert.Empty(t, modelName)
+	assert.Empty(t, opts)
+}
+
+func TestGenModelWithMapping(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, price DECIMAL(10,2), extra TEXT, secret TEXT, user_name VARCHAR(64))")
+	mc := &modelConfig{
+		DataTypeMap:     map[string]string{"decimal": "github.com/shopspring/decimal.Decimal"},
+		JSONTagStrategy: jsonLowerCamel,
+		Tables: map[string]*tableConfig{
+			"users": {
+				ModelName: "Account",
+				Columns: map[string]*columnConfig{
+					"extra":     {Type: "gorm.io/datatypes.JSON", Tags: map[string]string{"validate": "required"}},
+					"secret":    {Ignore: true},
+					"user_name": {Name: "Login", JSON: "login"},
+				},
+			},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		out := filepath.Join(t.TempDir(), "query")
+		genConfig := gen.Config{OutPath: out, ModelPkgPath: "model"}
+		mc.apply(&genConfig)
+		g := gen.NewGenerator(genConfig)
+		g.UseDB(db)
+		modelName, opts := mc.modelOpts("users")
+		g.GenerateModelAs("users", modelName, opts...)
+		g.Execute()
+
+		code, err := os.ReadFile(filepath.Join(filepath.Dir(out), "model", "users.gen.go"))
+		assert.NoError(t, err)
+		model := strings.Join(strings.Fields(string(code)), " ")
+		assert.Contains(t, model, `"github.com/shopspring/decimal"`)
+		assert.Contains(t, model, `"gorm.io/datatypes"`)
+		assert.Contains(t, model, "type Account struct")
+		assert.Contains(t, model, "Price decimal.Decimal")
+		assert.Contains(t, model, "Extra datatypes.JSON")
+		assert.Contains(t, model, `validate:"required"`)
+		assert.Contains(t, model, `Login string `+"`"+`gorm:"column:user_name" json:"login"`)
+		assert.Contains(t, model, `json:"id"`)
+		assert.NotContains(t, model, "Secret")
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/hu-1996/cwgo/pkg/consts"
 
 	"gorm.io/gen"
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
 )
 
@@ -35,6 +36,22 @@ var (
 )
 
 func Model(c *config.ModelArgument) error {
+	if c.OnlyModel && c.QueryDir != "" {
+		return fmt.Errorf("the custom query methods of '%s' need the query code, please remove --%s", c.QueryDir, consts.OnlyModel)
+	}
+
+	mc, err := loadModelConfig(c.ModelConfig)
+	if err != nil {
+		return err
+	}
+	if len(mc.DataSources) > 0 {
+		return genDataSources(c, mc)
+	}
+	return genModel(c, mc)
+}
+
+// genModel generates the model and query code of a database
+func genModel(c *config.ModelArgument, mc *modelConfig) error {
 	dialector := config.OpenTypeFuncMap[consts.DataBaseType(c.Type)]
 
 	if c.SQLDir != "" {
@@ -48,16 +65,9 @@ func Model(c *config.ModelArgument) error {
 		return err
 	}
 
-	genConfig := gen.Config{
-		OutPath:           c.OutPath,
-		OutFile:           c.OutFile,
-		ModelPkgPath:      c.ModelPkgName,
-		WithUnitTest:      c.WithUnitTest,
-		FieldNullable:     c.FieldNullable,
-		FieldSignable:     c.FieldSignable,
-		FieldWithIndexTag: c.FieldWithIndexTag,
-		FieldWithTypeTag:  c.FieldWithTypeTag,
-	}
+	genConfig := newGenConfig(c)
+
+	mc.apply(&genConfig)
 
 	if len(c.ExcludeTables) > 0 || c.Type == string(consts.Sqlite) {
 		genConfig.WithTableNameStrategy(func(tableName string) (targetTableName string) {
@@ -79,33 +89,101 @@ func Model(c *config.ModelArgument) error {
 
 	g.UseDB(db)
 
-	models, err := genModels(g, db, c.Tables)
+	models, err := genModels(g, db, c, mc)
 	if err != nil {
 		return err
 	}
 
+	if c.ToIDL != "" {
+		tables, err := tableMetas(c, db, models)
+		if err != nil {
+			return err
+		}
+		return genIDL(c, tables)
+	}
+
 	if !c.OnlyModel {
 		g.ApplyBasic(models...)
 	}
 
 	g.Execute()
+
+	if c.Template == "" && c.QueryDir == "" {
+		return nil
+	}
+	tables, err := tableMetas(c, db, models)
+	if err != nil {
+		return err
+	}
+	if c.QueryDir != "" {
+		if err = genQueries(c, tables); err != nil {
+			return err
+		}
+	}
+	if c.Template != "" {
+		if err = renderTemplates(c, tables); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func genModels(g *gen.Generator, db *gorm.DB, tables []string) (models []interface{}, err error) {
+// newGenConfig returns the config of gorm/gen from the arguments, the query generator is rendered with it too
+func newGenConfig(c *config.ModelArgument) gen.Config {
+	return gen.Config{
+		OutPath:           c.OutPath,
+		OutFile:           c.OutFile,
+		ModelPkgPath:      c.ModelPkgName,
+		WithUnitTest:      c.WithUnitTest,
+		FieldNullable:     c.FieldNullable,
+		FieldSignable:     c.FieldSignable,
+		FieldWithIndexTag: c.FieldWithIndexTag,
+		FieldWithTypeTag:  c.FieldWithTypeTag,
+	}
+}
+
+func genModels(g *gen.Generator, db *gorm.DB, c *config.ModelArgument, mc *modelConfig) (models []interface{}, err error) {
 	var tablesNameList []string
-	if len(tables) == 0 {
+	if len(c.Tables) == 0 {
 		tablesNameList, err = db.Migrator().GetTables()
 		if err != nil {
 			return nil, fmt.Errorf("migrator get all tables fail: %w", err)
 		}
 	} else {
-		tablesNameList = tables
+		tablesNameList = c.Tables
 	}
 
 	models = make([]interface{}, len(tablesNameList))
+	relates := make(map[string]relateFunc, len(tablesNameList))
+	addFields := make(map[string]func(fields []gen.Field), len(tablesNameList))
 	for i, tableName := range tablesNameList {
-		models[i] = g.GenerateModel(tableName)
+		modelName, opts := mc.modelOpts(tableName)
+		if modelName == "" {
+			modelName = db.NamingStrategy.SchemaName(tableName)
+		}
+
+		meta := g.GenerateModelAs(tableName, modelName, opts...)
+		models[i] = meta
+		if meta == nil {
+			// the table is excluded by the table name strategy
+			continue
+		}
+		relates[tableName] = func(relationship field.RelationshipType, fieldName string, config *field.RelateConfig) gen.Field {
+			return gen.FieldRelate(relationship, fieldName, meta, config)(nil)
+		}
+		addFields[tableName] = func(fields []gen.Field) {
+			meta.Fields = append(meta.Fields, fields...)
+		}
+	}
+
+	// the relation fields are created before appending any of them,
+	// so the related models have no nested relations
+	relations, err := relationFields(db, c.Type, c.SQLDir == "", mc, tablesNameList, relates)
+	if err != nil {
+		return nil, err
+	}
+	for tableName, fields := range relations {
+		addFields[tableName](fields)
 	}
 	return models, nil
 }
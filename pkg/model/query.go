@@ -0,0 +1,543 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/meta"
+	"golang.org/x/tools/imports"
+	"gorm.io/gen"
+)
+
+const (
+	querierSuffix   = "Querier"
+	tableDirective  = "table:"
+	sqlNameHeader   = "-- name:"
+	sqlTableHeader  = "-- table:"
+	sqlParamHeader  = "-- param:"
+	sqlCommentStart = "--"
+
+	queryGeneratorDir = "cwgo_query_gen_"
+	sqlQueryPkgName   = "sqlquery"
+	sqlQueryFileName  = "querier.go"
+)
+
+var sqlParamRegexp = regexp.MustCompile(`@(\w+)`)
+
+// queryInterface is the go interface of the custom query methods, which is applied to the tables by gorm/gen
+type queryInterface struct {
+	PkgPath string   // import path of the package of the interface
+	PkgName string   // package name of the interface
+	Name    string   // interface name
+	Tables  []string // the tables the interface applies to, empty means all the tables
+}
+
+type queryParam struct {
+	Name string
+	Type string
+}
+
+// sqlQueryMethod is the query method described by the sql file
+type sqlQueryMethod struct {
+	Tables []string
+	Name   string
+	Doc    []string
+	SQL    []string
+	Args   []queryParam
+	Result string // the results of the method, eg: (*gen.T, error)
+}
+
+type sqlQueryFile struct {
+	Version    string
+	Interfaces []*sqlQueryInterface
+}
+
+type sqlQueryInterface struct {
+	Name    string
+	File    string
+	Methods []*sqlQueryMethod
+}
+
+type queryGenerator struct {
+	Version     string
+	Config      gen.Config // the config of the main generation with the absolute paths
+	Imports     []queryImport
+	BasicModels []string
+	Applies     []*queryApply
+}
+
+type queryImport struct {
+	Alias string
+	Path  string
+}
+
+type queryApply struct {
+	Interface string // the interface with package alias, eg: query0.UserQuerier
+	Models    []string
+}
+
+// genQueries applies the custom query methods in the query_dir to the tables by the ApplyInterface of gorm/gen,
+// so the annotation syntax of gorm/gen, eg: {{if}}, {{where}} and {{set}}, is supported. The methods are
+// described by the go interfaces in the style of gorm/gen, eg:
+//
+//	type UserQuerier interface {
+//		// SELECT * FROM @@table WHERE id = @id
+//		GetByID(id int64) (gen.T, error)
+//	}
+//
+// or the sql files with name headers, which are converted to the go interfaces, eg:
+//
+//	-- name: GetByEmail :one
+//	-- param: email string
+//	SELECT * FROM @@table WHERE email = @email;
+//
+// ApplyInterface reads the interfaces from the compiled types, so a generator program importing the
+// interfaces and the models is written into the go module of the query code and run by "go run".
+func genQueries(c *config.ModelArgument, tables []*TableMeta) error {
+	if len(tables) == 0 {
+		return nil
+	}
+	ifaces, sqlFile, err := loadQueryInterfaces(c.QueryDir, tables)
+	if err != nil {
+		return err
+	}
+	if len(ifaces) == 0 && len(sqlFile.Interfaces) == 0 {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp(c.QueryDir, queryGeneratorDir)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if len(sqlFile.Interfaces) > 0 {
+		sqlDir := filepath.Join(dir, sqlQueryPkgName)
+		pkgPath, err := importPath(sqlDir)
+		if err != nil {
+			return err
+		}
+		if err = writeSQLQueryFile(filepath.Join(sqlDir, sqlQueryFileName), sqlFile); err != nil {
+			return err
+		}
+		for _, iface := range sqlFile.Interfaces {
+			ifaces = append(ifaces, &queryInterface{
+				PkgPath: pkgPath,
+				PkgName: sqlQueryPkgName,
+				Name:    iface.Name,
+				Tables:  iface.Methods[0].Tables,
+			})
+		}
+	}
+
+	modelImport, err := modelImportPath(c.OutPath, tables)
+	if err != nil {
+		return err
+	}
+	if modelImport == "" {
+		return fmt.Errorf("the import path of the models is not found in the query code of '%s'", c.OutPath)
+	}
+	generator, err := newQueryGenerator(c, modelImport, tables, ifaces)
+	if err != nil {
+		return err
+	}
+	code, err := renderQueryGenerator(generator)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(dir, "main.go"), code, 0o644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("run the query generator in '%s' failed, gorm.io/gen should be required by the go.mod: %w", c.QueryDir, err)
+	}
+	return nil
+}
+
+// newQueryGenerator applies the interfaces to the models of their tables, the other models only get the basic query
+func newQueryGenerator(c *config.ModelArgument, modelImport string, tables []*TableMeta, ifaces []*queryInterface) (*queryGenerator, error) {
+	absPath := func(p string) (string, error) {
+		if p == "" || filepath.IsAbs(p) || !strings.ContainsRune(p, os.PathSeparator) && !strings.ContainsRune(p, '/') {
+			return p, nil
+		}
+		return filepath.Abs(p)
+	}
+	outPath, err := filepath.Abs(c.OutPath)
+	if err != nil {
+		return nil, err
+	}
+	outFile, err := absPath(c.OutFile)
+	if err != nil {
+		return nil, err
+	}
+	modelPkgPath, err := absPath(c.ModelPkgName)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &queryGenerator{
+		Version: meta.Version,
+		Config:  newGenConfig(c),
+		Imports: []queryImport{{Alias: tables[0].ModelPkgName, Path: modelImport}},
+	}
+	g.Config.OutPath, g.Config.OutFile, g.Config.ModelPkgPath = outPath, outFile, modelPkgPath
+	aliases := make(map[string]string)
+	applied := make(map[string]bool)
+	for _, iface := range ifaces {
+		alias, ok := aliases[iface.PkgPath]
+		if !ok {
+			alias = fmt.Sprintf("%s%d", iface.PkgName, len(aliases))
+			aliases[iface.PkgPath] = alias
+			g.Imports = append(g.Imports, queryImport{Alias: alias, Path: iface.PkgPath})
+		}
+
+		apply := &queryApply{Interface: alias + "." + iface.Name}
+		for _, table := range tables {
+			if appliesTo(iface.Tables, table.TableName) {
+				apply.Models = append(apply.Models, table.ModelPkgName+"."+table.StructName)
+				applied[table.TableName] = true
+			}
+		}
+		if len(apply.Models) > 0 {
+			g.Applies = append(g.Applies, apply)
+		}
+	}
+	for _, table := range tables {
+		if !applied[table.TableName] {
+			g.BasicModels = append(g.BasicModels, table.ModelPkgName+"."+table.StructName)
+		}
+	}
+	return g, nil
+}
+
+func renderQueryGenerator(g *queryGenerator) ([]byte, error) {
+	tmpl, err := template.New("query_generator").Parse(queryGeneratorTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, g); err != nil {
+		return nil, fmt.Errorf("render query generator failed: %w", err)
+	}
+	code, err := imports.Process("main.go", buf.Bytes(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("format query generator failed: %w", err)
+	}
+	return code, nil
+}
+
+func appliesTo(tables []string, table string) bool {
+	if len(tables) == 0 {
+		return true
+	}
+	for _, t := range tables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// loadQueryInterfaces loads the go interfaces and the methods of the sql files in the query dir
+func loadQueryInterfaces(dir string, tables []*TableMeta) ([]*queryInterface, *sqlQueryFile, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(files)
+
+	var ifaces []*queryInterface
+	sqlFile := &sqlQueryFile{Version: meta.Version}
+	for _, file := range files {
+		switch {
+		case strings.HasSuffix(file, "_test.go"):
+		case filepath.Ext(file) == ".go":
+			is, err := parseQueryInterfaces(file, tables)
+			if err != nil {
+				return nil, nil, err
+			}
+			ifaces = append(ifaces, is...)
+		case filepath.Ext(file) == ".sql":
+			methods, err := parseQuerySQL(file)
+			if err != nil {
+				return nil, nil, err
+			}
+			sqlFile.Interfaces = append(sqlFile.Interfaces, sqlQueryInterfaces(file, methods)...)
+		}
+	}
+	if len(ifaces) > 0 {
+		pkgPath, err := importPath(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, iface := range ifaces {
+			iface.PkgPath = pkgPath
+		}
+	}
+	return ifaces, sqlFile, nil
+}
+
+// parseQueryInterfaces parses the go interfaces, the interface named <Model>Querier applies to the table
+// of the model, the interface with the "table: a, b" comment applies to the tables, the others apply to all
+func parseQueryInterfaces(file string, tables []*TableMeta) ([]*queryInterface, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse query file '%s' failed: %w", file, err)
+	}
+
+	var ifaces []*queryInterface
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, ok := ts.Type.(*ast.InterfaceType); !ok || !ts.Name.IsExported() {
+				continue
+			}
+			doc := ts.Doc
+			if doc == nil {
+				doc = gd.Doc
+			}
+			ifaces = append(ifaces, &queryInterface{
+				PkgName: f.Name.Name,
+				Name:    ts.Name.Name,
+				Tables:  interfaceTables(ts.Name.Name, doc, tables),
+			})
+		}
+	}
+	return ifaces, nil
+}
+
+func interfaceTables(name string, doc *ast.CommentGroup, tables []*TableMeta) []string {
+	if doc != nil {
+		for _, line := range strings.Split(doc.Text(), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), tableDirective) {
+				return splitList(strings.TrimPrefix(strings.TrimSpace(line), tableDirective))
+			}
+		}
+	}
+	modelName := strings.TrimSuffix(name, querierSuffix)
+	for _, table := range tables {
+		if modelName != name && table.StructName == modelName {
+			return []string{table.TableName}
+		}
+	}
+	return nil
+}
+
+// parseQuerySQL parses the sql file, the methods apply to the table named as the file unless the table header is specified.
+// The kind of the method is one of :one, :many, :exec and :execrows. The params used in the sql without the param header
+// are typed as interface{}.
+func parseQuerySQL(file string) ([]*sqlQueryMethod, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	defaultTables := []string{strings.TrimSuffix(filepath.Base(file), ".sql")}
+
+	var (
+		methods []*sqlQueryMethod
+		current *sqlQueryMethod
+	)
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		if len(current.SQL) == 0 {
+			return fmt.Errorf("the sql of method '%s' in '%s' is empty", current.Name, file)
+		}
+		last := len(current.SQL) - 1
+		current.SQL[last] = strings.TrimSuffix(current.SQL[last], ";")
+		current.declareParams()
+		methods = append(methods, current)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, sqlNameHeader):
+			if err = finish(); err != nil {
+				return nil, err
+			}
+			fields := strings.Fields(strings.TrimPrefix(line, sqlNameHeader))
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("invalid header '%s' in '%s', should be '-- name: Method :one'", line, file)
+			}
+			if !token.IsIdentifier(fields[0]) || !token.IsExported(fields[0]) {
+				return nil, fmt.Errorf("invalid method name '%s' in '%s', it should be an exported go identifier", fields[0], file)
+			}
+			current = &sqlQueryMethod{Name: fields[0], Tables: defaultTables}
+			switch fields[1] {
+			case ":one":
+				current.Result = "(*gen.T, error)"
+			case ":many":
+				current.Result = "([]*gen.T, error)"
+			case ":exec":
+				current.Result = "error"
+			case ":execrows":
+				current.Result = "(gen.RowsAffected, error)"
+			default:
+				return nil, fmt.Errorf("unknown kind '%s' of method '%s' in '%s' (support :one || :many || :exec || :execrows for now)", fields[1], fields[0], file)
+			}
+		case current == nil:
+		case strings.HasPrefix(line, sqlTableHeader):
+			current.Tables = splitList(strings.TrimPrefix(line, sqlTableHeader))
+		case strings.HasPrefix(line, sqlParamHeader):
+			fields := strings.Fields(strings.TrimPrefix(line, sqlParamHeader))
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("invalid header '%s' in '%s', should be '-- param: name type'", line, file)
+			}
+			current.Args = append(current.Args, queryParam{Name: fields[0], Type: strings.Join(fields[1:], " ")})
+		case strings.HasPrefix(line, sqlCommentStart):
+			if doc := strings.TrimSpace(strings.TrimPrefix(line, sqlCommentStart)); doc != "" {
+				current.Doc = append(current.Doc, doc)
+			}
+		case line != "":
+			current.SQL = append(current.SQL, line)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err = finish(); err != nil {
+		return nil, err
+	}
+	return methods, nil
+}
+
+// declareParams declares the params used in the sql without the param header as interface{}
+func (m *sqlQueryMethod) declareParams() {
+	declared := make(map[string]bool, len(m.Args))
+	for _, arg := range m.Args {
+		declared[arg.Name] = true
+	}
+	sql := strings.ReplaceAll(strings.Join(m.SQL, "\n"), "@@", "")
+	for _, match := range sqlParamRegexp.FindAllStringSubmatch(sql, -1) {
+		if name := match[1]; !declared[name] {
+			declared[name] = true
+			m.Args = append(m.Args, queryParam{Name: name, Type: "interface{}"})
+		}
+	}
+}
+
+// ParamList returns the params of the method, eg: id int64, name string
+func (m *sqlQueryMethod) ParamList() string {
+	params := make([]string, 0, len(m.Args))
+	for _, arg := range m.Args {
+		params = append(params, arg.Name+" "+arg.Type)
+	}
+	return strings.Join(params, ", ")
+}
+
+// sqlQueryInterfaces groups the methods of the sql file by their tables, each group is an interface
+func sqlQueryInterfaces(file string, methods []*sqlQueryMethod) []*sqlQueryInterface {
+	name := toCamel(strings.NewReplacer("-", "_", ".", "_").Replace(strings.TrimSuffix(filepath.Base(file), ".sql"))) + querierSuffix
+	var ifaces []*sqlQueryInterface
+	groups := make(map[string]*sqlQueryInterface)
+	for _, m := range methods {
+		key := strings.Join(m.Tables, ",")
+		iface, ok := groups[key]
+		if !ok {
+			iface = &sqlQueryInterface{Name: name, File: filepath.Base(file)}
+			if len(ifaces) > 0 {
+				iface.Name = fmt.Sprintf("%s%d", name, len(ifaces)+1)
+			}
+			groups[key] = iface
+			ifaces = append(ifaces, iface)
+		}
+		iface.Methods = append(iface.Methods, m)
+	}
+	return ifaces
+}
+
+// writeSQLQueryFile writes the go interfaces converted from the sql files
+func writeSQLQueryFile(file string, f *sqlQueryFile) error {
+	tmpl, err := template.New("sql_query").Parse(sqlQueryTemplate)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, f); err != nil {
+		return fmt.Errorf("render the interfaces of the sql files failed: %w", err)
+	}
+	code, err := imports.Process(file, buf.Bytes(), nil)
+	if err != nil {
+		return fmt.Errorf("format the interfaces of the sql files failed: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, code, 0o644)
+}
+
+// modelImportPath finds the import path of the model package in the query code generated by gorm/gen
+func modelImportPath(outPath string, tables []*TableMeta) (string, error) {
+	if len(tables) == 0 {
+		return "", nil
+	}
+	files, err := filepath.Glob(filepath.Join(outPath, "*.gen.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range f.Imports {
+			p, _ := strconv.Unquote(imp.Path.Value)
+			if path.Base(p) == tables[0].ModelPkgName {
+				return p, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+// queryGeneratorTemplate is the program applying the query interfaces to the models by gorm/gen
+const queryGeneratorTemplate = `// Code generated by cwgo ({{.Version}}). DO NOT EDIT.
+
+package main
+
+import (
+	"gorm.io/gen"
+{{- range .Imports}}
+	{{.Alias}} "{{.Path}}"
+{{- end}}
+)
+
+func main() {
+	g := gen.NewGenerator(gen.Config{
+{{- with .Config}}
+		OutPath:           {{printf "%q" .OutPath}},
+		OutFile:           {{printf "%q" .OutFile}},
+		ModelPkgPath:      {{printf "%q" .ModelPkgPath}},
+		WithUnitTest:      {{.WithUnitTest}},
+		FieldNullable:     {{.FieldNullable}},
+		FieldCoverable:    {{.FieldCoverable}},
+		FieldSignable:     {{.FieldSignable}},
+		FieldWithIndexTag: {{.FieldWithIndexTag}},
+		FieldWithTypeTag:  {{.FieldWithTypeTag}},
+		Mode:              {{printf "%d" .Mode}},
+{{- end}}
+	})
+{{- if .BasicModels}}
+
+	g.ApplyBasic({{range .BasicModels}}{{.}}{}, {{end}})
+{{- end}}
+{{- range .Applies}}
+
+	g.ApplyInterface(func({{.Interface}}) {}, {{range .Models}}{{.}}{}, {{end}})
+{{- end}}
+
+	g.Execute()
+}
+`
+
+// sqlQueryTemplate is the go interfaces converted from the sql files, the sql is the comment of the method in the style of gorm/gen
+const sqlQueryTemplate = `// Code generated by cwgo ({{.Version}}). DO NOT EDIT.
+
+package ` + sqlQueryPkgName + `
+
+import "gorm.io/gen"
+{{range .Interfaces}}
+// {{.Name}} is the query methods of {{.File}}
+type {{.Name}} interface {
+{{- range .Methods}}
+	// {{.Name}}{{range .Doc}} {{.}}{{end}}
+	//
+{{- range .SQL}}
+	// {{.}}
+{{- end}}
+	{{.Name}}({{.ParamList}}) {{.Result}}
+{{- end}}
+}
+{{end}}`
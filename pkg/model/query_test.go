@@ -0,0 +1,283 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gen"
+)
+
+func writeQueryFile(t *testing.T, dir, name, content string) string {
+	file := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	return file
+}
+
+func TestParseQuerySQL(t *testing.T) {
+	type testCase struct {
+		name    string
+		content string
+		methods []*sqlQueryMethod
+		err     string
+	}
+	cases := []testCase{
+		{
+			name: "kinds",
+			content: `-- name: GetByName :one
+-- the user of the name
+SELECT * FROM @@table WHERE user_name = @name;
+
+-- name: List :many
+SELECT * FROM @@table;
+-- name: Rename :exec
+UPDATE @@table SET user_name = @name WHERE id = @id;
+-- name: Clear :execrows
+DELETE FROM @@table;
+`,
+			methods: []*sqlQueryMethod{
+				{Tables: []string{"users"}, Name: "GetByName", Doc: []string{"the user of the name"}, SQL: []string{"SELECT * FROM @@table WHERE user_name = @name"}, Args: []queryParam{{"name", "interface{}"}}, Result: "(*gen.T, error)"},
+				{Tables: []string{"users"}, Name: "List", SQL: []string{"SELECT * FROM @@table"}, Result: "([]*gen.T, error)"},
+				{Tables: []string{"users"}, Name: "Rename", SQL: []string{"UPDATE @@table SET user_name = @name WHERE id = @id"}, Args: []queryParam{{"name", "interface{}"}, {"id", "interface{}"}}, Result: "error"},
+				{Tables: []string{"users"}, Name: "Clear", SQL: []string{"DELETE FROM @@table"}, Result: "(gen.RowsAffected, error)"},
+			},
+		},
+		{
+			name: "dynamic sql",
+			content: `-- name: Search :many
+-- table: users, orders
+-- param: name string
+-- param: ids []int64
+SELECT * FROM @@table
+{{where}}
+  {{if name != ""}} user_name = @name {{end}}
+  {{if len(ids) > 0}} AND id IN @ids {{end}}
+{{end}}
+`,
+			methods: []*sqlQueryMethod{
+				{
+					Tables: []string{"users", "orders"},
+					Name:   "Search",
+					SQL:    []string{"SELECT * FROM @@table", "{{where}}", `{{if name != ""}} user_name = @name {{end}}`, "{{if len(ids) > 0}} AND id IN @ids {{end}}", "{{end}}"},
+					Args:   []queryParam{{"name", "string"}, {"ids", "[]int64"}},
+					Result: "([]*gen.T, error)",
+				},
+			},
+		},
+		{name: "unknown kind", content: "-- name: Get :first\nSELECT 1", err: "unknown kind ':first'"},
+		{name: "invalid header", content: "-- name: Get\nSELECT 1", err: "invalid header"},
+		{name: "unexported method", content: "-- name: get :one\nSELECT 1", err: "invalid method name 'get'"},
+		{name: "empty sql", content: "-- name: Get :one\n-- the doc\n", err: "the sql of method 'Get'"},
+		{name: "invalid param", content: "-- name: Get :one\n-- param: id\nSELECT 1", err: "invalid header '-- param: id'"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			methods, err := parseQuerySQL(writeQueryFile(t, t.TempDir(), "users.sql", c.content))
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.methods, methods)
+		})
+	}
+}
+
+func TestSQLQueryInterfaces(t *testing.T) {
+	methods := []*sqlQueryMethod{
+		{Tables: []string{"users"}, Name: "GetByName", Doc: []string{"the user of the name"}, SQL: []string{"SELECT * FROM @@table", "{{where}} user_name = @name {{end}}"}, Args: []queryParam{{"name", "string"}}, Result: "(*gen.T, error)"},
+		{Tables: []string{"users", "orders"}, Name: "Clear", SQL: []string{"DELETE FROM @@table"}, Result: "(gen.RowsAffected, error)"},
+		{Tables: []string{"users"}, Name: "List", SQL: []string{"SELECT * FROM @@table"}, Result: "([]*gen.T, error)"},
+	}
+	ifaces := sqlQueryInterfaces("user-query.sql", methods)
+	assert.Len(t, ifaces, 2)
+	assert.Equal(t, "UserQueryQuerier", ifaces[0].Name)
+	assert.Equal(t, []*sqlQueryMethod{methods[0], methods[2]}, ifaces[0].Methods)
+	assert.Equal(t, "UserQueryQuerier2", ifaces[1].Name)
+
+	file := filepath.Join(t.TempDir(), sqlQueryPkgName, sqlQueryFileName)
+	assert.NoError(t, writeSQLQueryFile(file, &sqlQueryFile{Version: "test", Interfaces: ifaces}))
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	// the sql is the comment of the method, which is parsed by gorm/gen
+	assert.Contains(t, string(content), `type UserQueryQuerier interface {
+	// GetByName the user of the name
+	//
+	// SELECT * FROM @@table
+	// {{where}} user_name = @name {{end}}
+	GetByName(name string) (*gen.T, error)
+	// List
+	//
+	// SELECT * FROM @@table
+	List() ([]*gen.T, error)
+}`)
+	assert.Contains(t, string(content), "Clear() (gen.RowsAffected, error)")
+}
+
+func TestParseQueryInterfaces(t *testing.T) {
+	tables := []*TableMeta{
+		{TableName: "users", StructName: "User"},
+		{TableName: "orders", StructName: "Order"},
+	}
+	file := writeQueryFile(t, t.TempDir(), "query.go", `package query
+
+import "gorm.io/gen"
+
+// UserQuerier is applied to the users
+type UserQuerier interface {
+	// SELECT * FROM @@table WHERE id = @id
+	GetByID(id int64) (gen.T, error)
+}
+
+// Pager is applied to the tables
+//
+// table: users, orders
+type Pager interface {
+	// SELECT * FROM @@table LIMIT @limit
+	Page(limit int) ([]gen.T, error)
+}
+
+type (
+	// Common is applied to all the tables
+	Common interface {
+		// DELETE FROM @@table
+		Clear() error
+	}
+	private interface{}
+)
+`)
+	ifaces, err := parseQueryInterfaces(file, tables)
+	assert.NoError(t, err)
+	assert.Equal(t, []*queryInterface{
+		{PkgName: "query", Name: "UserQuerier", Tables: []string{"users"}},
+		{PkgName: "query", Name: "Pager", Tables: []string{"users", "orders"}},
+		{PkgName: "query", Name: "Common"},
+	}, ifaces)
+}
+
+func TestRenderQueryGenerator(t *testing.T) {
+	tables := []*TableMeta{
+		{TableName: "users", StructName: "User", ModelPkgName: "model"},
+		{TableName: "orders", StructName: "Order", ModelPkgName: "model"},
+		{TableName: "profiles", StructName: "Profile", ModelPkgName: "model"},
+	}
+	ifaces := []*queryInterface{
+		{PkgPath: "example.com/app/biz/query", PkgName: "query", Name: "UserQuerier", Tables: []string{"users"}},
+		{PkgPath: "example.com/app/biz/query", PkgName: "query", Name: "Pager", Tables: []string{"users", "orders"}},
+		{PkgPath: "example.com/app/biz/query/cwgo_query_gen_1/sqlquery", PkgName: "sqlquery", Name: "OrdersQuerier", Tables: []string{"orders"}},
+		{PkgPath: "example.com/app/biz/query", PkgName: "query", Name: "Unused", Tables: []string{"accounts"}},
+	}
+	c := &config.ModelArgument{
+		OutPath:       "biz/dal/query",
+		OutFile:       "gen.go",
+		ModelPkgName:  "biz/dal/model",
+		WithUnitTest:  true,
+		FieldNullable: true,
+		FieldSignable: true,
+	}
+	g, err := newQueryGenerator(c, "example.com/app/biz/dal/model", tables, ifaces)
+	assert.NoError(t, err)
+
+	outPath, _ := filepath.Abs(c.OutPath)
+	modelPkgPath, _ := filepath.Abs(c.ModelPkgName)
+	assert.Equal(t, outPath, g.Config.OutPath)
+	assert.Equal(t, "gen.go", g.Config.OutFile)
+	assert.Equal(t, modelPkgPath, g.Config.ModelPkgPath)
+	assert.Equal(t, []string{"model.Profile"}, g.BasicModels)
+	assert.Equal(t, []*queryApply{
+		{Interface: "query0.UserQuerier", Models: []string{"model.User"}},
+		{Interface: "query0.Pager", Models: []string{"model.User", "model.Order"}},
+		{Interface: "sqlquery1.OrdersQuerier", Models: []string{"model.Order"}},
+	}, g.Applies)
+
+	code, err := renderQueryGenerator(g)
+	assert.NoError(t, err)
+	_, err = format.Source(code)
+	assert.NoError(t, err)
+	content := string(code)
+	assert.Contains(t, content, `query0 "example.com/app/biz/query"`)
+	assert.Contains(t, content, `sqlquery1 "example.com/app/biz/query/cwgo_query_gen_1/sqlquery"`)
+	assert.Contains(t, content, "g.ApplyBasic(model.Profile{})")
+	assert.Contains(t, content, "g.ApplyInterface(func(query0.Pager) {}, model.User{}, model.Order{})")
+	for _, want := range []string{"WithUnitTest: true,", "FieldNullable: true,", "FieldSignable: true,", "FieldWithIndexTag: false,", "Mode: 0,"} {
+		assert.Contains(t, strings.Join(strings.Fields(content), " "), want)
+	}
+}
+
+// TestGenQueries runs the query generator by "go run", so the output is in the package dir to import the models
+func TestGenQueries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip running the query generator in short mode")
+	}
+	dir, err := os.MkdirTemp(".", "_query_test_")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db := openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, user_name TEXT, age INTEGER)")
+	c := &config.ModelArgument{
+		Type:         string(consts.Sqlite),
+		OutPath:      filepath.Join(dir, "query"),
+		OutFile:      "gen.go",
+		ModelPkgName: "model",
+		QueryDir:     filepath.Join(dir, "custom"),
+	}
+	g := gen.NewGenerator(gen.Config{OutPath: c.OutPath, OutFile: c.OutFile, ModelPkgPath: c.ModelPkgName})
+	g.UseDB(db)
+	models, err := genModels(g, db, c, &modelConfig{})
+	assert.NoError(t, err)
+	g.ApplyBasic(models...)
+	g.Execute()
+	tables, err := tableMetas(c, db, models)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.MkdirAll(c.QueryDir, 0o755))
+	writeQueryFile(t, c.QueryDir, "querier.go", `package custom
+
+import "gorm.io/gen"
+
+type UserQuerier interface {
+	// SELECT * FROM @@table WHERE id = @id
+	GetByID(id int64) (gen.T, error)
+}
+`)
+	writeQueryFile(t, c.QueryDir, "users.sql", `-- name: Search :many
+-- param: name string
+SELECT * FROM @@table
+{{where}}
+  {{if name != ""}} user_name = @name {{end}}
+{{end}}
+`)
+	assert.NoError(t, genQueries(c, tables))
+
+	content, err := os.ReadFile(filepath.Join(c.OutPath, "users.gen.go"))
+	assert.NoError(t, err)
+	code := string(content)
+	assert.Contains(t, code, "func (u userDo) GetByID(id int64) (result model.User, err error)")
+	assert.Contains(t, code, "func (u userDo) Search(name string) (result []*model.User, err error)")
+	// the dynamic sql is built by gorm/gen
+	assert.Contains(t, code, "helper.JoinWhereBuilder")
+	entries, err := os.ReadDir(c.QueryDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
@@ -0,0 +1,297 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"github.com/jinzhu/inflection"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+// relationConfig is the relations part of the model config, the relationships are detected only when enabled, eg:
+//
+//	relations:
+//	  enable: true
+//	  naming: true
+//	  ignore:
+//	    - orders.user_id
+type relationConfig struct {
+	// Enable enables the relationship detection
+	Enable bool `yaml:"enable"`
+	// Naming detects the relationship by the column named <table>_id besides the foreign keys
+	Naming bool `yaml:"naming"`
+	// Ignore is the foreign keys in the form of table.column whose relationships are not generated
+	Ignore []string `yaml:"ignore"`
+}
+
+type foreignKey struct {
+	Table     string
+	Column    string
+	RefTable  string
+	RefColumn string
+}
+
+// relateFunc creates the relation field to the model of a table
+type relateFunc func(relationship field.RelationshipType, fieldName string, config *field.RelateConfig) gen.Field
+
+const (
+	mysqlForeignKeys = `SELECT TABLE_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+FROM information_schema.KEY_COLUMN_USAGE
+WHERE TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME IS NOT NULL`
+	postgresForeignKeys = `SELECT kcu.table_name, kcu.column_name, ccu.table_name, ccu.column_name
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+JOIN information_schema.constraint_column_usage ccu ON tc.constraint_name = ccu.constraint_name AND tc.table_schema = ccu.table_schema
+WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = current_schema()`
+	sqlServerForeignKeys = `SELECT tp.name, cp.name, tr.name, cr.name
+FROM sys.foreign_key_columns fkc
+JOIN sys.tables tp ON fkc.parent_object_id = tp.object_id
+JOIN sys.columns cp ON fkc.parent_object_id = cp.object_id AND fkc.parent_column_id = cp.column_id
+JOIN sys.tables tr ON fkc.referenced_object_id = tr.object_id
+JOIN sys.columns cr ON fkc.referenced_object_id = cr.object_id AND fkc.referenced_column_id = cr.column_id`
+)
+
+// foreignKeys queries the foreign keys of the tables, the sql files have no foreign key info
+func foreignKeys(db *gorm.DB, dbType string, tables []string) ([]foreignKey, error) {
+	var query string
+	switch consts.DataBaseType(dbType) {
+	case consts.MySQL:
+		query = mysqlForeignKeys
+	case consts.Postgres:
+		query = postgresForeignKeys
+	case consts.SQLServer:
+		query = sqlServerForeignKeys
+	case consts.Sqlite:
+		return sqliteForeignKeys(db, tables)
+	default:
+		return nil, nil
+	}
+
+	rows, err := db.Raw(query).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("query foreign keys fail: %w", err)
+	}
+	defer rows.Close()
+
+	var fks []foreignKey
+	for rows.Next() {
+		var fk foreignKey
+		if err = rows.Scan(&fk.Table, &fk.Column, &fk.RefTable, &fk.RefColumn); err != nil {
+			return nil, err
+		}
+		fks = append(fks, fk)
+	}
+	return fks, rows.Err()
+}
+
+func sqliteForeignKeys(db *gorm.DB, tables []string) ([]foreignKey, error) {
+	var fks []foreignKey
+	for _, table := range tables {
+		var list []struct {
+			Table string `gorm:"column:table"`
+			From  string `gorm:"column:from"`
+			To    string `gorm:"column:to"`
+		}
+		if err := db.Raw(fmt.Sprintf("PRAGMA foreign_key_list(`%s`)", table)).Scan(&list).Error; err != nil {
+			return nil, fmt.Errorf("query foreign keys of table '%s' fail: %w", table, err)
+		}
+		for _, fk := range list {
+			fks = append(fks, foreignKey{Table: table, Column: fk.From, RefTable: fk.Table, RefColumn: fk.To})
+		}
+	}
+	return fks, nil
+}
+
+// tableColumns returns the columns of the tables and the primary key of each table
+func tableColumns(db *gorm.DB, tables []string) (map[string][]gorm.ColumnType, map[string]string, error) {
+	columns := make(map[string][]gorm.ColumnType, len(tables))
+	primaryKeys := make(map[string]string, len(tables))
+	for _, table := range tables {
+		columnTypes, err := db.Migrator().ColumnTypes(table)
+		if err != nil {
+			return nil, nil, fmt.Errorf("migrator get columns of table '%s' fail: %w", table, err)
+		}
+		columns[table] = columnTypes
+		for _, ct := range columnTypes {
+			if pk, _ := ct.PrimaryKey(); pk {
+				primaryKeys[table] = ct.Name()
+				break
+			}
+		}
+	}
+	return columns, primaryKeys, nil
+}
+
+// namingForeignKeys detects the columns named <table>_id, eg: orders.user_id -> users.id
+func namingForeignKeys(columns map[string][]gorm.ColumnType, primaryKeys map[string]string, known []foreignKey) []foreignKey {
+	exists := make(map[string]bool, len(known))
+	for _, fk := range known {
+		exists[fk.Table+"."+fk.Column] = true
+	}
+
+	tables := make([]string, 0, len(columns))
+	for table := range columns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	var fks []foreignKey
+	for _, table := range tables {
+		for _, ct := range columns[table] {
+			name := ct.Name()
+			if !strings.HasSuffix(name, "_id") || exists[table+"."+name] {
+				continue
+			}
+			prefix := strings.TrimSuffix(name, "_id")
+			for _, refTable := range []string{prefix, prefix + "s", prefix + "es"} {
+				if pk, ok := primaryKeys[refTable]; ok && pk == "id" && refTable != table {
+					fks = append(fks, foreignKey{Table: table, Column: name, RefTable: refTable, RefColumn: pk})
+					break
+				}
+			}
+		}
+	}
+	return fks
+}
+
+// relationFields detects the relationships of the tables and returns the relation fields of each table.
+// The table with the foreign key belongs to the referenced table, and the referenced table has one
+// or has many of the table depending on whether the foreign key is unique.
+func relationFields(db *gorm.DB, dbType string, useDB bool, mc *modelConfig, tables []string, relates map[string]relateFunc) (map[string][]gen.Field, error) {
+	rc := mc.Relations
+	if rc == nil || !rc.Enable {
+		return nil, nil
+	}
+
+	columns, primaryKeys, err := tableColumns(db, tables)
+	if err != nil {
+		return nil, err
+	}
+	var fks []foreignKey
+	if useDB {
+		if fks, err = foreignKeys(db, dbType, tables); err != nil {
+			return nil, err
+		}
+	}
+	if rc.Naming {
+		fks = append(fks, namingForeignKeys(columns, primaryKeys, fks)...)
+	}
+
+	// the foreign key named after the referenced table gets the relation name without prefix, eg: orders.user_id
+	sort.SliceStable(fks, func(i, j int) bool {
+		mi, mj := isNamedAfter(fks[i]), isNamedAfter(fks[j])
+		if mi != mj {
+			return mi
+		}
+		if fks[i].Table != fks[j].Table {
+			return fks[i].Table < fks[j].Table
+		}
+		return fks[i].Column < fks[j].Column
+	})
+
+	ignore := make(map[string]bool, len(rc.Ignore))
+	for _, fk := range rc.Ignore {
+		ignore[fk] = true
+	}
+
+	fields := make(map[string][]gen.Field)
+	names := make(map[string]map[string]bool)
+	uniqueName := func(table, name, prefix string) string {
+		if names[table] == nil {
+			names[table] = make(map[string]bool)
+			for _, ct := range columns[table] {
+				names[table][mc.fieldName(db, table, ct.Name())] = true
+			}
+		}
+		if names[table][name] {
+			name = prefix + name
+		}
+		for i := 2; names[table][name]; i++ {
+			name = fmt.Sprintf("%s%d", strings.TrimRight(name, "0123456789"), i)
+		}
+		names[table][name] = true
+		return name
+	}
+
+	for _, fk := range fks {
+		childRelate, parentRelate := relates[fk.Table], relates[fk.RefTable]
+		if ignore[fk.Table+"."+fk.Column] || childRelate == nil || parentRelate == nil {
+			continue
+		}
+		refColumn := fk.RefColumn
+		if refColumn == "" {
+			refColumn = primaryKeys[fk.RefTable]
+		}
+		if refColumn == "" {
+			continue
+		}
+
+		fkField := mc.fieldName(db, fk.Table, fk.Column)
+		refField := mc.fieldName(db, fk.RefTable, refColumn)
+		tag := func() field.GormTag {
+			return field.GormTag{}.Set("foreignKey", fkField).Set("references", refField)
+		}
+		columnPrefix := db.NamingStrategy.SchemaName(strings.TrimSuffix(fk.Column, "_id"))
+
+		// eg: Order.User
+		belongsTo := mc.modelName(db, fk.RefTable)
+		if strings.HasSuffix(fk.Column, "_id") && columnPrefix != "" {
+			belongsTo = columnPrefix
+		}
+		belongsTo = uniqueName(fk.Table, belongsTo, "")
+		fields[fk.Table] = append(fields[fk.Table], parentRelate(field.BelongsTo, belongsTo, &field.RelateConfig{
+			RelatePointer: true,
+			GORMTag:       tag(),
+		}))
+
+		// eg: User.Orders
+		child := mc.modelName(db, fk.Table)
+		if isUniqueColumn(columns[fk.Table], fk.Column) {
+			fields[fk.RefTable] = append(fields[fk.RefTable], childRelate(field.HasOne, uniqueName(fk.RefTable, child, columnPrefix), &field.RelateConfig{
+				RelatePointer: true,
+				GORMTag:       tag(),
+			}))
+		} else {
+			fields[fk.RefTable] = append(fields[fk.RefTable], childRelate(field.HasMany, uniqueName(fk.RefTable, inflection.Plural(child), columnPrefix), &field.RelateConfig{
+				RelateSlice: true,
+				GORMTag:     tag(),
+			}))
+		}
+	}
+	return fields, nil
+}
+
+func isNamedAfter(fk foreignKey) bool {
+	prefix := strings.TrimSuffix(fk.Column, "_id")
+	return prefix != fk.Column && (fk.RefTable == prefix || inflection.Singular(fk.RefTable) == prefix)
+}
+
+func isUniqueColumn(columns []gorm.ColumnType, name string) bool {
+	for _, ct := range columns {
+		if ct.Name() == name {
+			unique, _ := ct.Unique()
+			return unique
+		}
+	}
+	return false
+}
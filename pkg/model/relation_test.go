@@ -0,0 +1,99 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gen"
+)
+
+var relationDDL = []string{
+	"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+	"CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id), reviewer_id INTEGER REFERENCES users(id))",
+	"CREATE TABLE profiles (id INTEGER PRIMARY KEY, user_id INTEGER UNIQUE REFERENCES users(id))",
+	"CREATE TABLE comments (id INTEGER PRIMARY KEY, order_id INTEGER)",
+}
+
+// genRelationModels generates the models of the relation tables and returns the model and query code of each table
+func genRelationModels(t *testing.T, mc *modelConfig) map[string]string {
+	db := openTestDB(t, relationDDL...)
+	out := filepath.Join(t.TempDir(), "query")
+	g := gen.NewGenerator(gen.Config{OutPath: out, ModelPkgPath: "model"})
+	g.UseDB(db)
+
+	tables := []string{"users", "orders", "profiles", "comments"}
+	models, err := genModels(g, db, &config.ModelArgument{Type: string(consts.Sqlite), Tables: tables}, mc)
+	assert.NoError(t, err)
+	g.ApplyBasic(models...)
+	g.Execute()
+
+	code := make(map[string]string, len(tables))
+	for _, table := range tables {
+		content, err := os.ReadFile(filepath.Join(filepath.Dir(out), "model", table+".gen.go"))
+		assert.NoError(t, err)
+		code[table] = strings.Join(strings.Fields(string(content)), " ")
+		content, err = os.ReadFile(filepath.Join(out, table+".gen.go"))
+		assert.NoError(t, err)
+		code["query/"+table] = strings.Join(strings.Fields(string(content)), " ")
+	}
+	return code
+}
+
+func TestRelationsDisabledByDefault(t *testing.T) {
+	for _, mc := range []*modelConfig{{}, {Relations: &relationConfig{Naming: true}}} {
+		code := genRelationModels(t, mc)
+		assert.NotContains(t, code["users"], "Orders")
+		assert.NotContains(t, code["orders"], "*User")
+	}
+}
+
+func TestRelationsFromForeignKeys(t *testing.T) {
+	code := genRelationModels(t, &modelConfig{Relations: &relationConfig{Enable: true}})
+
+	// the foreign key named after the referenced table gets the relation name without prefix
+	assert.Contains(t, code["orders"], "User *User `gorm:\"foreignKey:UserID;references:ID\" json:\"user\"`")
+	assert.Contains(t, code["orders"], "Reviewer *User `gorm:\"foreignKey:ReviewerID;references:ID\" json:\"reviewer\"`")
+	assert.Contains(t, code["users"], "Orders []Order `gorm:\"foreignKey:UserID;references:ID\" json:\"orders\"`")
+	assert.Contains(t, code["users"], "ReviewerOrders []Order `gorm:\"foreignKey:ReviewerID;references:ID\" json:\"reviewer_orders\"`")
+	// the unique foreign key is has one
+	assert.Contains(t, code["users"], "Profile *Profile `gorm:\"foreignKey:UserID;references:ID\" json:\"profile\"`")
+	assert.Contains(t, code["profiles"], "User *User")
+	assert.NotContains(t, code["comments"], "Order *Order")
+	// the preload-capable relation fields of the query have no nested relations
+	assert.Contains(t, code["query/orders"], `RelationField: field.NewRelation("User", "model.User"), }`)
+	assert.Contains(t, code["query/users"], `RelationField: field.NewRelation("Orders", "model.Order"), }`)
+}
+
+func TestRelationsNamingAndIgnore(t *testing.T) {
+	code := genRelationModels(t, &modelConfig{Relations: &relationConfig{
+		Enable: true,
+		Naming: true,
+		Ignore: []string{"orders.reviewer_id"},
+	}})
+
+	assert.Contains(t, code["comments"], "Order *Order `gorm:\"foreignKey:OrderID;references:ID\" json:\"order\"`")
+	assert.Contains(t, code["orders"], "Comments []Comment")
+	assert.NotContains(t, code["orders"], "Reviewer *User")
+	assert.NotContains(t, code["users"], "ReviewerOrders")
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/hu-1996/cwgo/config"
+	"github.com/hu-1996/cwgo/pkg/common/utils"
+	"github.com/hu-1996/cwgo/pkg/consts"
+	"gorm.io/gorm"
+)
+
+const templateSuffix = ".tpl"
+
+// TableMeta is the data of the user templates, which are rendered once per table
+type TableMeta struct {
+	TableName       string
+	TableComment    string
+	StructName      string // model struct name, eg: User
+	QueryStructName string // internal query struct name of gorm/gen, eg: user
+	ModelPkgName    string // package name of the generated models
+	QueryPkgName    string // package name of the generated query code
+	Columns         []*ColumnMeta
+	PrimaryKeys     []*ColumnMeta
+}
+
+// ColumnMeta is the column of the table
+type ColumnMeta struct {
+	ColumnName    string
+	FieldName     string // field name in the model struct
+	GoType        string // field type in the model struct
+	DataType      string // database type, eg: varchar(64)
+	Comment       string
+	Default       string
+	Tags          string // field tags in the model struct
+	PrimaryKey    bool
+	AutoIncrement bool
+	Nullable      bool
+	Unique        bool
+}
+
+// tableMetas converts the models generated by gorm/gen to the template data
+func tableMetas(c *config.ModelArgument, db *gorm.DB, models []interface{}) ([]*TableMeta, error) {
+	modelPkg := c.ModelPkgName
+	if strings.TrimSpace(modelPkg) == "" {
+		modelPkg = "model"
+	}
+	outPath := c.OutPath
+	if outPath == "" {
+		outPath = consts.DefaultDbOutDir
+	}
+
+	metas := make([]*TableMeta, 0, len(models))
+	for _, m := range models {
+		// the model meta of gorm/gen is an internal type, so it's read by reflection
+		v := reflect.ValueOf(m)
+		if v.IsNil() {
+			// the table is excluded by the table name strategy
+			continue
+		}
+		v = v.Elem()
+		meta := &TableMeta{
+			TableName:       v.FieldByName("TableName").String(),
+			TableComment:    v.FieldByName("TableComment").String(),
+			StructName:      v.FieldByName("ModelStructName").String(),
+			QueryStructName: v.FieldByName("QueryStructName").String(),
+			ModelPkgName:    filepath.Base(modelPkg),
+			QueryPkgName:    filepath.Base(outPath),
+		}
+
+		columnTypes, err := db.Migrator().ColumnTypes(meta.TableName)
+		if err != nil {
+			return nil, fmt.Errorf("migrator get columns of table '%s' fail: %w", meta.TableName, err)
+		}
+		columns := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, ct := range columnTypes {
+			columns[ct.Name()] = ct
+		}
+
+		fields := v.FieldByName("Fields")
+		for i := 0; i < fields.Len(); i++ {
+			f := fields.Index(i)
+			column := &ColumnMeta{
+				ColumnName: f.Elem().FieldByName("ColumnName").String(),
+				FieldName:  f.Elem().FieldByName("Name").String(),
+				GoType:     f.Elem().FieldByName("Type").String(),
+				Comment:    f.Elem().FieldByName("ColumnComment").String(),
+				Tags:       f.MethodByName("Tags").Call(nil)[0].String(),
+			}
+			if ct, ok := columns[column.ColumnName]; ok {
+				fillColumnMeta(column, ct)
+			}
+			if column.ColumnName == "" {
+				// relation fields are not columns
+				continue
+			}
+			meta.Columns = append(meta.Columns, column)
+			if column.PrimaryKey {
+				meta.PrimaryKeys = append(meta.PrimaryKeys, column)
+			}
+		}
+		metas = append(metas, meta)
+	}
+	return metas, nil
+}
+
+func fillColumnMeta(column *ColumnMeta, ct gorm.ColumnType) {
+	column.DataType = ct.DatabaseTypeName()
+	if t, ok := ct.ColumnType(); ok {
+		column.DataType = t
+	}
+	column.PrimaryKey, _ = ct.PrimaryKey()
+	column.AutoIncrement, _ = ct.AutoIncrement()
+	column.Nullable, _ = ct.Nullable()
+	column.Unique, _ = ct.Unique()
+	if column.Comment == "" {
+		column.Comment, _ = ct.Comment()
+	}
+	column.Default, _ = ct.DefaultValue()
+}
+
+// templateDir returns the local directory of the user templates, git templates are cloned first
+func templateDir(c *config.ModelArgument) (string, error) {
+	if !strings.HasSuffix(c.Template, consts.SuffixGit) {
+		return c.Template, nil
+	}
+
+	dir := path.Join(os.TempDir(), consts.DB)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := utils.GitClone(c.Template, dir); err != nil {
+		return "", err
+	}
+	gitPath, err := utils.GitPath(c.Template)
+	if err != nil {
+		return "", err
+	}
+	gitPath = path.Join(dir, gitPath)
+	if err = utils.GitCheckout(c.Branch, gitPath); err != nil {
+		return "", err
+	}
+	return gitPath, nil
+}
+
+func templateFuncMap() template.FuncMap {
+	funcMap := sprig.TxtFuncMap()
+	funcMap["ToCamel"] = toCamel
+	return funcMap
+}
+
+// renderTemplates renders every *.tpl file in the template directory once per table.
+// The relative path of the template is the output path, which is also rendered with the table,
+// eg: "repository/{{.TableName}}.go.tpl", and the path without actions is prefixed with the table name.
+func renderTemplates(c *config.ModelArgument, tables []*TableMeta) error {
+	dir, err := templateDir(c)
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == consts.SuffixGit {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, templateSuffix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = strings.TrimSuffix(filepath.ToSlash(rel), templateSuffix)
+		if !strings.Contains(rel, "{{") {
+			rel = path.Join(path.Dir(rel), "{{.TableName}}_"+path.Base(rel))
+		}
+		nameTpl, err := template.New(rel).Funcs(templateFuncMap()).Parse(rel)
+		if err != nil {
+			return fmt.Errorf("parse template name '%s' failed: %w", rel, err)
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		tpl, err := template.New(rel).Funcs(templateFuncMap()).Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("parse template '%s' failed: %w", p, err)
+		}
+
+		for _, table := range tables {
+			if err = renderTable(c.TemplateOutDir, nameTpl, tpl, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func renderTable(outDir string, nameTpl, tpl *template.Template, table *TableMeta) error {
+	var name bytes.Buffer
+	if err := nameTpl.Execute(&name, table); err != nil {
+		return fmt.Errorf("render template name '%s' of table '%s' failed: %w", nameTpl.Name(), table.TableName, err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, table); err != nil {
+		return fmt.Errorf("render template '%s' of table '%s' failed: %w", tpl.Name(), table.TableName, err)
+	}
+
+	content := buf.Bytes()
+	outPath := filepath.Join(outDir, filepath.FromSlash(name.String()))
+	if strings.HasSuffix(outPath, ".go") {
+		code, err := format.Source(content)
+		if err != nil {
+			return fmt.Errorf("format '%s' failed: %w", outPath, err)
+		}
+		content = code
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, content, 0o644)
+}
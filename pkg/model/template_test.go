@@ -0,0 +1,129 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hu-1996/cwgo/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB opens a sqlite database in the temp dir and creates the tables by the ddl
+func openTestDB(t *testing.T, ddl ...string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	assert.NoError(t, err)
+	for _, stmt := range ddl {
+		assert.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+var testTables = []*TableMeta{
+	{
+		TableName:       "users",
+		StructName:      "User",
+		QueryStructName: "user",
+		ModelPkgName:    "model",
+		QueryPkgName:    "query",
+		Columns: []*ColumnMeta{
+			{ColumnName: "id", FieldName: "ID", GoType: "int64", DataType: "bigint", PrimaryKey: true, AutoIncrement: true, Tags: `gorm:"column:id;primaryKey" json:"id"`},
+			{ColumnName: "user_name", FieldName: "UserName", GoType: "string", DataType: "varchar(64)", Comment: "the name\nof the user", Tags: `gorm:"column:user_name" json:"user_name"`},
+			{ColumnName: "age", FieldName: "Age", GoType: "*int32", DataType: "int", Nullable: true, Tags: `gorm:"column:age" json:"age"`},
+		},
+	},
+}
+
+func init() {
+	testTables[0].PrimaryKeys = testTables[0].Columns[:1]
+}
+
+func TestRenderTemplates(t *testing.T) {
+	tplDir, outDir := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		"repository/{{.TableName}}_repo.go.tpl": `package repository
+
+// {{.StructName}}Repo is the repository of {{.TableName}}
+type {{.StructName}}Repo interface {
+	Get({{range .PrimaryKeys}}{{ToCamel .ColumnName | untitle}} {{.GoType}}{{end}}) (*{{.ModelPkgName}}.{{.StructName}}, error)
+}
+`,
+		"columns.txt.tpl": `{{range .Columns}}{{.ColumnName}} {{.DataType}}{{if .Nullable}} null{{end}}
+{{end}}`,
+		"ignored.txt": "not a template",
+	}
+	for name, content := range files {
+		p := filepath.Join(tplDir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+
+	err := renderTemplates(&config.ModelArgument{Template: tplDir, TemplateOutDir: outDir}, testTables)
+	assert.NoError(t, err)
+
+	repo, err := os.ReadFile(filepath.Join(outDir, "repository", "users_repo.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(repo), "Get(id int64) (*model.User, error)")
+
+	// the path without actions is prefixed with the table name
+	columns, err := os.ReadFile(filepath.Join(outDir, "users_columns.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "id bigint\nuser_name varchar(64)\nage int null\n", string(columns))
+
+	_, err = os.Stat(filepath.Join(outDir, "users_ignored.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRenderTemplatesInvalidGo(t *testing.T) {
+	tplDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tplDir, "bad.go.tpl"), []byte("package {{.TableName}\n"), 0o644))
+	err := renderTemplates(&config.ModelArgument{Template: tplDir, TemplateOutDir: t.TempDir()}, testTables)
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tplDir, "bad.go.tpl"), []byte("package {{.TableName}}\nfunc (\n"), 0o644))
+	err = renderTemplates(&config.ModelArgument{Template: tplDir, TemplateOutDir: t.TempDir()}, testTables)
+	assert.ErrorContains(t, err, "format")
+}
+
+func TestTableMetas(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, user_name VARCHAR(64) NOT NULL DEFAULT '', age INT)")
+	g := gen.NewGenerator(gen.Config{OutPath: filepath.Join(t.TempDir(), "query")})
+	g.UseDB(db)
+	models := []interface{}{g.GenerateModelAs("users", "User")}
+
+	tables, err := tableMetas(&config.ModelArgument{ModelPkgName: "biz/model", OutPath: "biz/query"}, db, models)
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	table := tables[0]
+	assert.Equal(t, "users", table.TableName)
+	assert.Equal(t, "User", table.StructName)
+	assert.Equal(t, "model", table.ModelPkgName)
+	assert.Equal(t, "query", table.QueryPkgName)
+	assert.Len(t, table.Columns, 3)
+	assert.Len(t, table.PrimaryKeys, 1)
+	assert.Equal(t, "id", table.PrimaryKeys[0].ColumnName)
+	assert.Equal(t, "UserName", table.Columns[1].FieldName)
+	assert.Equal(t, "string", table.Columns[1].GoType)
+	assert.False(t, table.Columns[1].Nullable)
+	assert.True(t, table.Columns[2].Nullable)
+}
@@ -118,6 +118,13 @@ func convertHzArgument(sa *config.ServerArgument, hzArgument *config.HzArgument)
 	pbCamelJSONTag := f.Bool("pb_camel_json_tag", false, "")
 	snakeTag := f.Bool("snake_tag", false, "")
 	handlerByMethod := f.Bool("handler_by_method", false, "")
+	modelBackend := f.String("model_backend", "", "")
+	openAPI := f.Bool("openapi", false, "")
+	swaggerUI := f.Bool("swagger_ui", false, "")
+	wktGoType := f.Bool("wkt_go_type", false, "map the protobuf well-known types to the idiomatic go types in the models, "+
+		"the fields of them are dropped from the protobuf wire format of the models and only encoded by json, form and query, "+
+		"so they are rejected in the requests which may be bound from the protobuf body")
+	mock := f.Bool("mock", false, "")
 
 	err = f.Parse(utils.StringSliceSpilt(sa.SliceParam.Pass))
 	if err != nil {
@@ -138,5 +145,16 @@ func convertHzArgument(sa *config.ServerArgument, hzArgument *config.HzArgument)
 	hzArgument.ProtobufCamelJSONTag = *pbCamelJSONTag
 	hzArgument.SnakeName = *snakeTag
 	hzArgument.HandlerByMethod = *handlerByMethod
+	hzArgument.ModelBackend = *modelBackend
+	hzArgument.OpenAPI = *openAPI || *swaggerUI
+	hzArgument.SwaggerUI = *swaggerUI
+	hzArgument.WellKnownGoType = *wktGoType
+	hzArgument.Mock = *mock
+	if hzArgument.OpenAPI && !strings.EqualFold(hzArgument.IdlType, consts.Proto) {
+		return fmt.Errorf("the openapi document is only supported for the protobuf idl for now")
+	}
+	if hzArgument.Mock && !strings.EqualFold(hzArgument.IdlType, consts.Proto) {
+		return fmt.Errorf("the mock server is only supported for the protobuf idl for now")
+	}
 	return nil
 }